@@ -0,0 +1,189 @@
+package healthz
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+var errWrongAdaptive = errors.New("incorrect adaptive periods")
+
+// adaptive - bounds of the adaptive check period, disabled if zero.
+type adaptive struct {
+	min time.Duration
+	max time.Duration
+}
+
+// targetPace - planned schedule of the target, see updatePaces.
+type targetPace struct {
+	status   Status        // status of the last check
+	period   time.Duration // current period of the checks
+	next     time.Time     // next planned check
+	failures int           // failed checks in a row, counted by WithBackoff
+}
+
+type targetPaces struct {
+	mu    sync.Mutex
+	paces map[string]*targetPace // keyed by "scope/dest"
+}
+
+// WithAdaptiveSchedule - every target gets own check period: it starts at minPeriod, doubles after every
+// check with the same healthy status up to maxPeriod and falls back to minPeriod when the status changes
+// or isn't healthy, so stable targets are probed rarely while flapping and recovering ones quickly.
+// Check cycles run every minPeriod instead of the check period, targets not due keep their last result.
+func WithAdaptiveSchedule(minPeriod, maxPeriod time.Duration) Option {
+	return func(i *Inspector) error {
+		if minPeriod <= 0 || maxPeriod < minPeriod {
+			return errWrongAdaptive
+		}
+
+		i.adaptive = adaptive{min: minPeriod, max: maxPeriod}
+
+		return nil
+	}
+}
+
+// cyclePeriod - period of the check cycles: the shortest of the check period (or the shortest adaptive
+// period if enabled) and own periods of the targets.
+func (i *Inspector) cyclePeriod() time.Duration {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	period := i.checkPeriod
+	if i.adaptive.min > 0 {
+		period = i.adaptive.min
+	}
+
+	for _, target := range i.targets {
+		if target.Period > 0 {
+			period = min(period, target.Period)
+		}
+	}
+
+	return period
+}
+
+// carriedResults - last results of the targets not due at the cycle started at (see also StatusDisabled),
+// keyed by index of the target. All treats every target as due, disabled ones aside.
+func (i *Inspector) carriedResults(targets []HealthCheckTarget, at time.Time, all bool) map[int]TargetResult {
+	prev := make(map[string]TargetResult)
+
+	for _, tr := range i.get().targets {
+		prev[targetKey(tr.Scope, tr.Dest)] = tr
+	}
+
+	cyclePeriod := i.cyclePeriod()
+
+	i.paces.mu.Lock()
+	defer i.paces.mu.Unlock()
+
+	carried := make(map[int]TargetResult)
+
+	for idx, target := range targets {
+		scope, dest := target.Service.Scope(), target.Service.Dest()
+		key := targetKey(scope, dest)
+
+		tr, ok := prev[key]
+
+		switch {
+		case i.manualStatus(scope, dest) == StatusDisabled:
+			if !ok { // never checked
+				tr = TargetResult{Scope: scope, Dest: dest, Groups: target.Groups}
+			}
+		case all || !ok:
+			continue
+		default:
+			p, paced := i.paces.paces[key]
+			if !paced || !at.Add(cyclePeriod/2).Before(p.next) { // tolerates jitter of the cycles
+				continue
+			}
+		}
+
+		tr.carried = true
+		carried[idx] = tr
+	}
+
+	return carried
+}
+
+// updatePaces - plans next checks of the targets checked by the cycle started at: by own periods of the targets,
+// adaptive ones or the check period if cycles run more often (see cyclePeriod), backed off if failing
+// (see WithBackoff). Targets checked every cycle aren't planned.
+func (i *Inspector) updatePaces(targets []HealthCheckTarget, results []TargetResult, at time.Time) {
+	checkPeriod, cyclePeriod := i.period(), i.cyclePeriod()
+
+	i.paces.mu.Lock()
+	defer i.paces.mu.Unlock()
+
+	if i.paces.paces == nil {
+		i.paces.paces = make(map[string]*targetPace)
+	}
+
+	alive := make(map[string]bool, len(targets))
+
+	for n, target := range targets {
+		tr := results[n]
+		failing := i.backoffMax > 0 && tr.Err != nil
+
+		period := target.Period
+		if period == 0 && i.adaptive.min == 0 {
+			if cyclePeriod == checkPeriod && !failing {
+				continue // checked every cycle
+			}
+
+			period = checkPeriod
+		}
+
+		key := targetKey(target.Service.Scope(), target.Service.Dest())
+		alive[key] = true
+
+		if tr.carried {
+			continue
+		}
+
+		p, ok := i.paces.paces[key]
+		if !ok {
+			p = &targetPace{period: i.adaptive.min}
+			i.paces.paces[key] = p
+		}
+
+		switch {
+		case period > 0:
+			p.period = period
+		case !ok:
+		case tr.Status() != p.status || tr.Status() != StatusHealthy:
+			p.period = i.adaptive.min
+		default:
+			p.period = min(2*p.period, i.adaptive.max)
+		}
+
+		if failing {
+			p.failures++
+			p.period = i.backoffPeriod(p.period, p.failures)
+		} else {
+			p.failures = 0
+		}
+
+		p.status = tr.Status()
+		p.next = at.Add(p.period)
+	}
+
+	for key := range i.paces.paces {
+		if !alive[key] {
+			delete(i.paces.paces, key)
+		}
+	}
+}
+
+// paceOf - planned schedule of the target, false if it isn't planned (yet).
+func (i *Inspector) paceOf(scope, dest string) (targetPace, bool) {
+	i.paces.mu.Lock()
+	defer i.paces.mu.Unlock()
+
+	p, ok := i.paces.paces[targetKey(scope, dest)]
+	if !ok {
+		return targetPace{}, false
+	}
+
+	return *p, true
+}
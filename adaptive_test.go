@@ -0,0 +1,154 @@
+package healthz
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithAdaptiveSchedule(t *testing.T) {
+	tests := []struct {
+		name     string
+		min, max time.Duration
+		wantErr  bool
+	}{
+		{name: "test.1 ok", min: time.Second, max: time.Minute},
+		{name: "test.2 equal", min: time.Second, max: time.Second},
+		{name: "test.3 zero min", max: time.Minute, wantErr: true},
+		{name: "test.4 max below min", min: time.Minute, max: time.Second, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inspector := New()
+			err := WithAdaptiveSchedule(tt.min, tt.max)(inspector)
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, errWrongAdaptive)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.min, inspector.cyclePeriod())
+		})
+	}
+}
+
+func TestAdaptiveSchedule_periods(t *testing.T) {
+	var calls atomic.Int32
+
+	svc := &mockService{scope: "db", dest: "pg", callBack: func() { calls.Add(1) }}
+
+	inspector := New(HealthCheckTarget{Service: svc, Groups: GroupReady})
+	assert.NoError(t, WithCheckPeriod(time.Hour)(inspector))
+	assert.NoError(t, WithAdaptiveSchedule(time.Second, 4*time.Second)(inspector))
+
+	// makes the target due as if its period passed
+	due := func() {
+		inspector.paces.mu.Lock()
+		defer inspector.paces.mu.Unlock()
+
+		inspector.paces.paces[targetKey("db", "pg")].next = time.Now()
+	}
+
+	period := func() time.Duration {
+		p, ok := inspector.paceOf("db", "pg")
+		assert.True(t, ok)
+
+		return p.period
+	}
+
+	inspector.check(context.Background())
+	assert.Equal(t, int32(1), calls.Load())
+	assert.Equal(t, time.Second, period())
+
+	inspector.check(context.Background())
+	assert.Equal(t, int32(1), calls.Load(), "not due, the last result is kept")
+	assert.NoError(t, inspector.CheckGroup(GroupReady, true))
+
+	for _, want := range []time.Duration{2 * time.Second, 4 * time.Second, 4 * time.Second} {
+		due()
+		inspector.check(context.Background())
+		assert.Equal(t, want, period(), "stable target is checked less frequently")
+	}
+
+	assert.Equal(t, int32(4), calls.Load())
+
+	svc.healthErr = errors.New("down")
+
+	due()
+	inspector.check(context.Background())
+	assert.Equal(t, time.Second, period(), "changed status falls back to the min period")
+	assert.Error(t, inspector.CheckGroup(GroupReady, true))
+
+	due()
+	inspector.check(context.Background())
+	assert.Equal(t, time.Second, period(), "unhealthy target is checked frequently")
+
+	schedule := inspector.Schedule()
+	assert.Len(t, schedule, 1)
+	assert.Equal(t, time.Second, schedule[0].Period)
+}
+
+func TestHealthCheckTarget_Period(t *testing.T) {
+	var fastCalls, slowCalls atomic.Int32
+
+	fast := &mockService{scope: "cache", dest: "local", callBack: func() { fastCalls.Add(1) }}
+	slow := &mockService{scope: "s3", dest: "bucket", callBack: func() { slowCalls.Add(1) }}
+
+	inspector := New(
+		HealthCheckTarget{Service: fast, Groups: GroupReady, Period: time.Second},
+		HealthCheckTarget{Service: slow, Groups: GroupReady},
+	)
+	assert.NoError(t, WithCheckPeriod(time.Minute)(inspector))
+	assert.Equal(t, time.Second, inspector.cyclePeriod(), "cycles run by the shortest period")
+
+	due := func(scope, dest string) {
+		inspector.paces.mu.Lock()
+		defer inspector.paces.mu.Unlock()
+
+		inspector.paces.paces[targetKey(scope, dest)].next = time.Now()
+	}
+
+	inspector.check(context.Background())
+	assert.Equal(t, int32(1), fastCalls.Load())
+	assert.Equal(t, int32(1), slowCalls.Load())
+
+	for range 3 {
+		due("cache", "local")
+		inspector.check(context.Background())
+	}
+
+	assert.Equal(t, int32(4), fastCalls.Load())
+	assert.Equal(t, int32(1), slowCalls.Load(), "checked by the check period")
+
+	due("s3", "bucket")
+	inspector.check(context.Background())
+	assert.Equal(t, int32(4), fastCalls.Load())
+	assert.Equal(t, int32(2), slowCalls.Load())
+
+	_, err := inspector.CheckNow(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int32(5), fastCalls.Load())
+	assert.Equal(t, int32(3), slowCalls.Load(), "CheckNow checks all targets")
+
+	assert.NoError(t, inspector.Start(context.Background()))
+
+	defer inspector.Stop(context.Background())
+
+	for _, ts := range inspector.Schedule() {
+		switch ts.Dest {
+		case "local":
+			assert.Equal(t, ScheduleOwnPeriod, ts.Reason)
+			assert.Equal(t, time.Second, ts.Period)
+		case "bucket":
+			assert.Equal(t, SchedulePeriodic, ts.Reason)
+			assert.Equal(t, time.Minute, ts.Period)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package healthz
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+var errWrongAuditGrace = errors.New("cancellation audit grace must be positive")
+
+// CancelAudit - check which returned late (or hasn't returned yet) after cancellation of its context,
+// such checks block graceful shutdown.
+type CancelAudit struct {
+	Scope       string
+	Dest        string
+	CancelledAt time.Time
+	Lag         time.Duration // from cancellation to return, lower bound if Running
+	Running     bool          // the check hasn't returned yet
+}
+
+func (ca CancelAudit) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Scope       string    `json:"scope"`
+		Dest        string    `json:"dest"`
+		CancelledAt time.Time `json:"cancelledAt"`
+		Lag         float64   `json:"lagSeconds"`
+		Running     bool      `json:"running"`
+	}{
+		Scope:       ca.Scope,
+		Dest:        ca.Dest,
+		CancelledAt: ca.CancelledAt,
+		Lag:         ca.Lag.Seconds(),
+		Running:     ca.Running,
+	})
+}
+
+// WithCancellationAudit - debug mode measuring how long checks take to return after their context
+// is cancelled (cycle timeout, shutdown), checks exceeding grace are logged (slog) and reported by
+// Inspector.CancelAudit and Snapshot. Combine with WithCycleTimeout to get cancellations every cycle.
+func WithCancellationAudit(grace time.Duration) Option {
+	return func(i *Inspector) error {
+		if grace <= 0 {
+			return errWrongAuditGrace
+		}
+
+		i.auditGrace = grace
+
+		return nil
+	}
+}
+
+// CancelAudit - current offenders of the cancellation audit, sorted by scope and dest.
+func (i *Inspector) CancelAudit() []CancelAudit {
+	if i.parent != nil {
+		var list []CancelAudit
+
+		for _, ca := range i.parent.CancelAudit() {
+			if ca.Scope == i.scope {
+				list = append(list, ca)
+			}
+		}
+
+		return list
+	}
+
+	i.auditMu.Lock()
+	defer i.auditMu.Unlock()
+
+	list := make([]CancelAudit, 0, len(i.audits))
+
+	for _, ca := range i.audits {
+		list = append(list, ca)
+	}
+
+	sort.Slice(list, func(a, b int) bool {
+		return targetKey(list[a].Scope, list[a].Dest) < targetKey(list[b].Scope, list[b].Dest)
+	})
+
+	return list
+}
+
+// auditCancel - starts measuring of the check in the audit mode, returned func is called when
+// the check returns.
+func (i *Inspector) auditCancel(ctx context.Context, target HealthCheckTarget) (returned func()) {
+	grace := i.auditGrace
+	if grace == 0 {
+		return func() {}
+	}
+
+	var (
+		mu          sync.Mutex
+		done        bool
+		cancelledAt time.Time
+		timer       *time.Timer
+	)
+
+	scope, dest := target.Service.Scope(), target.Service.Dest()
+
+	stop := context.AfterFunc(ctx, func() {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if done {
+			return
+		}
+
+		cancelledAt = time.Now()
+		timer = time.AfterFunc(grace, func() {
+			mu.Lock()
+			defer mu.Unlock()
+
+			if !done {
+				i.recordCancelAudit(CancelAudit{Scope: scope, Dest: dest, CancelledAt: cancelledAt, Lag: grace, Running: true})
+			}
+		})
+	})
+
+	return func() {
+		stop()
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		done = true
+
+		if cancelledAt.IsZero() {
+			return
+		}
+
+		timer.Stop()
+
+		lag := time.Since(cancelledAt)
+		if lag <= grace {
+			i.clearCancelAudit(scope, dest)
+
+			return
+		}
+
+		i.recordCancelAudit(CancelAudit{Scope: scope, Dest: dest, CancelledAt: cancelledAt, Lag: lag})
+	}
+}
+
+func (i *Inspector) recordCancelAudit(ca CancelAudit) {
+	i.log().Warn("healthz: check doesn't honor context cancellation",
+		"scope", ca.Scope, "dest", ca.Dest, "lag", ca.Lag, "running", ca.Running)
+
+	i.auditMu.Lock()
+	defer i.auditMu.Unlock()
+
+	if i.audits == nil {
+		i.audits = make(map[string]CancelAudit)
+	}
+
+	i.audits[targetKey(ca.Scope, ca.Dest)] = ca
+}
+
+func (i *Inspector) clearCancelAudit(scope, dest string) {
+	i.auditMu.Lock()
+	defer i.auditMu.Unlock()
+
+	delete(i.audits, targetKey(scope, dest))
+}
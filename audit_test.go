@@ -0,0 +1,59 @@
+package healthz
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// lagService - returns lag after cancellation of the context.
+type lagService struct {
+	dest string
+	lag  time.Duration
+}
+
+func (s *lagService) Health(ctx context.Context) error {
+	<-ctx.Done()
+	time.Sleep(s.lag)
+
+	return ctx.Err()
+}
+func (s *lagService) Scope() string { return "audit" }
+func (s *lagService) Dest() string  { return s.dest }
+
+func TestCancellationAudit(t *testing.T) {
+	inspector := New(
+		HealthCheckTarget{Service: &lagService{dest: "polite"}, Groups: GroupLive},
+		HealthCheckTarget{Service: &lagService{dest: "stubborn", lag: 150 * time.Millisecond}, Groups: GroupLive},
+	)
+	assert.NoError(t, WithCycleTimeout(10*time.Millisecond)(inspector))
+	assert.NoError(t, WithCancellationAudit(50*time.Millisecond)(inspector))
+
+	inspector.check(context.Background())
+
+	// still running after grace
+	assert.Eventually(t, func() bool {
+		list := inspector.CancelAudit()
+
+		return len(list) == 1 && list[0].Running
+	}, testTimeout, time.Millisecond)
+
+	// returned late
+	assert.Eventually(t, func() bool {
+		list := inspector.CancelAudit()
+
+		return len(list) == 1 && !list[0].Running
+	}, testTimeout, time.Millisecond)
+
+	offender := inspector.Snapshot().CancelOffenders[0]
+	assert.Equal(t, "stubborn", offender.Dest)
+	assert.GreaterOrEqual(t, offender.Lag, 150*time.Millisecond)
+
+	child, err := inspector.Child("other")
+	assert.NoError(t, err)
+	assert.Empty(t, child.CancelAudit())
+
+	assert.ErrorIs(t, WithCancellationAudit(0)(New()), errWrongAuditGrace)
+}
@@ -0,0 +1,35 @@
+package healthz
+
+import (
+	"errors"
+	"time"
+)
+
+var errWrongBackoff = errors.New("incorrect backoff period")
+
+// WithBackoff - persistently failing targets are checked less frequently instead of hammering a dead
+// dependency every cycle: after the second failure in a row the period of the target doubles with every
+// failure up to maxPeriod, the first success restores it. Applies to own periods of the targets
+// and WithAdaptiveSchedule too, CheckNow checks failing targets regardless.
+func WithBackoff(maxPeriod time.Duration) Option {
+	return func(i *Inspector) error {
+		if maxPeriod <= 0 {
+			return errWrongBackoff
+		}
+
+		i.backoffMax = maxPeriod
+
+		return nil
+	}
+}
+
+// backoffPeriod - period of the target after failures in a row, base is its usual period.
+func (i *Inspector) backoffPeriod(base time.Duration, failures int) time.Duration {
+	period := base
+
+	for n := 1; n < failures && period < i.backoffMax; n++ {
+		period *= 2
+	}
+
+	return max(min(period, i.backoffMax), base)
+}
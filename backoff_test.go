@@ -0,0 +1,86 @@
+package healthz
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInspector_backoffPeriod(t *testing.T) {
+	tests := []struct {
+		name     string
+		max      time.Duration
+		base     time.Duration
+		failures int
+		want     time.Duration
+	}{
+		{name: "test.1 first failure", max: time.Minute, base: time.Second, failures: 1, want: time.Second},
+		{name: "test.2 doubled", max: time.Minute, base: time.Second, failures: 3, want: 4 * time.Second},
+		{name: "test.3 capped", max: time.Minute, base: time.Second, failures: 100, want: time.Minute},
+		{name: "test.4 base above max", max: time.Second, base: time.Minute, failures: 5, want: time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inspector := New()
+			assert.NoError(t, WithBackoff(tt.max)(inspector))
+			assert.Equal(t, tt.want, inspector.backoffPeriod(tt.base, tt.failures))
+		})
+	}
+
+	assert.ErrorIs(t, WithBackoff(0)(New()), errWrongBackoff)
+}
+
+func TestWithBackoff(t *testing.T) {
+	var calls atomic.Int32
+
+	svc := &mockService{scope: "db", dest: "pg", healthErr: errors.New("down"), callBack: func() { calls.Add(1) }}
+
+	inspector := New(HealthCheckTarget{Service: svc, Groups: GroupReady})
+	assert.NoError(t, WithCheckPeriod(time.Second)(inspector))
+	assert.NoError(t, WithBackoff(4*time.Second)(inspector))
+
+	// makes the target due as if its period passed
+	due := func() {
+		inspector.paces.mu.Lock()
+		defer inspector.paces.mu.Unlock()
+
+		if p, ok := inspector.paces.paces[targetKey("db", "pg")]; ok {
+			p.next = time.Now()
+		}
+	}
+
+	period := func() time.Duration {
+		p, ok := inspector.paceOf("db", "pg")
+		assert.True(t, ok)
+
+		return p.period
+	}
+
+	for _, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 4 * time.Second} {
+		due()
+		inspector.check(context.Background())
+		assert.Equal(t, want, period(), "period doubles up to the max")
+	}
+
+	assert.Equal(t, int32(4), calls.Load())
+
+	inspector.check(context.Background())
+	assert.Equal(t, int32(4), calls.Load(), "failing target isn't checked every cycle")
+
+	svc.healthErr = nil
+
+	_, err := inspector.CheckNow(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int32(5), calls.Load())
+
+	_, ok := inspector.paceOf("db", "pg")
+	assert.False(t, ok, "the first success restores the check period")
+
+	inspector.check(context.Background())
+	assert.Equal(t, int32(6), calls.Load())
+}
@@ -0,0 +1,128 @@
+package healthz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	defTierTimeout   = time.Minute
+	defTierInterval  = time.Second
+	bootstrapMinWait = 10 * time.Millisecond
+)
+
+var (
+	errWrongBootstrap = errors.New("incorrect bootstrap timeouts")
+	errTierUnhealthy  = errors.New("dependency tier isn't healthy")
+)
+
+// bootstrap - settings of Bootstrap, defaults if zero.
+type bootstrap struct {
+	tierTimeout time.Duration
+	interval    time.Duration
+}
+
+// WithBootstrapTimeouts - how long Bootstrap waits for every tier to become healthy (1m by default)
+// and how often failing targets of the tier are checked again (1s by default).
+func WithBootstrapTimeouts(tierTimeout, interval time.Duration) Option {
+	return func(i *Inspector) error {
+		if tierTimeout <= 0 || interval < bootstrapMinWait {
+			return errWrongBootstrap
+		}
+
+		i.bootstrap = bootstrap{tierTimeout: tierTimeout, interval: interval}
+
+		return nil
+	}
+}
+
+// Bootstrap - waits for the dependency tiers to become healthy in sequence, e.g. database before cache
+// before consumers, so the application starts its components only when their dependencies are up.
+// Failing targets of the tier are checked again till the tier timeout (see WithBootstrapTimeouts),
+// then the error lists them. Targets are checked like by the cycles (timeouts, retries, concurrency
+// limits), but the results aren't published, typically it runs before Start.
+func (i *Inspector) Bootstrap(ctx context.Context, order ...[]HealthCheckTarget) error {
+	for _, tier := range order {
+		for _, target := range tier {
+			if target.Service == nil {
+				return errMissService
+			}
+		}
+	}
+
+	for n, tier := range order {
+		if err := i.bootstrapTier(ctx, n+1, tier); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// bootstrapTier - waits for the targets of the tier (numbered from 1) to become healthy.
+func (i *Inspector) bootstrapTier(ctx context.Context, tier int, targets []HealthCheckTarget) error {
+	i.mu.RLock()
+	checkTimeout := i.checkTimeout
+	i.mu.RUnlock()
+
+	tierTimeout, interval := i.bootstrap.tierTimeout, i.bootstrap.interval
+	if tierTimeout == 0 {
+		tierTimeout, interval = defTierTimeout, defTierInterval
+	}
+
+	start := time.Now()
+
+	ctx, cancel := context.WithTimeout(ctx, tierTimeout)
+	defer cancel()
+
+	pending := targets
+
+	for attempt := 1; ; attempt++ {
+		errs := make([]error, len(pending))
+
+		var wg sync.WaitGroup
+
+		for n, target := range pending {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				errs[n] = i.checkTarget(ctx, 0, target, checkTimeout).err
+			}()
+		}
+
+		wg.Wait()
+
+		var (
+			failing []HealthCheckTarget
+			failed  []error
+		)
+
+		for n, err := range errs {
+			if err != nil {
+				target := pending[n]
+				failing = append(failing, target)
+				failed = append(failed, fmt.Errorf("%s: %w", targetKey(target.Service.Scope(), target.Service.Dest()), i.redact(err)))
+			}
+		}
+
+		if len(failing) == 0 {
+			i.logAt(i.logLevels.Lifecycle, "healthz: dependency tier is healthy",
+				"tier", tier, "targets", len(targets), "attempts", attempt, "duration", time.Since(start))
+
+			return nil
+		}
+
+		pending = failing
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w: tier %d: %w", errTierUnhealthy, tier, errors.Join(failed...))
+		case <-time.After(interval):
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package healthz
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithBootstrapTimeouts(t *testing.T) {
+	tests := []struct {
+		name     string
+		timeout  time.Duration
+		interval time.Duration
+		wantErr  bool
+	}{
+		{name: "test.1 ok", timeout: time.Minute, interval: time.Second},
+		{name: "test.2 zero timeout", interval: time.Second, wantErr: true},
+		{name: "test.3 short interval", timeout: time.Minute, interval: time.Millisecond, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := WithBootstrapTimeouts(tt.timeout, tt.interval)(New())
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, errWrongBootstrap)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestInspector_Bootstrap(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		order []string
+	)
+
+	record := func(dest string) func() {
+		return func() {
+			mu.Lock()
+			defer mu.Unlock()
+
+			order = append(order, dest)
+		}
+	}
+
+	var dbCalls atomic.Int32
+
+	db := &mockService{scope: "db", dest: "pg"}
+	db.callBack = func() {
+		record("pg")()
+
+		if dbCalls.Add(1) == 3 {
+			db.healthErr = nil
+		}
+	}
+	db.healthErr = errors.New("starting")
+
+	cache := &mockService{scope: "cache", dest: "redis", callBack: record("redis")}
+	consumer := &mockService{scope: "kafka", dest: "orders", callBack: record("orders")}
+
+	inspector := New()
+	assert.NoError(t, WithBootstrapTimeouts(time.Second, 10*time.Millisecond)(inspector))
+
+	err := inspector.Bootstrap(context.Background(),
+		[]HealthCheckTarget{{Service: db, Groups: GroupReady}},
+		[]HealthCheckTarget{{Service: cache, Groups: GroupReady}},
+		[]HealthCheckTarget{{Service: consumer, Groups: GroupReady}},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"pg", "pg", "pg", "redis", "orders"}, order, "tiers in sequence")
+
+	assert.ErrorIs(t, inspector.Bootstrap(context.Background(), []HealthCheckTarget{{}}), errMissService)
+
+	down := &mockService{scope: "s3", dest: "bucket", healthErr: errors.New("access denied")}
+
+	assert.NoError(t, WithBootstrapTimeouts(50*time.Millisecond, 10*time.Millisecond)(inspector))
+
+	err = inspector.Bootstrap(context.Background(),
+		[]HealthCheckTarget{{Service: cache, Groups: GroupReady}},
+		[]HealthCheckTarget{{Service: down, Groups: GroupReady}, {Service: consumer, Groups: GroupReady}},
+	)
+	assert.ErrorIs(t, err, errTierUnhealthy)
+	assert.ErrorContains(t, err, "tier 2: s3/bucket: access denied")
+	assert.NotContains(t, err.Error(), "kafka/orders")
+}
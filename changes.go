@@ -0,0 +1,139 @@
+package healthz
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+const (
+	maxChanges    = 256              // size of the change log
+	recentChanges = 15 * time.Minute // changes shown in verbose output
+)
+
+// Change - transition of the target between healthy and unhealthy.
+type Change struct {
+	Time    time.Time
+	Scope   string
+	Dest    string
+	Healthy bool // state after the change
+	Err     error
+}
+
+func (c Change) MarshalJSON() ([]byte, error) {
+	view := struct {
+		Time    time.Time `json:"time"`
+		Scope   string    `json:"scope"`
+		Dest    string    `json:"dest"`
+		Healthy bool      `json:"healthy"`
+		Error   string    `json:"error,omitempty"`
+	}{
+		Time:    c.Time,
+		Scope:   c.Scope,
+		Dest:    c.Dest,
+		Healthy: c.Healthy,
+	}
+
+	if c.Err != nil {
+		view.Error = c.Err.Error()
+	}
+
+	return json.Marshal(view)
+}
+
+// UnmarshalJSON - restores the change (e.g. of WebhookEvent), the error keeps only its message.
+func (c *Change) UnmarshalJSON(data []byte) error {
+	var view struct {
+		Time    time.Time `json:"time"`
+		Scope   string    `json:"scope"`
+		Dest    string    `json:"dest"`
+		Healthy bool      `json:"healthy"`
+		Error   string    `json:"error"`
+	}
+
+	if err := json.Unmarshal(data, &view); err != nil {
+		return err
+	}
+
+	*c = Change{Time: view.Time, Scope: view.Scope, Dest: view.Dest, Healthy: view.Healthy}
+
+	if view.Error != "" {
+		c.Err = errors.New(view.Error)
+	}
+
+	return nil
+}
+
+// Changes - transitions recorded after since, oldest first.
+func (i *Inspector) Changes(since time.Time) []Change {
+	if i.parent != nil {
+		return i.childChanges(since)
+	}
+
+	i.changesMu.Lock()
+	defer i.changesMu.Unlock()
+
+	var list []Change
+
+	for _, c := range i.changes {
+		if c.Time.After(since) {
+			list = append(list, c)
+		}
+	}
+
+	return list
+}
+
+// publish - stores the new result and records its difference with the previous one.
+func (i *Inspector) publish(result *healthResult) []Change {
+	i.changesMu.Lock()
+	defer i.changesMu.Unlock()
+
+	return i.storeLocked(i.get(), result)
+}
+
+// storeLocked - stores the result replacing prev, changesMu must be held. Returns the recorded changes.
+// Every stored result gets the next sequence number.
+func (i *Inspector) storeLocked(prev, result *healthResult) []Change {
+	result.seq, result.publishedAt = prev.seq+1, time.Now()
+	i.store(result)
+
+	if !prev.checked {
+		return nil
+	}
+
+	changes := diffResults(prev.targets, result.targets, time.Now())
+	i.changes = append(i.changes, changes...)
+
+	if over := len(i.changes) - maxChanges; over > 0 {
+		i.changes = append(i.changes[:0:0], i.changes[over:]...)
+	}
+
+	return changes
+}
+
+// diffResults - targets whose health differs between prev and next.
+func diffResults(prev, next []TargetResult, at time.Time) []Change {
+	was := make(map[string]bool, len(prev))
+
+	for _, tr := range prev {
+		was[targetKey(tr.Scope, tr.Dest)] = tr.Healthy()
+	}
+
+	var list []Change
+
+	for _, tr := range next {
+		healthy, ok := was[targetKey(tr.Scope, tr.Dest)]
+		if !ok || healthy == tr.Healthy() {
+			continue
+		}
+
+		list = append(list, Change{Time: at, Scope: tr.Scope, Dest: tr.Dest, Healthy: tr.Healthy(), Err: tr.Err})
+	}
+
+	return list
+}
+
+func targetKey(scope, dest string) string {
+	return scope + "/" + dest
+}
@@ -0,0 +1,81 @@
+package healthz
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_diffResults(t *testing.T) {
+	errFail := errors.New("fail")
+	at := time.Now()
+
+	prev := []TargetResult{
+		{Scope: "db", Dest: "pg-1"},
+		{Scope: "db", Dest: "pg-2", Err: errFail},
+		{Scope: "db", Dest: "pg-3"},
+	}
+	next := []TargetResult{
+		{Scope: "db", Dest: "pg-1", Err: errFail},
+		{Scope: "db", Dest: "pg-2"},
+		{Scope: "db", Dest: "pg-3"},
+		{Scope: "db", Dest: "pg-4", Err: errFail}, // new target isn't a change
+	}
+
+	assert.Equal(t, []Change{
+		{Time: at, Scope: "db", Dest: "pg-1", Healthy: false, Err: errFail},
+		{Time: at, Scope: "db", Dest: "pg-2", Healthy: true},
+	}, diffResults(prev, next, at))
+}
+
+func TestInspectorChanges(t *testing.T) {
+	svc := &mockService{scope: "db", dest: "pg-1"}
+	inspector := New(HealthCheckTarget{Service: svc, Groups: GroupReady})
+	assert.NoError(t, WithResponseFormat(FormatKubeVerbose)(inspector))
+
+	start := time.Now()
+
+	inspector.check(context.Background())
+	assert.Empty(t, inspector.Changes(start), "first check isn't a change")
+
+	svc.healthErr = errors.New("fail")
+	inspector.check(context.Background())
+
+	svc.healthErr = nil
+	inspector.check(context.Background())
+
+	changes := inspector.Changes(start)
+	assert.Len(t, changes, 2)
+	assert.False(t, changes[0].Healthy)
+	assert.True(t, changes[1].Healthy)
+
+	assert.Len(t, inspector.Changes(changes[0].Time), 1)
+
+	w := httptest.NewRecorder()
+	inspector.HealthHandler(GroupReady, true, nil)(w, httptest.NewRequest(http.MethodGet, "/healthz/ready", nil))
+	assert.True(t, strings.Contains(w.Body.String(), "changed:\n"))
+	assert.Equal(t, 2, strings.Count(w.Body.String(), " db/pg-1 "))
+}
+
+func TestInspectorChanges_bounded(t *testing.T) {
+	svc := &mockService{}
+	inspector := New(HealthCheckTarget{Service: svc, Groups: GroupLive})
+	inspector.check(context.Background())
+
+	for n := range maxChanges + 10 {
+		svc.healthErr = nil
+		if n%2 == 0 {
+			svc.healthErr = errors.New("fail")
+		}
+
+		inspector.check(context.Background())
+	}
+
+	assert.Len(t, inspector.Changes(time.Time{}), maxChanges)
+}
@@ -0,0 +1,24 @@
+package healthz
+
+import "context"
+
+// checkFunc - checkable of the inline check, see CheckFunc.
+type checkFunc struct {
+	scope string
+	dest  string
+	fn    func(ctx context.Context) error
+}
+
+// CheckFunc - checkable calling fn, for simple inline checks without own type:
+//
+//	healthz.HealthCheckTarget{
+//		Service: healthz.CheckFunc("cache", "redis", func(ctx context.Context) error { return rdb.Ping(ctx).Err() }),
+//		Groups:  healthz.GroupReady,
+//	}
+func CheckFunc(scope, dest string, fn func(ctx context.Context) error) HealthCheckable {
+	return checkFunc{scope: scope, dest: dest, fn: fn}
+}
+
+func (cf checkFunc) Health(ctx context.Context) error { return cf.fn(ctx) }
+func (cf checkFunc) Scope() string                    { return cf.scope }
+func (cf checkFunc) Dest() string                     { return cf.dest }
@@ -0,0 +1,30 @@
+package healthz
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckFunc(t *testing.T) {
+	fail := errors.New("fail")
+
+	var calls int
+
+	svc := CheckFunc("cache", "redis", func(context.Context) error {
+		calls++
+
+		return fail
+	})
+
+	assert.Equal(t, "cache", svc.Scope())
+	assert.Equal(t, "redis", svc.Dest())
+
+	inspector := New(HealthCheckTarget{Service: svc, Groups: GroupReady})
+	inspector.check(context.Background())
+
+	assert.Equal(t, 1, calls)
+	assert.ErrorIs(t, inspector.CheckGroup(GroupReady, true), fail)
+}
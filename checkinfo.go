@@ -0,0 +1,29 @@
+package healthz
+
+import (
+	"context"
+	"time"
+)
+
+type checkInfoKey struct{}
+
+// CheckInfo - metadata of the running check, available in the context passed to Health.
+type CheckInfo struct {
+	Cycle    uint64     // number of the check cycle, starts from 1
+	Attempt  int        // attempt of the check within the cycle, starts from 1
+	Group    ProbeGroup // groups the result is evaluated for
+	Deadline time.Time  // zero if the check has no deadline
+}
+
+// CheckInfoFromContext - returns metadata of the check, ok is false outside of checks.
+func CheckInfoFromContext(ctx context.Context) (CheckInfo, bool) {
+	info, ok := ctx.Value(checkInfoKey{}).(CheckInfo)
+
+	return info, ok
+}
+
+func withCheckInfo(ctx context.Context, info CheckInfo) context.Context {
+	info.Deadline, _ = ctx.Deadline()
+
+	return context.WithValue(ctx, checkInfoKey{}, info)
+}
@@ -0,0 +1,44 @@
+package healthz
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type infoService struct {
+	mockService
+	infos []CheckInfo
+}
+
+func (s *infoService) Health(ctx context.Context) error {
+	info, ok := CheckInfoFromContext(ctx)
+	if ok {
+		s.infos = append(s.infos, info)
+	}
+
+	return nil
+}
+
+func TestCheckInfoFromContext(t *testing.T) {
+	_, ok := CheckInfoFromContext(context.Background())
+	assert.False(t, ok)
+
+	svc := &infoService{}
+	inspector := New(HealthCheckTarget{Service: svc, Groups: GroupLive | GroupReady})
+	assert.NoError(t, WithCycleTimeout(time.Minute)(inspector))
+
+	inspector.check(context.Background())
+	inspector.check(context.Background())
+
+	assert.Len(t, svc.infos, 2)
+
+	for n, info := range svc.infos {
+		assert.Equal(t, uint64(n+1), info.Cycle)
+		assert.Equal(t, 1, info.Attempt)
+		assert.Equal(t, GroupLive|GroupReady, info.Group)
+		assert.WithinDuration(t, time.Now().Add(time.Minute), info.Deadline, time.Second)
+	}
+}
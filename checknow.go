@@ -0,0 +1,69 @@
+package healthz
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+var errCheckNowStopping = errors.New("inspector is shutting down")
+
+// QueryRefresh - query param of the handlers triggering an immediate check, see WithRefreshQuery.
+const QueryRefresh = "refresh"
+
+// WithRefreshQuery - HealthHandler and StatusHandler run an immediate check cycle (see CheckNow)
+// before responding to requests with ?refresh=true, e.g. for deploy pipelines verifying dependencies
+// right after a rollout. Disabled by default: every such request costs a full check cycle.
+func WithRefreshQuery() Option {
+	return func(i *Inspector) error {
+		i.refreshQuery = true
+
+		return nil
+	}
+}
+
+// CheckNow - runs an immediate check cycle of all targets (adaptive schedules aside, disabled targets
+// keep their last result), publishes and returns the fresh result. Concurrent calls share one cycle,
+// periodic cycles wait for it. Child checks the targets of its parent and returns its scope only.
+// The cycle isn't canceled with ctx, which bounds the wait only.
+func (i *Inspector) CheckNow(ctx context.Context) (Snapshot, error) {
+	if i.parent != nil {
+		if _, err := i.parent.CheckNow(ctx); err != nil {
+			return Snapshot{}, err
+		}
+
+		return i.Snapshot(), nil
+	}
+
+	if i.shuttingDown.Load() {
+		return Snapshot{}, errCheckNowStopping
+	}
+
+	ch := i.nowFlight.DoChan("check", func() (any, error) {
+		i.checkCycle(context.WithoutCancel(ctx), true) // shared by the callers, the caller mustn't cancel it
+
+		return nil, nil
+	})
+
+	select {
+	case <-ctx.Done():
+		return Snapshot{}, ctx.Err()
+	case <-ch:
+		return i.Snapshot(), nil
+	}
+}
+
+// refreshRequested - runs CheckNow if the request asks for it and WithRefreshQuery is set,
+// failures leave the cached result.
+func (i *Inspector) refreshRequested(r *http.Request) {
+	root := i
+	if i.parent != nil {
+		root = i.parent
+	}
+
+	if !root.refreshQuery || r.URL.Query().Get(QueryRefresh) != "true" {
+		return
+	}
+
+	_, _ = i.CheckNow(r.Context())
+}
@@ -0,0 +1,123 @@
+package healthz
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInspector_CheckNow(t *testing.T) {
+	var calls atomic.Int32
+
+	svc := &mockService{scope: "db", dest: "pg", callBack: func() {
+		calls.Add(1)
+		time.Sleep(10 * time.Millisecond)
+	}}
+
+	inspector := New(
+		HealthCheckTarget{Service: svc, Groups: GroupReady},
+		HealthCheckTarget{Service: &mockService{scope: "cache", dest: "redis"}, Groups: GroupReady},
+	)
+	assert.NoError(t, WithAdaptiveSchedule(time.Hour, time.Hour)(inspector))
+
+	inspector.check(context.Background())
+	assert.Equal(t, int32(1), calls.Load())
+
+	svc.healthErr = errors.New("down")
+
+	snapshot, err := inspector.CheckNow(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), calls.Load(), "targets not due by the adaptive schedule are checked too")
+	assert.Equal(t, uint64(2), snapshot.Seq)
+
+	tr, ok := snapshot.Target("db", "pg")
+	assert.True(t, ok)
+	assert.Equal(t, StatusUnhealthy, tr.Status())
+
+	var wg sync.WaitGroup
+
+	for range 10 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			_, err := inspector.CheckNow(context.Background())
+			assert.NoError(t, err)
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Less(t, calls.Load(), int32(12), "concurrent calls share cycles")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = inspector.CheckNow(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	child, err := inspector.Child("db")
+	assert.NoError(t, err)
+
+	snapshot, err = child.CheckNow(context.Background())
+	assert.NoError(t, err)
+
+	if assert.Len(t, snapshot.Targets, 1) {
+		assert.Equal(t, "pg", snapshot.Targets[0].Dest)
+	}
+
+	inspector.shuttingDown.Store(true)
+
+	_, err = inspector.CheckNow(context.Background())
+	assert.ErrorIs(t, err, errCheckNowStopping)
+}
+
+func TestWithRefreshQuery(t *testing.T) {
+	tests := []struct {
+		name      string
+		refresh   bool
+		target    string
+		wantCalls int32
+	}{
+		{name: "test.1 refresh", refresh: true, target: "/healthz/ready?refresh=true", wantCalls: 2},
+		{name: "test.2 no query", refresh: true, target: "/healthz/ready", wantCalls: 1},
+		{name: "test.3 not enabled", target: "/healthz/ready?refresh=true", wantCalls: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+
+			inspector := New(HealthCheckTarget{
+				Service: &mockService{scope: "db", dest: "pg", callBack: func() { calls.Add(1) }},
+				Groups:  GroupReady,
+			})
+
+			if tt.refresh {
+				assert.NoError(t, WithRefreshQuery()(inspector))
+			}
+
+			inspector.check(context.Background())
+
+			w := httptest.NewRecorder()
+			inspector.HealthHandler(GroupReady, true, nil)(w, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.wantCalls, calls.Load())
+
+			w = httptest.NewRecorder()
+			inspector.StatusHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, 2*tt.wantCalls-1, calls.Load())
+		})
+	}
+}
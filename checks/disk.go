@@ -0,0 +1,70 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/art-frela/healthz"
+)
+
+// ScopeDisk - scope of NewDiskSpace checkers created with empty scope.
+const ScopeDisk = "disk"
+
+var errLowDiskSpace = errors.New("low disk space")
+
+// DiskSpace - checks free space of the filesystem holding the path (spool files, local caches).
+type DiskSpace struct {
+	scope          string
+	path           string
+	minFreeBytes   uint64
+	minFreePercent float64
+}
+
+var _ healthz.DetailedChecker = (*DiskSpace)(nil)
+
+// NewDiskSpace - checker of the filesystem holding the path failing when free space is below minFreeBytes
+// or minFreePercent (0-100) of its size, zero disables the threshold; scope is ScopeDisk if empty.
+// Space available to unprivileged users is counted as free. Usage is reported by the details
+// of the result: "total_bytes", "free_bytes", "used_percent". Supported on linux, darwin, freebsd, dragonfly.
+func NewDiskSpace(scope, path string, minFreeBytes uint64, minFreePercent float64) *DiskSpace {
+	if scope == "" {
+		scope = ScopeDisk
+	}
+
+	return &DiskSpace{scope: scope, path: path, minFreeBytes: minFreeBytes, minFreePercent: minFreePercent}
+}
+
+func (d *DiskSpace) Scope() string { return d.scope }
+func (d *DiskSpace) Dest() string  { return d.path }
+
+func (d *DiskSpace) Health(ctx context.Context) error {
+	_, err := d.HealthDetails(ctx)
+
+	return err
+}
+
+func (d *DiskSpace) HealthDetails(_ context.Context) (map[string]any, error) {
+	total, free, err := diskUsage(d.path)
+	if err != nil {
+		return nil, fmt.Errorf("disk usage of %s: %w", d.path, err)
+	}
+
+	freePercent := 100.0
+	if total > 0 {
+		freePercent = float64(free) / float64(total) * 100
+	}
+
+	details := map[string]any{
+		"total_bytes":  total,
+		"free_bytes":   free,
+		"used_percent": 100 - freePercent,
+	}
+
+	if free < d.minFreeBytes || freePercent < d.minFreePercent {
+		return details, fmt.Errorf("%w: %d bytes (%.1f%%) free, minimum %d bytes (%.1f%%)",
+			errLowDiskSpace, free, freePercent, d.minFreeBytes, d.minFreePercent)
+	}
+
+	return details, nil
+}
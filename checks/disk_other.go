@@ -0,0 +1,9 @@
+//go:build !(linux || darwin || freebsd || dragonfly)
+
+package checks
+
+import "errors"
+
+func diskUsage(string) (total, free uint64, err error) {
+	return 0, 0, errors.ErrUnsupported
+}
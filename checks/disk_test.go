@@ -0,0 +1,60 @@
+//go:build linux || darwin || freebsd || dragonfly
+
+package checks
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiskSpace(t *testing.T) {
+	path := t.TempDir()
+
+	c := NewDiskSpace("", path, 0, 0)
+	assert.Equal(t, ScopeDisk, c.Scope())
+	assert.Equal(t, path, c.Dest())
+	assert.Equal(t, "spool", NewDiskSpace("spool", path, 0, 0).Scope())
+
+	tests := []struct {
+		name           string
+		path           string
+		minFreeBytes   uint64
+		minFreePercent float64
+		wantErr        error
+		wantDetails    bool
+	}{
+		{name: "test.1 no thresholds", path: path, wantDetails: true},
+		{name: "test.2 enough bytes", path: path, minFreeBytes: 1, wantDetails: true},
+		{name: "test.3 low bytes", path: path, minFreeBytes: math.MaxUint64, wantErr: errLowDiskSpace, wantDetails: true},
+		{name: "test.4 low percent", path: path, minFreePercent: 100.1, wantErr: errLowDiskSpace, wantDetails: true},
+		{name: "test.5 missing path", path: path + "/missing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			details, err := NewDiskSpace("", tt.path, tt.minFreeBytes, tt.minFreePercent).HealthDetails(context.Background())
+
+			switch {
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+			case !tt.wantDetails:
+				assert.Error(t, err)
+			default:
+				assert.NoError(t, err)
+			}
+
+			if !tt.wantDetails {
+				assert.Nil(t, details)
+
+				return
+			}
+
+			assert.Greater(t, details["total_bytes"], uint64(0))
+			assert.LessOrEqual(t, details["free_bytes"], details["total_bytes"])
+			assert.InDelta(t, 50, details["used_percent"], 50)
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/art-frela/healthz"
+)
+
+var errMissEndpoints = errors.New("missing egress endpoints")
+
+// EgressEndpoint - well-known external endpoint proving outbound connectivity.
+type EgressEndpoint struct {
+	Host string // resolved by DNS and dialed by TCP, e.g. "one.one.one.one"
+	Port string // "443" if empty
+	URL  string // optional, requested by HTTP GET, any response passes
+}
+
+// DefaultEgressEndpoints - endpoints of NewEgress without own ones.
+var DefaultEgressEndpoints = []EgressEndpoint{
+	{Host: "one.one.one.one"},
+	{Host: "dns.google"},
+	{Host: "www.cloudflare.com"},
+}
+
+// Egress - checks outbound internet connectivity: healthy if any endpoint passes
+// DNS resolution, TCP dial and the optional HTTP request.
+type Egress struct {
+	scope     string
+	endpoints []EgressEndpoint
+	client    *http.Client
+	dialer    net.Dialer
+}
+
+var _ healthz.HealthCheckable = (*Egress)(nil)
+
+// NewEgress - checker of the endpoints (DefaultEgressEndpoints if none),
+// client could be nil (http.DefaultClient).
+func NewEgress(scope string, client *http.Client, endpoints ...EgressEndpoint) *Egress {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	if len(endpoints) == 0 {
+		endpoints = DefaultEgressEndpoints
+	}
+
+	return &Egress{
+		scope:     scope,
+		endpoints: endpoints,
+		client:    client,
+	}
+}
+
+func (e *Egress) Scope() string { return e.scope }
+func (e *Egress) Dest() string  { return "egress" }
+
+func (e *Egress) Health(ctx context.Context) error {
+	if len(e.endpoints) == 0 {
+		return errMissEndpoints
+	}
+
+	errs := make([]error, 0, len(e.endpoints))
+
+	for _, endpoint := range e.endpoints {
+		err := e.check(ctx, endpoint)
+		if err == nil {
+			return nil
+		}
+
+		errs = append(errs, fmt.Errorf("%s: %w", endpoint.Host, err))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (e *Egress) check(ctx context.Context, endpoint EgressEndpoint) error {
+	if _, err := net.DefaultResolver.LookupHost(ctx, endpoint.Host); err != nil {
+		return fmt.Errorf("dns: %w", err)
+	}
+
+	port := endpoint.Port
+	if port == "" {
+		port = "443"
+	}
+
+	conn, err := e.dialer.DialContext(ctx, "tcp", net.JoinHostPort(endpoint.Host, port))
+	if err != nil {
+		return fmt.Errorf("tcp: %w", err)
+	}
+
+	conn.Close()
+
+	if endpoint.URL == "" {
+		return nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.URL, nil)
+	if err != nil {
+		return fmt.Errorf("http: %w", err)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("http: %w", err)
+	}
+
+	resp.Body.Close()
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package checks
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEgress(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	_, port, _ := net.SplitHostPort(srv.Listener.Addr().String())
+
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	_, closedPort, _ := net.SplitHostPort(closed.Addr().String())
+	closed.Close()
+
+	reachable := EgressEndpoint{Host: "127.0.0.1", Port: port, URL: srv.URL}
+	unreachable := EgressEndpoint{Host: "127.0.0.1", Port: closedPort}
+
+	tests := []struct {
+		name      string
+		endpoints []EgressEndpoint
+		wantErr   bool
+	}{
+		{name: "test.1 reachable", endpoints: []EgressEndpoint{reachable}},
+		{name: "test.2 unreachable", endpoints: []EgressEndpoint{unreachable}, wantErr: true},
+		{name: "test.3 any reachable", endpoints: []EgressEndpoint{unreachable, reachable}},
+		{name: "test.4 http fails", endpoints: []EgressEndpoint{{Host: "127.0.0.1", Port: port, URL: "http://127.0.0.1:" + closedPort}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewEgress("network", nil, tt.endpoints...).Health(context.Background())
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	assert.Equal(t, DefaultEgressEndpoints, NewEgress("network", nil).endpoints)
+}
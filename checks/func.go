@@ -0,0 +1,29 @@
+package checks
+
+import (
+	"context"
+
+	"github.com/art-frela/healthz"
+)
+
+// Func - checks by the closure, for dependencies without own checker.
+type Func struct {
+	scope string
+	dest  string
+	fn    func(ctx context.Context) error
+}
+
+var _ healthz.HealthCheckable = (*Func)(nil)
+
+// NewFunc - checker calling fn, dest is normalized (see healthz.NormalizeDest),
+// so connection strings could be passed as is.
+func NewFunc(scope, dest string, fn func(ctx context.Context) error) *Func {
+	return &Func{scope: scope, dest: healthz.NormalizeDest(dest), fn: fn}
+}
+
+func (f *Func) Scope() string { return f.scope }
+func (f *Func) Dest() string  { return f.dest }
+
+func (f *Func) Health(ctx context.Context) error {
+	return f.fn(ctx)
+}
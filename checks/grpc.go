@@ -0,0 +1,78 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/art-frela/healthz"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// ScopeGRPC - scope of NewGRPC checkers created with empty scope.
+const ScopeGRPC = "grpc"
+
+var errNotServing = errors.New("grpc service isn't serving")
+
+// GRPC - checks the remote service by grpc.health.v1.Health/Check, SERVING passes.
+type GRPC struct {
+	scope   string
+	target  string
+	service string
+	conn    *grpc.ClientConn
+	client  healthpb.HealthClient
+}
+
+var _ healthz.HealthCheckable = (*GRPC)(nil)
+
+// NewGRPC - checker of the service (empty for the overall health of the server) at the target
+// (see grpc.NewClient), scope is ScopeGRPC if empty. Options must set the transport credentials,
+// e.g. grpc.WithTransportCredentials(insecure.NewCredentials()). The connection is established lazily
+// and kept between checks, Close releases it.
+func NewGRPC(scope, target, service string, opts ...grpc.DialOption) (*GRPC, error) {
+	if scope == "" {
+		scope = ScopeGRPC
+	}
+
+	conn, err := grpc.NewClient(target, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("grpc client of %s: %w", target, err)
+	}
+
+	return &GRPC{
+		scope:   scope,
+		target:  target,
+		service: service,
+		conn:    conn,
+		client:  healthpb.NewHealthClient(conn),
+	}, nil
+}
+
+func (g *GRPC) Scope() string { return g.scope }
+
+func (g *GRPC) Dest() string {
+	if g.service == "" {
+		return healthz.NormalizeDest(g.target)
+	}
+
+	return healthz.NormalizeDest(g.target) + "/" + g.service
+}
+
+func (g *GRPC) Health(ctx context.Context) error {
+	resp, err := g.client.Check(ctx, &healthpb.HealthCheckRequest{Service: g.service})
+	if err != nil {
+		return err
+	}
+
+	if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("%w: %s", errNotServing, resp.GetStatus())
+	}
+
+	return nil
+}
+
+// Close - closes the connection.
+func (g *GRPC) Close() error {
+	return g.conn.Close()
+}
@@ -0,0 +1,81 @@
+package checks
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+func TestGRPC(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	hs := health.NewServer()
+	hs.SetServingStatus("payments", healthpb.HealthCheckResponse_SERVING)
+	hs.SetServingStatus("ledger", healthpb.HealthCheckResponse_NOT_SERVING)
+
+	srv := grpc.NewServer()
+	healthpb.RegisterHealthServer(srv, hs)
+
+	go srv.Serve(ln)
+
+	defer srv.Stop()
+
+	addr := ln.Addr().String()
+	creds := grpc.WithTransportCredentials(insecure.NewCredentials())
+
+	_, err = NewGRPC("", addr, "")
+	assert.Error(t, err, "no transport credentials")
+
+	tests := []struct {
+		name     string
+		service  string
+		wantDest string
+		wantErr  error
+		wantCode codes.Code
+	}{
+		{name: "test.1 server", wantDest: addr},
+		{name: "test.2 serving service", service: "payments", wantDest: addr + "/payments"},
+		{name: "test.3 not serving service", service: "ledger", wantDest: addr + "/ledger", wantErr: errNotServing},
+		{name: "test.4 unknown service", service: "orders", wantDest: addr + "/orders", wantCode: codes.NotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewGRPC("", addr, tt.service, creds)
+			assert.NoError(t, err)
+
+			defer c.Close()
+
+			assert.Equal(t, ScopeGRPC, c.Scope())
+			assert.Equal(t, tt.wantDest, c.Dest())
+
+			err = c.Health(context.Background())
+
+			switch {
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+			case tt.wantCode != codes.OK:
+				assert.Equal(t, tt.wantCode, status.Code(err))
+			default:
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	c, err := NewGRPC("payments", addr, "", creds)
+	assert.NoError(t, err)
+	assert.Equal(t, "payments", c.Scope())
+
+	hs.Shutdown()
+	assert.ErrorIs(t, c.Health(context.Background()), errNotServing, "server is shutting down")
+	assert.NoError(t, c.Close())
+}
@@ -0,0 +1,59 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/art-frela/healthz"
+)
+
+// ScopeHTTP - scope of NewHTTP checkers created with empty scope.
+const ScopeHTTP = "http"
+
+// HTTP - checks the endpoint by GET request, 2xx responses pass.
+type HTTP struct {
+	scope  string
+	url    string
+	client *http.Client
+}
+
+var _ healthz.HealthCheckable = (*HTTP)(nil)
+
+// NewHTTP - checker of the url, scope is ScopeHTTP if empty, client could be nil (http.DefaultClient).
+func NewHTTP(scope, url string, client *http.Client) *HTTP {
+	if scope == "" {
+		scope = ScopeHTTP
+	}
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &HTTP{scope: scope, url: url, client: client}
+}
+
+func (h *HTTP) Scope() string { return h.scope }
+func (h *HTTP) Dest() string  { return healthz.NormalizeDest(h.url) }
+
+func (h *HTTP) Health(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, h.url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxBodySize))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%w: %d", errUnexpectedCode, resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package checks
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTP(t *testing.T) {
+	code := http.StatusOK
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(code)
+	}))
+	defer srv.Close()
+
+	c := NewHTTP("", srv.URL+"/health?token=secret", nil)
+
+	assert.Equal(t, ScopeHTTP, c.Scope())
+	assert.Equal(t, srv.URL+"/health", c.Dest())
+
+	tests := []struct {
+		name    string
+		code    int
+		wantErr bool
+	}{
+		{name: "test.1 ok", code: http.StatusOK},
+		{name: "test.2 no content", code: http.StatusNoContent},
+		{name: "test.3 unavailable", code: http.StatusServiceUnavailable, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code = tt.code
+
+			err := c.Health(context.Background())
+			if tt.wantErr {
+				assert.ErrorIs(t, err, errUnexpectedCode)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	srv.Close()
+	assert.Error(t, c.Health(context.Background()), "unreachable")
+}
@@ -0,0 +1,112 @@
+// Package checks - built-in health check targets for the healthz inspector.
+package checks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/art-frela/healthz"
+)
+
+const maxBodySize = 1 << 20
+
+var (
+	errIssuerMismatch = errors.New("issuer mismatch")
+	errMissJWKSURI    = errors.New("discovery document has no jwks_uri")
+	errNoKeys         = errors.New("jwks has no keys")
+	errWrongKey       = errors.New("jwks key without kty")
+	errUnexpectedCode = errors.New("unexpected status code")
+)
+
+// OIDC - checks the identity provider: OIDC discovery document and JWKS are fetched and parsed.
+type OIDC struct {
+	scope  string
+	issuer string
+	client *http.Client
+}
+
+var _ healthz.HealthCheckable = (*OIDC)(nil)
+
+// NewOIDC - checker of the issuer (e.g. "https://accounts.example.com/realms/app"),
+// client could be nil (http.DefaultClient).
+func NewOIDC(scope, issuer string, client *http.Client) *OIDC {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &OIDC{
+		scope:  scope,
+		issuer: strings.TrimSuffix(issuer, "/"),
+		client: client,
+	}
+}
+
+func (o *OIDC) Scope() string { return o.scope }
+func (o *OIDC) Dest() string  { return healthz.NormalizeDest(o.issuer) }
+
+func (o *OIDC) Health(ctx context.Context) error {
+	var discovery struct {
+		Issuer  string `json:"issuer"`
+		JWKSURI string `json:"jwks_uri"`
+	}
+
+	if err := getJSON(ctx, o.client, o.issuer+"/.well-known/openid-configuration", &discovery); err != nil {
+		return fmt.Errorf("discovery: %w", err)
+	}
+
+	if strings.TrimSuffix(discovery.Issuer, "/") != o.issuer {
+		return fmt.Errorf("%w: %q", errIssuerMismatch, discovery.Issuer)
+	}
+
+	if discovery.JWKSURI == "" {
+		return errMissJWKSURI
+	}
+
+	var jwks struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+		} `json:"keys"`
+	}
+
+	if err := getJSON(ctx, o.client, discovery.JWKSURI, &jwks); err != nil {
+		return fmt.Errorf("jwks: %w", err)
+	}
+
+	if len(jwks.Keys) == 0 {
+		return errNoKeys
+	}
+
+	for _, key := range jwks.Keys {
+		if key.Kty == "" {
+			return errWrongKey
+		}
+	}
+
+	return nil
+}
+
+func getJSON(ctx context.Context, client *http.Client, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: %d", errUnexpectedCode, resp.StatusCode)
+	}
+
+	return json.NewDecoder(io.LimitReader(resp.Body, maxBodySize)).Decode(v)
+}
@@ -0,0 +1,97 @@
+package checks
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOIDC(t *testing.T) {
+	var (
+		issuer    string
+		discovery string
+		jwks      string
+		jwksCode  int
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/realm/.well-known/openid-configuration":
+			w.Write([]byte(discovery))
+		case "/realm/certs":
+			w.WriteHeader(jwksCode)
+			w.Write([]byte(jwks))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	issuer = srv.URL + "/realm"
+
+	tests := []struct {
+		name      string
+		discovery string
+		jwks      string
+		jwksCode  int
+		wantErr   error
+		anyErr    bool
+	}{
+		{
+			name:      "test.1 healthy provider",
+			discovery: `{"issuer":"` + issuer + `","jwks_uri":"` + issuer + `/certs"}`,
+			jwks:      `{"keys":[{"kty":"RSA","kid":"1"}]}`,
+			jwksCode:  http.StatusOK,
+		},
+		{
+			name:      "test.2 issuer mismatch",
+			discovery: `{"issuer":"https://other","jwks_uri":"` + issuer + `/certs"}`,
+			wantErr:   errIssuerMismatch,
+		},
+		{
+			name:      "test.3 no jwks_uri",
+			discovery: `{"issuer":"` + issuer + `"}`,
+			wantErr:   errMissJWKSURI,
+		},
+		{
+			name:      "test.4 jwks unavailable",
+			discovery: `{"issuer":"` + issuer + `","jwks_uri":"` + issuer + `/certs"}`,
+			jwksCode:  http.StatusBadGateway,
+			wantErr:   errUnexpectedCode,
+		},
+		{
+			name:      "test.5 empty jwks",
+			discovery: `{"issuer":"` + issuer + `","jwks_uri":"` + issuer + `/certs"}`,
+			jwks:      `{"keys":[]}`,
+			jwksCode:  http.StatusOK,
+			wantErr:   errNoKeys,
+		},
+		{
+			name:      "test.6 broken discovery",
+			discovery: `<html>`,
+			anyErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			discovery, jwks, jwksCode = tt.discovery, tt.jwks, tt.jwksCode
+
+			err := NewOIDC("auth", issuer+"/", nil).Health(context.Background())
+
+			switch {
+			case tt.anyErr:
+				assert.Error(t, err)
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+			default:
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	assert.Equal(t, issuer, NewOIDC("auth", issuer, nil).Dest())
+}
@@ -0,0 +1,122 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/art-frela/healthz"
+)
+
+// Default headers with the remaining quota, checked in order.
+var DefaultQuotaHeaders = []string{"X-RateLimit-Remaining", "RateLimit-Remaining"}
+
+var errMissQuotaHeader = errors.New("missing rate-limit header")
+
+// QuotaLowError - remaining quota of the API is below the threshold, the API is reachable
+// but degraded; find it by errors.As. The target is degraded (see healthz.Degraded)
+// while some quota remains and unhealthy when it's exhausted.
+type QuotaLowError struct {
+	Remaining int64
+	Threshold int64
+}
+
+var _ healthz.DegradedError = (*QuotaLowError)(nil)
+
+func (e *QuotaLowError) Error() string {
+	return fmt.Sprintf("rate-limit quota is low: %d remaining, threshold %d", e.Remaining, e.Threshold)
+}
+
+func (e *QuotaLowError) Degraded() bool { return e.Remaining > 0 }
+
+// Quota - checks remaining rate-limit quota of a partner API by the response headers.
+type Quota struct {
+	scope     string
+	url       string
+	threshold int64
+	client    *http.Client
+	headers   []string
+}
+
+var _ healthz.HealthCheckable = (*Quota)(nil)
+
+// NewQuota - checker of the url (cheap endpoint of the API) failing with QuotaLowError when the
+// remaining quota is below the threshold, client could be nil (http.DefaultClient), the quota is
+// read from the headers (DefaultQuotaHeaders if none).
+func NewQuota(scope, url string, threshold int64, client *http.Client, headers ...string) *Quota {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	if len(headers) == 0 {
+		headers = DefaultQuotaHeaders
+	}
+
+	return &Quota{
+		scope:     scope,
+		url:       url,
+		threshold: threshold,
+		client:    client,
+		headers:   headers,
+	}
+}
+
+func (q *Quota) Scope() string { return q.scope }
+func (q *Quota) Dest() string  { return healthz.NormalizeDest(q.url) }
+
+func (q *Quota) Health(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, q.url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := q.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return &QuotaLowError{Remaining: 0, Threshold: q.threshold}
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%w: %d", errUnexpectedCode, resp.StatusCode)
+	}
+
+	remaining, err := q.remaining(resp.Header)
+	if err != nil {
+		return err
+	}
+
+	if remaining < q.threshold {
+		return &QuotaLowError{Remaining: remaining, Threshold: q.threshold}
+	}
+
+	return nil
+}
+
+func (q *Quota) remaining(header http.Header) (int64, error) {
+	for _, name := range q.headers {
+		value := header.Get(name)
+		if value == "" {
+			continue
+		}
+
+		// "RateLimit-Remaining" could be a list of the policies, the first one is the most restrictive
+		value, _, _ = strings.Cut(value, ",")
+
+		remaining, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("header %s: %w", name, err)
+		}
+
+		return remaining, nil
+	}
+
+	return 0, fmt.Errorf("%w: %s", errMissQuotaHeader, strings.Join(q.headers, ", "))
+}
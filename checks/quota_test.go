@@ -0,0 +1,65 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuota(t *testing.T) {
+	var (
+		code    int
+		headers map[string]string
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		for k, v := range headers {
+			w.Header().Set(k, v)
+		}
+
+		w.WriteHeader(code)
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name      string
+		code      int
+		headers   map[string]string
+		wantErr   error
+		remaining int64
+		lowQuota  bool
+	}{
+		{name: "test.1 enough quota", code: http.StatusOK, headers: map[string]string{"X-RateLimit-Remaining": "500"}},
+		{name: "test.2 low quota", code: http.StatusOK, headers: map[string]string{"X-RateLimit-Remaining": "5"}, lowQuota: true, remaining: 5},
+		{name: "test.3 ietf header", code: http.StatusOK, headers: map[string]string{"RateLimit-Remaining": "50, 900"}},
+		{name: "test.4 exhausted", code: http.StatusTooManyRequests, lowQuota: true},
+		{name: "test.5 missing header", code: http.StatusOK, wantErr: errMissQuotaHeader},
+		{name: "test.6 server error", code: http.StatusBadGateway, wantErr: errUnexpectedCode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, headers = tt.code, tt.headers
+
+			err := NewQuota("partner", srv.URL, 10, nil).Health(context.Background())
+
+			switch {
+			case tt.lowQuota:
+				var low *QuotaLowError
+
+				assert.True(t, errors.As(err, &low))
+				assert.Equal(t, tt.remaining, low.Remaining)
+				assert.Equal(t, int64(10), low.Threshold)
+				assert.Equal(t, tt.remaining > 0, low.Degraded(), "degraded until exhausted")
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+			default:
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"runtime/metrics"
+
+	"github.com/art-frela/healthz"
+)
+
+// ScopeRuntime - scope of NewRuntime checkers created with empty scope.
+const ScopeRuntime = "runtime"
+
+// heapMetric - bytes of live and not yet swept heap objects, read without stopping the world.
+const heapMetric = "/memory/classes/heap/objects:bytes"
+
+var (
+	errHeapTooLarge    = errors.New("heap is too large")
+	errTooManyRoutines = errors.New("too many goroutines")
+)
+
+// Runtime - self-check of the process failing when it's clearly leaking memory or goroutines,
+// meant for healthz.GroupLive, so the orchestrator restarts the process.
+type Runtime struct {
+	scope         string
+	maxHeapBytes  uint64
+	maxGoroutines int
+}
+
+var _ healthz.DetailedChecker = (*Runtime)(nil)
+
+// NewRuntime - checker of the process failing when the heap exceeds maxHeapBytes or the number of goroutines
+// exceeds maxGoroutines, zero disables the threshold; scope is ScopeRuntime if empty, dest is "process".
+// Current values are reported by the details of the result: "heap_bytes", "goroutines".
+func NewRuntime(scope string, maxHeapBytes uint64, maxGoroutines int) *Runtime {
+	if scope == "" {
+		scope = ScopeRuntime
+	}
+
+	return &Runtime{scope: scope, maxHeapBytes: maxHeapBytes, maxGoroutines: maxGoroutines}
+}
+
+func (r *Runtime) Scope() string { return r.scope }
+func (r *Runtime) Dest() string  { return "process" }
+
+func (r *Runtime) Health(ctx context.Context) error {
+	_, err := r.HealthDetails(ctx)
+
+	return err
+}
+
+func (r *Runtime) HealthDetails(_ context.Context) (map[string]any, error) {
+	sample := []metrics.Sample{{Name: heapMetric}}
+	metrics.Read(sample)
+
+	heap := sample[0].Value.Uint64()
+	goroutines := runtime.NumGoroutine()
+
+	details := map[string]any{
+		"heap_bytes": heap,
+		"goroutines": goroutines,
+	}
+
+	var errs []error
+
+	if r.maxHeapBytes > 0 && heap > r.maxHeapBytes {
+		errs = append(errs, fmt.Errorf("%w: %d bytes, maximum %d", errHeapTooLarge, heap, r.maxHeapBytes))
+	}
+
+	if r.maxGoroutines > 0 && goroutines > r.maxGoroutines {
+		errs = append(errs, fmt.Errorf("%w: %d, maximum %d", errTooManyRoutines, goroutines, r.maxGoroutines))
+	}
+
+	return details, errors.Join(errs...)
+}
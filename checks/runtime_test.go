@@ -0,0 +1,45 @@
+package checks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRuntime(t *testing.T) {
+	c := NewRuntime("", 0, 0)
+	assert.Equal(t, ScopeRuntime, c.Scope())
+	assert.Equal(t, "process", c.Dest())
+	assert.Equal(t, "self", NewRuntime("self", 0, 0).Scope())
+
+	tests := []struct {
+		name          string
+		maxHeapBytes  uint64
+		maxGoroutines int
+		wantErrs      []error
+	}{
+		{name: "test.1 no thresholds"},
+		{name: "test.2 within thresholds", maxHeapBytes: 1 << 40, maxGoroutines: 1 << 20},
+		{name: "test.3 heap too large", maxHeapBytes: 1, wantErrs: []error{errHeapTooLarge}},
+		{name: "test.4 too many goroutines", maxGoroutines: 1, wantErrs: []error{errTooManyRoutines}},
+		{name: "test.5 both", maxHeapBytes: 1, maxGoroutines: 1, wantErrs: []error{errHeapTooLarge, errTooManyRoutines}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			details, err := NewRuntime("", tt.maxHeapBytes, tt.maxGoroutines).HealthDetails(context.Background())
+
+			if len(tt.wantErrs) == 0 {
+				assert.NoError(t, err)
+			}
+
+			for _, want := range tt.wantErrs {
+				assert.ErrorIs(t, err, want)
+			}
+
+			assert.Greater(t, details["heap_bytes"], uint64(0))
+			assert.Greater(t, details["goroutines"], 1)
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package checks
+
+import (
+	"context"
+
+	"github.com/art-frela/healthz"
+)
+
+// ScopeDatabase - scope of NewSQL checkers created with empty scope.
+const ScopeDatabase = "database"
+
+// Pinger - connection pool verifying a connection is alive, e.g. *sql.DB.
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// SQL - checks the database by ping of the pool (*sql.DB, *sqlx.DB).
+type SQL struct {
+	scope string
+	dest  string
+	db    Pinger
+}
+
+var _ healthz.HealthCheckable = (*SQL)(nil)
+
+// NewSQL - checker of the pool opened by the dsn, scope is ScopeDatabase if empty,
+// dest is the dsn without credentials (see healthz.NormalizeDest).
+func NewSQL(scope, dsn string, db Pinger) *SQL {
+	if scope == "" {
+		scope = ScopeDatabase
+	}
+
+	return &SQL{scope: scope, dest: healthz.NormalizeDest(dsn), db: db}
+}
+
+func (s *SQL) Scope() string { return s.scope }
+func (s *SQL) Dest() string  { return s.dest }
+
+func (s *SQL) Health(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
@@ -0,0 +1,48 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type pinger struct{ err error }
+
+func (p pinger) PingContext(context.Context) error { return p.err }
+
+func TestSQL(t *testing.T) {
+	tests := []struct {
+		name      string
+		scope     string
+		dsn       string
+		pingErr   error
+		wantScope string
+		wantDest  string
+	}{
+		{
+			name:      "test.1 default scope, dsn without credentials",
+			dsn:       "postgres://app:secret@db:5432/app?sslmode=disable",
+			wantScope: ScopeDatabase,
+			wantDest:  "postgres://db:5432/app",
+		},
+		{
+			name:      "test.2 own scope, ping fails",
+			scope:     "billing-db",
+			dsn:       "host=db port=5432 user=app password=secret",
+			pingErr:   errors.New("connection refused"),
+			wantScope: "billing-db",
+			wantDest:  "host=db port=5432",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewSQL(tt.scope, tt.dsn, pinger{err: tt.pingErr})
+
+			assert.Equal(t, tt.wantScope, c.Scope())
+			assert.Equal(t, tt.wantDest, c.Dest())
+			assert.Equal(t, tt.pingErr, c.Health(context.Background()))
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package checks
+
+import (
+	"context"
+	"net"
+
+	"github.com/art-frela/healthz"
+)
+
+// ScopeTCP - scope of NewTCP checkers created with empty scope.
+const ScopeTCP = "tcp"
+
+// TCP - checks the address accepts TCP connections.
+type TCP struct {
+	scope  string
+	addr   string
+	dialer net.Dialer
+}
+
+var _ healthz.HealthCheckable = (*TCP)(nil)
+
+// NewTCP - checker of the address ("host:port"), scope is ScopeTCP if empty.
+func NewTCP(scope, addr string) *TCP {
+	if scope == "" {
+		scope = ScopeTCP
+	}
+
+	return &TCP{scope: scope, addr: addr}
+}
+
+func (t *TCP) Scope() string { return t.scope }
+func (t *TCP) Dest() string  { return t.addr }
+
+func (t *TCP) Health(ctx context.Context) error {
+	conn, err := t.dialer.DialContext(ctx, "tcp", t.addr)
+	if err != nil {
+		return err
+	}
+
+	return conn.Close()
+}
@@ -0,0 +1,25 @@
+package checks
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	addr := ln.Addr().String()
+
+	c := NewTCP("", addr)
+	assert.Equal(t, ScopeTCP, c.Scope())
+	assert.Equal(t, addr, c.Dest())
+	assert.NoError(t, c.Health(context.Background()))
+
+	ln.Close()
+	assert.Error(t, c.Health(context.Background()), "closed listener")
+	assert.Equal(t, "cache", NewTCP("cache", addr).Scope())
+}
@@ -0,0 +1,100 @@
+package healthz
+
+import (
+	"errors"
+	"time"
+)
+
+var errChildLifecycle = errors.New("child inspector is driven by its parent, start the parent")
+
+// Child - view of the inspector restricted to targets of the scope, with own handlers,
+// response settings and metrics (apply options to the child). Checks are run by the parent.
+func (i *Inspector) Child(scope string, opts ...Option) (*Inspector, error) {
+	child := New()
+	child.parent = i
+	child.scope = scope
+	child.response = i.response
+	child.redactor = i.redactor
+	child.emptyGroup = i.emptyGroup
+
+	for _, opt := range opts {
+		if err := opt(child); err != nil {
+			return nil, err
+		}
+	}
+
+	i.childrenMu.Lock()
+	defer i.childrenMu.Unlock()
+
+	i.children = append(i.children, child)
+
+	return child, nil
+}
+
+// scoped - results of the scope targets only.
+func (hr *healthResult) scoped(scope string) *healthResult {
+	next := &healthResult{checked: hr.checked, checkedAt: hr.checkedAt, seq: hr.seq, publishedAt: hr.publishedAt}
+
+	for _, tr := range hr.targets {
+		if tr.Scope == scope {
+			next.targets = append(next.targets, tr)
+		}
+	}
+
+	return next
+}
+
+func (i *Inspector) childrenOf(scope string) []*Inspector {
+	i.childrenMu.Lock()
+	defer i.childrenMu.Unlock()
+
+	var list []*Inspector
+
+	for _, child := range i.children {
+		if child.scope == scope {
+			list = append(list, child)
+		}
+	}
+
+	return list
+}
+
+// updateChildMetrics - metrics of the children owning the target.
+func (i *Inspector) updateChildMetrics(res serviceCheckResult) error {
+	var errs []error
+
+	for _, child := range i.childrenOf(res.target.Service.Scope()) {
+		errs = append(errs, child.updateMetric(res))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (i *Inspector) childChanges(since time.Time) []Change {
+	var list []Change
+
+	for _, c := range i.parent.Changes(since) {
+		if c.Scope == i.scope {
+			list = append(list, c)
+		}
+	}
+
+	return list
+}
+
+func (i *Inspector) childHistory(from, to time.Time) ([]HistoryEntry, error) {
+	entries, err := i.parent.History(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var list []HistoryEntry
+
+	for _, e := range entries {
+		if e.Scope == i.scope {
+			list = append(list, e)
+		}
+	}
+
+	return list, nil
+}
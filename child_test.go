@@ -0,0 +1,58 @@
+package healthz
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChild(t *testing.T) {
+	kafka := &mockService{scope: "kafka", dest: "k-1"}
+	parent := New(
+		HealthCheckTarget{Service: &mockService{scope: "db", dest: "pg"}, Groups: GroupReady},
+		HealthCheckTarget{Service: kafka, Groups: GroupReady},
+	)
+
+	metric := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test_child_up"}, []string{"scope", "dest"})
+
+	child, err := parent.Child("db", WithMetric(metric), WithResponseFormat(FormatKubeVerbose))
+	assert.NoError(t, err)
+
+	_, err = parent.Child("db", WithCheckPeriod(0))
+	assert.Error(t, err)
+
+	assert.ErrorIs(t, child.Start(context.Background()), errChildLifecycle)
+
+	kafka.healthErr = errors.New("fail")
+	parent.check(context.Background())
+
+	assert.Error(t, parent.CheckGroup(GroupReady, true))
+	assert.NoError(t, child.CheckGroup(GroupReady, true), "kafka is out of the child scope")
+
+	assert.Len(t, child.Snapshot().Targets, 1)
+	assert.Equal(t, 1.0, testutil.ToFloat64(metric.WithLabelValues("db", "pg")))
+	assert.Equal(t, 1, testutil.CollectAndCount(metric))
+
+	w := httptest.NewRecorder()
+	child.HealthHandler(GroupReady, true, nil)(w, httptest.NewRequest(http.MethodGet, "/db/ready", nil))
+	assert.Equal(t, "[+]db/pg ok\nready check passed\n", w.Body.String())
+
+	assert.False(t, child.Invalidate("kafka", "k-1"), "not a target of the child")
+	assert.True(t, child.Invalidate("db", "pg"))
+	assert.ErrorIs(t, parent.CheckGroup(GroupReady, false), errInvalidated)
+	assert.Len(t, child.Changes(time.Time{}), 1)
+	assert.Equal(t, "pg", child.Changes(time.Time{})[0].Dest)
+
+	assert.ErrorIs(t, child.MarkUnhealthy("kafka", "k-1", nil, time.Minute), errForeignScope)
+	assert.ErrorIs(t, child.MarkUnhealthy("db", "mysql", nil, time.Minute), errUnregisteredTarget)
+	assert.NoError(t, child.MarkUnhealthy("db", "pg", nil, time.Minute))
+	assert.ErrorIs(t, parent.CheckGroup(GroupReady, false), errMarkedUnhealthy, "marked on the parent")
+	assert.ErrorIs(t, child.CheckGroup(GroupReady, true), errMarkedUnhealthy)
+}
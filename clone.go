@@ -0,0 +1,145 @@
+package healthz
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"unsafe"
+)
+
+var errChildConfig = errors.New("child inspector has no config of its own, use the parent")
+
+// WithOverrides - copy of the inspector with the options applied on top of its settings,
+// e.g. shorter periods and fake targets for integration tests reusing production wiring.
+// The copy shares no state with the original: it isn't started, has no results, changes,
+// overrides, forced readiness, subscriptions or children, state change callbacks are kept.
+// Sinks of the original aren't copied: metrics, history store, execution log, textfile exporter,
+// Pushgateway and logger, give the copy its own by options.
+// A copy of a child is a view of the same parent, which doesn't know about it.
+// Panics if an option fails, like regexp.MustCompile, as it's meant for test setups.
+func (i *Inspector) WithOverrides(opts ...Option) *Inspector {
+	i.mu.RLock()
+
+	clone := &Inspector{
+		targets:       slices.Clone(i.targets),
+		checkPeriod:   i.checkPeriod,
+		reload:        make(chan struct{}, 1),
+		cycleTimeout:  i.cycleTimeout,
+		checkTimeout:  i.checkTimeout,
+		data:          unsafe.Pointer(newHealthResult()),
+		response:      i.response,
+		routes:        maps.Clone(i.routes),
+		shutdownDelay: i.shutdownDelay,
+		hysteresis:    maps.Clone(i.hysteresis),
+		parent:        i.parent,
+		scope:         i.scope,
+		redactor:      i.redactor,
+		tracer:        i.tracer,
+		exemplar:      i.exemplar,
+		emptyGroup:    i.emptyGroup,
+		auditGrace:    i.auditGrace,
+		groupMsgs:     maps.Clone(i.groupMsgs),
+		targetMsgs:    maps.Clone(i.targetMsgs),
+		providers:     slices.Clone(i.providers),
+		namespace:     i.namespace,
+		thresholds:    i.thresholds,
+		metricLabels:  slices.Clone(i.metricLabels),
+		latchStartup:  i.latchStartup,
+		maxChecks:     i.maxChecks,
+		backoffMax:    i.backoffMax,
+		bootstrap:     i.bootstrap,
+		adaptive:      i.adaptive,
+		logLevels:     i.logLevels,
+		refreshQuery:  i.refreshQuery,
+		groupHeader:   i.groupHeader,
+	}
+
+	i.mu.RUnlock()
+
+	if i.scopeSlots != nil {
+		clone.scopeSlots = make(map[string]chan struct{}, len(i.scopeSlots))
+
+		for scope, slots := range i.scopeSlots {
+			clone.scopeSlots[scope] = make(chan struct{}, cap(slots))
+		}
+	}
+
+	if i.onDemand != nil {
+		clone.onDemand = &onDemand{interval: i.onDemand.interval}
+	}
+
+	if i.retention != nil {
+		clone.retention = &retention{policy: i.retention.policy}
+	}
+
+	i.retry.mu.Lock()
+	clone.retry.attempts = i.retry.attempts
+	clone.retry.backoff = i.retry.backoff
+	clone.retry.maxTokens = i.retry.maxTokens
+	clone.retry.ratio = i.retry.ratio
+	i.retry.mu.Unlock()
+
+	i.stoppingMu.Lock()
+	clone.stopping = slices.Clone(i.stopping)
+	i.stoppingMu.Unlock()
+
+	i.subscribers.mu.Lock()
+	clone.subscribers.callbacks = slices.Clone(i.subscribers.callbacks)
+	i.subscribers.mu.Unlock()
+
+	if i.webhook != nil {
+		wh := *i.webhook
+		wh.queue = make(chan WebhookEvent, cap(i.webhook.queue))
+		clone.webhook = &wh
+	}
+
+	for n, opt := range opts {
+		if err := opt(clone); err != nil {
+			panic(fmt.Sprintf("healthz: override #%d: %v", n, err))
+		}
+	}
+
+	return clone
+}
+
+// shareSinks - sets the sinks of the inspector, not copied by WithOverrides, to the copy replacing it.
+func (i *Inspector) shareSinks(clone *Inspector) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	clone.metric = i.metric
+	clone.metricErrors = i.metricErrors
+	clone.outcomeMetric = i.outcomeMetric
+	clone.latencyMetric = i.latencyMetric
+	clone.durationHist = i.durationHist
+	clone.queueWaitHist = i.queueWaitHist
+	clone.scopeMetric = i.scopeMetric
+	clone.targetMetric = i.targetMetric
+	clone.history = i.history
+	clone.execLog = i.execLog
+	clone.textfile = i.textfile
+	clone.pushgateway = i.pushgateway
+	clone.logger = i.logger
+}
+
+// WithConfig - copy of the inspector (see WithOverrides) with the config applied (see ApplyConfig),
+// e.g. a candidate of the updated config for Rollout. The inspector itself is left as is,
+// the copy takes over its sinks (metrics, history and so on), they are written once it's promoted.
+// Children have no config of their own and fail.
+func (i *Inspector) WithConfig(cfg Config) (*Inspector, error) {
+	if i.parent != nil {
+		return nil, errChildConfig
+	}
+
+	clone := i.WithOverrides()
+
+	if err := clone.ApplyConfig(cfg); err != nil {
+		return nil, err
+	}
+
+	// after the config, series of targets it drops belong to the inspector still
+	i.shareSinks(clone)
+
+	return clone, nil
+}
@@ -0,0 +1,99 @@
+package healthz
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithOverrides(t *testing.T) {
+	prod := New(HealthCheckTarget{Service: &mockService{scope: "db", dest: "pg", healthErr: errors.New("fail")}, Groups: GroupReady})
+	assert.NoError(t, WithCheckPeriod(time.Minute)(prod))
+	assert.NoError(t, WithGroupMessages(GroupReady, Messages{Healthy: "READY"})(prod))
+	prod.check(context.Background())
+
+	fake := &mockService{scope: "db", dest: "fake"}
+	clone := prod.WithOverrides(
+		WithCheckPeriod(10*time.Millisecond),
+		WithTargets(HealthCheckTarget{Service: fake, Groups: GroupReady}),
+	)
+
+	assert.Equal(t, 10*time.Millisecond, clone.period())
+	assert.Equal(t, time.Minute, prod.period(), "original settings are kept")
+	assert.Equal(t, "READY", clone.groupMsgs[GroupReady].Healthy, "not overridden settings are copied")
+	assert.ErrorIs(t, clone.CheckGroup(GroupReady, true), errNoYetChecked, "results are not shared")
+
+	clone.check(context.Background())
+
+	assert.NoError(t, clone.CheckGroup(GroupReady, true))
+	assert.Error(t, prod.CheckGroup(GroupReady, true))
+	assert.Len(t, prod.Snapshot().Targets, 1)
+	assert.Equal(t, "pg", prod.Snapshot().Targets[0].Dest)
+
+	assert.NoError(t, WithGroupMessages(GroupReady, Messages{Healthy: "UP"})(clone))
+	assert.Equal(t, "READY", prod.groupMsgs[GroupReady].Healthy, "maps are not shared")
+
+	assert.Panics(t, func() { prod.WithOverrides(WithCheckPeriod(-time.Second)) })
+}
+
+func TestWithOverrides_sinks(t *testing.T) {
+	up := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test_with_overrides_up"}, []string{"scope", "dest"})
+
+	store, err := NewMemoryHistory(10)
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+
+	prod := New(HealthCheckTarget{Service: &mockService{scope: "db", dest: "pg"}, Groups: GroupReady})
+	assert.NoError(t, WithMetric(up)(prod))
+	assert.NoError(t, WithHistory(store)(prod))
+	assert.NoError(t, WithExecutionLog(NewWriterExecutionLog(&buf))(prod))
+
+	parent := New()
+	child, err := parent.Child("db")
+	assert.NoError(t, err)
+
+	clone := prod.WithOverrides()
+	clone.check(context.Background())
+
+	assert.Zero(t, testutil.CollectAndCount(up), "metrics are not shared")
+	assert.Zero(t, buf.Len(), "execution log is not shared")
+
+	entries, err := prod.History(time.Time{}, time.Now())
+	assert.NoError(t, err)
+	assert.Empty(t, entries, "history is not shared")
+
+	childClone := child.WithOverrides()
+	assert.Same(t, parent, childClone.parent)
+	assert.Equal(t, []*Inspector{child}, parent.childrenOf("db"), "the copy isn't a child of the parent")
+
+	candidate, err := prod.WithConfig(Config{})
+	assert.NoError(t, err)
+	assert.Same(t, store, candidate.history, "the candidate takes over the sinks")
+}
+
+func TestWithConfig(t *testing.T) {
+	prod := New(HealthCheckTarget{Service: &mockService{scope: "db", dest: "pg"}, Groups: GroupReady})
+	assert.NoError(t, WithCheckPeriod(time.Minute)(prod))
+
+	candidate, err := prod.WithConfig(Config{CheckPeriod: Duration(time.Second)})
+	assert.NoError(t, err)
+	assert.Equal(t, time.Second, candidate.period())
+	assert.Equal(t, time.Minute, prod.period(), "the original is left as is")
+	assert.Len(t, candidate.targets, 1, "programmatic targets are kept")
+
+	_, err = prod.WithConfig(Config{CheckPeriod: Duration(-time.Second)})
+	assert.ErrorIs(t, err, errWrongCheckPeriod)
+
+	child, err := prod.Child("db")
+	assert.NoError(t, err)
+
+	_, err = child.WithConfig(Config{})
+	assert.ErrorIs(t, err, errChildConfig)
+}
@@ -0,0 +1,102 @@
+package healthz
+
+import (
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+var errWrongCodec = errors.New("codec must have content type")
+
+// Codec - serialization of snapshots and other published values (history entries, scope summaries,
+// webhook events), JSON by default. See package codecs for MessagePack.
+type Codec interface {
+	ContentType() string // media type without parameters, e.g. "application/json"
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// JSONCodec - the default codec.
+var JSONCodec Codec = jsonCodec{}
+
+type jsonCodec struct{}
+
+func (jsonCodec) ContentType() string                { return "application/json" }
+func (jsonCodec) Marshal(v any) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v any) error { return json.Unmarshal(data, v) }
+
+var (
+	codecsMu sync.RWMutex
+	codecs   = map[string]Codec{JSONCodec.ContentType(): JSONCodec}
+)
+
+// RegisterCodec - adds (or replaces) a codec negotiated by the Accept header of StatusHandler,
+// ScopesHandler, HistoryHandler, ReportHandler, GraphHandler (JSON form) and ScheduleHandler
+// and selected by Content-Type of received webhooks.
+func RegisterCodec(c Codec) error {
+	if c == nil || c.ContentType() == "" {
+		return errWrongCodec
+	}
+
+	codecsMu.Lock()
+	defer codecsMu.Unlock()
+
+	codecs[c.ContentType()] = c
+
+	return nil
+}
+
+// LookupCodec - registered codec of the content type (parameters are ignored), JSONCodec for empty one.
+func LookupCodec(contentType string) (Codec, bool) {
+	if contentType == "" {
+		return JSONCodec, true
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, false
+	}
+
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+
+	c, ok := codecs[mediaType]
+
+	return c, ok
+}
+
+// negotiateCodec - first registered codec accepted by the request, JSONCodec by default.
+func negotiateCodec(r *http.Request) Codec {
+	for _, accepted := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(accepted))
+		if err != nil || refused(params["q"]) {
+			continue
+		}
+
+		if c, ok := LookupCodec(mediaType); ok {
+			return c
+		}
+	}
+
+	return JSONCodec
+}
+
+// writeEncoded - writes v encoded by the codec negotiated with the request.
+func writeEncoded(w http.ResponseWriter, r *http.Request, status int, v any) {
+	codec := negotiateCodec(r)
+
+	body, err := codec.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", codec.ContentType())
+	w.Header().Add("Vary", "Accept")
+	w.WriteHeader(status)
+	w.Write(body)
+}
@@ -0,0 +1,69 @@
+package healthz
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type textCodec struct{ jsonCodec }
+
+func (textCodec) ContentType() string { return "application/x-test" }
+
+func Test_negotiateCodec(t *testing.T) {
+	assert.NoError(t, RegisterCodec(textCodec{}))
+	assert.ErrorIs(t, RegisterCodec(nil), errWrongCodec)
+
+	tests := []struct {
+		name   string
+		accept string
+		want   string
+	}{
+		{name: "test.1 no accept", want: "application/json"},
+		{name: "test.2 any", accept: "*/*", want: "application/json"},
+		{name: "test.3 registered", accept: "text/html, application/x-test;q=0.9", want: "application/x-test"},
+		{name: "test.4 refused", accept: "application/x-test;q=0", want: "application/json"},
+		{name: "test.5 unknown", accept: "application/xml", want: "application/json"},
+		{name: "test.6 refused by q=0.000", accept: "application/x-test;q=0.000", want: "application/json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/status", nil)
+			if tt.accept != "" {
+				r.Header.Set("Accept", tt.accept)
+			}
+
+			assert.Equal(t, tt.want, negotiateCodec(r).ContentType())
+		})
+	}
+
+	c, ok := LookupCodec("application/json; charset=utf-8")
+	assert.True(t, ok)
+	assert.Equal(t, JSONCodec, c)
+
+	_, ok = LookupCodec("application/xml")
+	assert.False(t, ok)
+}
+
+func TestHandlers_codec(t *testing.T) {
+	assert.NoError(t, RegisterCodec(textCodec{}))
+
+	inspector := New(HealthCheckTarget{Service: &mockService{scope: "db", dest: "pg"}, Groups: GroupReady})
+	store, err := NewMemoryHistory(10)
+	assert.NoError(t, err)
+	assert.NoError(t, WithHistory(store)(inspector))
+	inspector.check(context.Background())
+
+	for _, handler := range []http.Handler{inspector.ReportHandler(), inspector.GraphHandler(), inspector.ScheduleHandler()} {
+		rec := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Accept", "application/x-test")
+
+		handler.ServeHTTP(rec, r)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/x-test", rec.Header().Get("Content-Type"))
+	}
+}
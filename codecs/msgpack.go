@@ -0,0 +1,79 @@
+// Package codecs - healthz.Codec implementations besides the default JSON one: MessagePack and
+// protobuf of snapshots (schema proto/healthz/v1/healthz.proto), register them to be negotiated
+// by the endpoints:
+//
+//	err := healthz.RegisterCodec(codecs.MsgPack)
+package codecs
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/art-frela/healthz"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// MsgPack - MessagePack codec ("application/msgpack"). Values are encoded in their JSON form
+// (custom MarshalJSON of results and changes, json field names), so both codecs carry the same data.
+var MsgPack healthz.Codec = msgPack{}
+
+type msgPack struct{}
+
+func (msgPack) ContentType() string { return "application/msgpack" }
+
+func (msgPack) Marshal(v any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var generic any
+
+	if err := dec.Decode(&generic); err != nil {
+		return nil, err
+	}
+
+	return msgpack.Marshal(numbers(generic))
+}
+
+func (msgPack) Unmarshal(data []byte, v any) error {
+	var generic any
+
+	if err := msgpack.Unmarshal(data, &generic); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(generic)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+// numbers - replaces json.Number of the decoded JSON by int64 or float64 keeping integers exact.
+func numbers(v any) any {
+	switch v := v.(type) {
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+
+		f, _ := v.Float64()
+
+		return f
+	case map[string]any:
+		for k, item := range v {
+			v[k] = numbers(item)
+		}
+	case []any:
+		for n, item := range v {
+			v[n] = numbers(item)
+		}
+	}
+
+	return v
+}
@@ -0,0 +1,101 @@
+package codecs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/art-frela/healthz"
+	"github.com/stretchr/testify/assert"
+)
+
+type service struct{ err error }
+
+func (s service) Health(context.Context) error { return s.err }
+func (s service) Scope() string                { return "db" }
+func (s service) Dest() string                 { return "pg" }
+
+func TestMsgPack(t *testing.T) {
+	assert.NoError(t, healthz.RegisterCodec(MsgPack))
+
+	inspector := healthz.New(healthz.HealthCheckTarget{
+		Service: service{err: healthz.Degraded(errors.New("replica is down"))},
+		Groups:  healthz.GroupReady,
+	})
+	assert.NoError(t, inspector.Start(context.Background()))
+	defer inspector.Stop(context.Background())
+
+	assert.Eventually(t, func() bool { return len(inspector.Snapshot().Targets) > 0 }, time.Second, time.Millisecond)
+
+	srv := httptest.NewServer(inspector.StatusHandler())
+	defer srv.Close()
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	assert.NoError(t, err)
+	req.Header.Set("Accept", "application/msgpack, application/json;q=0.5")
+
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+
+	defer resp.Body.Close()
+
+	assert.Equal(t, "application/msgpack", resp.Header.Get("Content-Type"))
+
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+
+	var snapshot healthz.Snapshot
+
+	assert.NoError(t, MsgPack.Unmarshal(body, &snapshot))
+
+	if assert.Len(t, snapshot.Targets, 1) {
+		tr := snapshot.Targets[0]
+		assert.Equal(t, "pg", tr.Dest)
+		assert.Equal(t, healthz.GroupReady, tr.Groups)
+		assert.Equal(t, healthz.StatusDegraded, tr.Status())
+		assert.EqualError(t, tr.Degraded, "replica is down")
+	}
+
+	var n struct {
+		Big   int64   `json:"big"`
+		Float float64 `json:"float"`
+	}
+
+	data, err := MsgPack.Marshal(map[string]any{"big": int64(1) << 60, "float": 0.25})
+	assert.NoError(t, err)
+	assert.NoError(t, MsgPack.Unmarshal(data, &n))
+	assert.Equal(t, int64(1)<<60, n.Big, "integers are exact")
+	assert.Equal(t, 0.25, n.Float)
+}
+
+func TestMsgPack_fileHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.msgpack")
+
+	store, err := healthz.NewCodecFileHistory(path, MsgPack)
+	assert.NoError(t, err)
+
+	at := time.Now().Truncate(time.Second)
+	assert.NoError(t, store.Append(
+		healthz.HistoryEntry{Time: at, Scope: "db", Dest: "pg", Healthy: true},
+		healthz.HistoryEntry{Time: at, Scope: "db", Dest: "replica", Error: "lag"},
+	))
+	assert.NoError(t, store.Close())
+
+	store, err = healthz.NewCodecFileHistory(path, MsgPack)
+	assert.NoError(t, err)
+	defer store.Close()
+
+	entries, err := store.Query(time.Time{}, time.Now().Add(time.Second))
+	assert.NoError(t, err)
+
+	if assert.Len(t, entries, 2) {
+		assert.True(t, entries[0].Healthy)
+		assert.Equal(t, "lag", entries[1].Error)
+		assert.True(t, at.Equal(entries[1].Time))
+	}
+}
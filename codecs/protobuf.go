@@ -0,0 +1,502 @@
+package codecs
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/art-frela/healthz"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var (
+	errUnsupported = errors.New("protobuf codec supports healthz.Snapshot and healthz.SnapshotPage only")
+	errMalformed   = errors.New("malformed protobuf")
+)
+
+// Protobuf - protobuf codec ("application/x-protobuf") of snapshots by the schema healthz.v1.Snapshot
+// (proto/healthz/v1/healthz.proto), for strongly typed tooling. Other values aren't supported,
+// so register it for the status endpoint, not for webhooks or history.
+var Protobuf healthz.Codec = protobuf{}
+
+type protobuf struct{}
+
+func (protobuf) ContentType() string { return "application/x-protobuf" }
+
+func (protobuf) Marshal(v any) ([]byte, error) {
+	switch v := v.(type) {
+	case healthz.Snapshot:
+		return appendSnapshot(nil, v, nil)
+	case *healthz.Snapshot:
+		return appendSnapshot(nil, *v, nil)
+	case healthz.SnapshotPage:
+		return appendSnapshot(nil, v.Snapshot, &v.Total)
+	case *healthz.SnapshotPage:
+		return appendSnapshot(nil, v.Snapshot, &v.Total)
+	default:
+		return nil, fmt.Errorf("%w: %T", errUnsupported, v)
+	}
+}
+
+func (protobuf) Unmarshal(data []byte, v any) error {
+	switch v := v.(type) {
+	case *healthz.Snapshot:
+		*v = healthz.Snapshot{}
+
+		return consumeSnapshot(data, v, nil)
+	case *healthz.SnapshotPage:
+		*v = healthz.SnapshotPage{}
+
+		return consumeSnapshot(data, &v.Snapshot, &v.Total)
+	default:
+		return fmt.Errorf("%w: %T", errUnsupported, v)
+	}
+}
+
+// Field numbers of the schema.
+const (
+	snapshotTargets         = 1
+	snapshotCancelOffenders = 2
+	snapshotTotal           = 3
+	snapshotSeq             = 4
+	snapshotPublishedAt     = 5
+	snapshotSilences        = 6
+
+	targetScope     = 1
+	targetDest      = 2
+	targetGroups    = 3
+	targetStatus    = 4
+	targetError     = 5
+	targetDegraded  = 6
+	targetCheckedAt = 7
+	targetDuration  = 8
+	targetDetails   = 9
+
+	auditScope       = 1
+	auditDest        = 2
+	auditCancelledAt = 3
+	auditLag         = 4
+	auditRunning     = 5
+
+	silenceScope     = 1
+	silenceDest      = 2
+	silenceUntil     = 3
+	silenceReason    = 4
+	silenceCreatedAt = 5
+)
+
+// Values of the Status enum.
+var statuses = map[healthz.Status]uint64{
+	healthz.StatusHealthy:     1,
+	healthz.StatusDegraded:    2,
+	healthz.StatusUnhealthy:   3,
+	healthz.StatusMaintenance: 4,
+	healthz.StatusDisabled:    5,
+}
+
+func appendSnapshot(b []byte, s healthz.Snapshot, total *int) ([]byte, error) {
+	for _, tr := range s.Targets {
+		msg, err := appendTarget(nil, tr)
+		if err != nil {
+			return nil, err
+		}
+
+		b = appendMessage(b, snapshotTargets, msg)
+	}
+
+	for _, ca := range s.CancelOffenders {
+		msg, err := appendAudit(nil, ca)
+		if err != nil {
+			return nil, err
+		}
+
+		b = appendMessage(b, snapshotCancelOffenders, msg)
+	}
+
+	if total != nil {
+		b = protowire.AppendTag(b, snapshotTotal, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(*total))
+	}
+
+	b = appendVarint(b, snapshotSeq, s.Seq)
+
+	if !s.PublishedAt.IsZero() {
+		var err error
+
+		if b, err = appendProto(b, snapshotPublishedAt, timestamppb.New(s.PublishedAt)); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, silence := range s.Silences {
+		msg, err := appendSilence(nil, silence)
+		if err != nil {
+			return nil, err
+		}
+
+		b = appendMessage(b, snapshotSilences, msg)
+	}
+
+	return b, nil
+}
+
+func appendTarget(b []byte, tr healthz.TargetResult) ([]byte, error) {
+	b = appendString(b, targetScope, tr.Scope)
+	b = appendString(b, targetDest, tr.Dest)
+	b = appendVarint(b, targetGroups, uint64(tr.Groups))
+	b = appendVarint(b, targetStatus, statuses[tr.Status()])
+
+	if tr.Err != nil {
+		b = appendString(b, targetError, tr.Err.Error())
+	}
+
+	if tr.Degraded != nil {
+		b = appendString(b, targetDegraded, tr.Degraded.Error())
+	}
+
+	var err error
+
+	if !tr.CheckedAt.IsZero() {
+		if b, err = appendProto(b, targetCheckedAt, timestamppb.New(tr.CheckedAt)); err != nil {
+			return nil, err
+		}
+	}
+
+	if tr.Duration > 0 {
+		if b, err = appendProto(b, targetDuration, durationpb.New(tr.Duration)); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(tr.Details) > 0 {
+		details, err := structpb.NewStruct(tr.Details)
+		if err != nil {
+			return nil, fmt.Errorf("details of %s/%s: %w", tr.Scope, tr.Dest, err)
+		}
+
+		if b, err = appendProto(b, targetDetails, details); err != nil {
+			return nil, err
+		}
+	}
+
+	return b, nil
+}
+
+func appendAudit(b []byte, ca healthz.CancelAudit) ([]byte, error) {
+	b = appendString(b, auditScope, ca.Scope)
+	b = appendString(b, auditDest, ca.Dest)
+
+	b, err := appendProto(b, auditCancelledAt, timestamppb.New(ca.CancelledAt))
+	if err != nil {
+		return nil, err
+	}
+
+	if b, err = appendProto(b, auditLag, durationpb.New(ca.Lag)); err != nil {
+		return nil, err
+	}
+
+	if ca.Running {
+		b = appendVarint(b, auditRunning, 1)
+	}
+
+	return b, nil
+}
+
+func appendSilence(b []byte, silence healthz.Silence) ([]byte, error) {
+	b = appendString(b, silenceScope, silence.Scope)
+	b = appendString(b, silenceDest, silence.Dest)
+
+	b, err := appendProto(b, silenceUntil, timestamppb.New(silence.Until))
+	if err != nil {
+		return nil, err
+	}
+
+	b = appendString(b, silenceReason, silence.Reason)
+
+	if !silence.CreatedAt.IsZero() {
+		if b, err = appendProto(b, silenceCreatedAt, timestamppb.New(silence.CreatedAt)); err != nil {
+			return nil, err
+		}
+	}
+
+	return b, nil
+}
+
+func appendString(b []byte, num protowire.Number, s string) []byte {
+	if s == "" {
+		return b
+	}
+
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+
+	return protowire.AppendString(b, s)
+}
+
+func appendVarint(b []byte, num protowire.Number, v uint64) []byte {
+	if v == 0 {
+		return b
+	}
+
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+
+	return protowire.AppendVarint(b, v)
+}
+
+func appendMessage(b []byte, num protowire.Number, msg []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+
+	return protowire.AppendBytes(b, msg)
+}
+
+func appendProto(b []byte, num protowire.Number, m proto.Message) ([]byte, error) {
+	msg, err := proto.MarshalOptions{Deterministic: true}.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+
+	return appendMessage(b, num, msg), nil
+}
+
+// consumeFields - calls field for every field of the message, unknown ones are skipped by field.
+func consumeFields(data []byte, field func(num protowire.Number, typ protowire.Type, data []byte) (int, error)) error {
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return fmt.Errorf("%w: %w", errMalformed, protowire.ParseError(n))
+		}
+
+		data = data[n:]
+
+		n, err := field(num, typ, data)
+		if err != nil {
+			return err
+		}
+
+		if n < 0 {
+			return fmt.Errorf("%w: %w", errMalformed, protowire.ParseError(n))
+		}
+
+		data = data[n:]
+	}
+
+	return nil
+}
+
+func consumeSnapshot(data []byte, s *healthz.Snapshot, total *int) error {
+	return consumeFields(data, func(num protowire.Number, typ protowire.Type, data []byte) (int, error) {
+		switch {
+		case num == snapshotTargets && typ == protowire.BytesType:
+			msg, n := protowire.ConsumeBytes(data)
+			if n < 0 {
+				return n, nil
+			}
+
+			tr, err := consumeTarget(msg)
+			s.Targets = append(s.Targets, tr)
+
+			return n, err
+		case num == snapshotCancelOffenders && typ == protowire.BytesType:
+			msg, n := protowire.ConsumeBytes(data)
+			if n < 0 {
+				return n, nil
+			}
+
+			ca, err := consumeAudit(msg)
+			s.CancelOffenders = append(s.CancelOffenders, ca)
+
+			return n, err
+		case num == snapshotTotal && typ == protowire.VarintType:
+			v, n := protowire.ConsumeVarint(data)
+			if total != nil {
+				*total = int(v)
+			}
+
+			return n, nil
+		case num == snapshotSeq && typ == protowire.VarintType:
+			v, n := protowire.ConsumeVarint(data)
+			s.Seq = v
+
+			return n, nil
+		case num == snapshotPublishedAt && typ == protowire.BytesType:
+			msg, n := protowire.ConsumeBytes(data)
+			if n < 0 {
+				return n, nil
+			}
+
+			at, err := consumeTimestamp(msg)
+			s.PublishedAt = at
+
+			return n, err
+		case num == snapshotSilences && typ == protowire.BytesType:
+			msg, n := protowire.ConsumeBytes(data)
+			if n < 0 {
+				return n, nil
+			}
+
+			silence, err := consumeSilence(msg)
+			s.Silences = append(s.Silences, silence)
+
+			return n, err
+		default:
+			return protowire.ConsumeFieldValue(num, typ, data), nil
+		}
+	})
+}
+
+func consumeTarget(data []byte) (healthz.TargetResult, error) {
+	var (
+		tr     healthz.TargetResult
+		status uint64
+	)
+
+	err := consumeFields(data, func(num protowire.Number, typ protowire.Type, data []byte) (int, error) {
+		switch {
+		case typ == protowire.VarintType && (num == targetGroups || num == targetStatus):
+			v, n := protowire.ConsumeVarint(data)
+			if num == targetGroups {
+				tr.Groups = healthz.ProbeGroup(v)
+			} else {
+				status = v
+			}
+
+			return n, nil
+		case typ != protowire.BytesType:
+			return protowire.ConsumeFieldValue(num, typ, data), nil
+		}
+
+		v, n := protowire.ConsumeBytes(data)
+		if n < 0 {
+			return n, nil
+		}
+
+		switch num {
+		case targetScope:
+			tr.Scope = string(v)
+		case targetDest:
+			tr.Dest = string(v)
+		case targetError:
+			tr.Err = errors.New(string(v))
+		case targetDegraded:
+			tr.Degraded = healthz.Degraded(errors.New(string(v)))
+		case targetCheckedAt:
+			at, err := consumeTimestamp(v)
+			tr.CheckedAt = at
+
+			return n, err
+		case targetDuration:
+			d, err := consumeDuration(v)
+			tr.Duration = d
+
+			return n, err
+		case targetDetails:
+			var details structpb.Struct
+			if err := proto.Unmarshal(v, &details); err != nil {
+				return n, err
+			}
+
+			tr.Details = details.AsMap()
+		}
+
+		return n, nil
+	})
+
+	if status == statuses[healthz.StatusUnhealthy] && tr.Err == nil {
+		tr.Err = errors.New("unhealthy")
+	}
+
+	return tr, err
+}
+
+func consumeAudit(data []byte) (healthz.CancelAudit, error) {
+	var ca healthz.CancelAudit
+
+	err := consumeFields(data, func(num protowire.Number, typ protowire.Type, data []byte) (int, error) {
+		switch {
+		case num == auditRunning && typ == protowire.VarintType:
+			v, n := protowire.ConsumeVarint(data)
+			ca.Running = v != 0
+
+			return n, nil
+		case typ != protowire.BytesType:
+			return protowire.ConsumeFieldValue(num, typ, data), nil
+		}
+
+		v, n := protowire.ConsumeBytes(data)
+		if n < 0 {
+			return n, nil
+		}
+
+		var err error
+
+		switch num {
+		case auditScope:
+			ca.Scope = string(v)
+		case auditDest:
+			ca.Dest = string(v)
+		case auditCancelledAt:
+			ca.CancelledAt, err = consumeTimestamp(v)
+		case auditLag:
+			ca.Lag, err = consumeDuration(v)
+		}
+
+		return n, err
+	})
+
+	return ca, err
+}
+
+func consumeSilence(data []byte) (healthz.Silence, error) {
+	var silence healthz.Silence
+
+	err := consumeFields(data, func(num protowire.Number, typ protowire.Type, data []byte) (int, error) {
+		if typ != protowire.BytesType {
+			return protowire.ConsumeFieldValue(num, typ, data), nil
+		}
+
+		v, n := protowire.ConsumeBytes(data)
+		if n < 0 {
+			return n, nil
+		}
+
+		var err error
+
+		switch num {
+		case silenceScope:
+			silence.Scope = string(v)
+		case silenceDest:
+			silence.Dest = string(v)
+		case silenceUntil:
+			silence.Until, err = consumeTimestamp(v)
+		case silenceReason:
+			silence.Reason = string(v)
+		case silenceCreatedAt:
+			silence.CreatedAt, err = consumeTimestamp(v)
+		}
+
+		return n, err
+	})
+
+	return silence, err
+}
+
+func consumeTimestamp(data []byte) (time.Time, error) {
+	var ts timestamppb.Timestamp
+	if err := proto.Unmarshal(data, &ts); err != nil {
+		return time.Time{}, err
+	}
+
+	return ts.AsTime(), nil
+}
+
+func consumeDuration(data []byte) (time.Duration, error) {
+	var d durationpb.Duration
+	if err := proto.Unmarshal(data, &d); err != nil {
+		return 0, err
+	}
+
+	return d.AsDuration(), nil
+}
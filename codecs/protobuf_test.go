@@ -0,0 +1,132 @@
+package codecs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/art-frela/healthz"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestProtobuf(t *testing.T) {
+	at := time.Date(2026, 10, 16, 12, 0, 0, 500, time.UTC)
+
+	snapshot := healthz.Snapshot{
+		Seq:         7,
+		PublishedAt: at,
+		Targets: []healthz.TargetResult{
+			{
+				Scope: "db", Dest: "pg", Groups: healthz.GroupLive | healthz.GroupReady,
+				Err: errors.New("conn refused"), CheckedAt: at, Duration: 15 * time.Millisecond,
+				Details: map[string]any{"lag": 1.5, "role": "replica"},
+			},
+			{Scope: "db", Dest: "replica", Groups: healthz.GroupReady, Degraded: healthz.Degraded(errors.New("read-only"))},
+			{Scope: "cache", Dest: "redis", Groups: healthz.GroupLive},
+		},
+		CancelOffenders: []healthz.CancelAudit{{Scope: "db", Dest: "pg", CancelledAt: at, Lag: time.Second, Running: true}},
+		Silences: []healthz.Silence{
+			{Scope: "db", Dest: "pg", Until: at.Add(time.Hour), Reason: "failover drill", CreatedAt: at},
+			{Scope: "cache", Dest: "redis", Until: at.Add(time.Minute)},
+		},
+	}
+
+	data, err := Protobuf.Marshal(snapshot)
+	assert.NoError(t, err)
+
+	// unknown fields of newer schemas are skipped
+	data = protowire.AppendTag(data, 100, protowire.BytesType)
+	data = protowire.AppendString(data, "future")
+
+	var got healthz.Snapshot
+
+	assert.NoError(t, Protobuf.Unmarshal(data, &got))
+	assert.Equal(t, uint64(7), got.Seq)
+	assert.True(t, at.Equal(got.PublishedAt))
+
+	if assert.Len(t, got.Targets, 3) {
+		pg := got.Targets[0]
+		assert.Equal(t, "db", pg.Scope)
+		assert.Equal(t, healthz.GroupLive|healthz.GroupReady, pg.Groups)
+		assert.EqualError(t, pg.Err, "conn refused")
+		assert.True(t, at.Equal(pg.CheckedAt))
+		assert.Equal(t, 15*time.Millisecond, pg.Duration)
+		assert.Equal(t, map[string]any{"lag": 1.5, "role": "replica"}, pg.Details)
+
+		assert.Equal(t, healthz.StatusDegraded, got.Targets[1].Status())
+		assert.EqualError(t, got.Targets[1].Degraded, "read-only")
+		assert.Equal(t, healthz.StatusHealthy, got.Targets[2].Status())
+	}
+
+	if assert.Len(t, got.CancelOffenders, 1) {
+		assert.Equal(t, time.Second, got.CancelOffenders[0].Lag)
+		assert.True(t, got.CancelOffenders[0].Running)
+	}
+
+	if assert.Len(t, got.Silences, 2) {
+		drill := got.Silences[0]
+		assert.Equal(t, "db", drill.Scope)
+		assert.Equal(t, "pg", drill.Dest)
+		assert.True(t, at.Add(time.Hour).Equal(drill.Until))
+		assert.Equal(t, "failover drill", drill.Reason)
+		assert.True(t, at.Equal(drill.CreatedAt))
+
+		assert.Equal(t, "redis", got.Silences[1].Dest)
+		assert.Empty(t, got.Silences[1].Reason)
+		assert.True(t, got.Silences[1].CreatedAt.IsZero())
+	}
+
+	var page healthz.SnapshotPage
+
+	data, err = Protobuf.Marshal(healthz.SnapshotPage{Snapshot: snapshot, Total: 42})
+	assert.NoError(t, err)
+	assert.NoError(t, Protobuf.Unmarshal(data, &page))
+	assert.Equal(t, 42, page.Total)
+	assert.Len(t, page.Targets, 3)
+
+	_, err = Protobuf.Marshal([]healthz.ScopeSummary{})
+	assert.ErrorIs(t, err, errUnsupported)
+	assert.ErrorIs(t, Protobuf.Unmarshal(data, &struct{}{}), errUnsupported)
+	assert.ErrorIs(t, Protobuf.Unmarshal([]byte{0x0a, 0x05, 0x01}, &got), errMalformed)
+}
+
+func TestProtobuf_statusHandler(t *testing.T) {
+	assert.NoError(t, healthz.RegisterCodec(Protobuf))
+
+	inspector := healthz.New(healthz.HealthCheckTarget{Service: service{err: errors.New("down")}, Groups: healthz.GroupReady})
+	assert.NoError(t, inspector.Start(context.Background()))
+	defer inspector.Stop(context.Background())
+
+	assert.Eventually(t, func() bool { return len(inspector.Snapshot().Targets) > 0 }, time.Second, time.Millisecond)
+
+	srv := httptest.NewServer(inspector.StatusHandler())
+	defer srv.Close()
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"?status=fail", nil)
+	assert.NoError(t, err)
+	req.Header.Set("Accept", "application/x-protobuf")
+
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+
+	defer resp.Body.Close()
+
+	assert.Equal(t, "application/x-protobuf", resp.Header.Get("Content-Type"))
+
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+
+	var page healthz.SnapshotPage
+
+	assert.NoError(t, Protobuf.Unmarshal(body, &page))
+	assert.Equal(t, 1, page.Total)
+
+	if assert.Len(t, page.Targets, 1) {
+		assert.EqualError(t, page.Targets[0].Err, "down")
+	}
+}
@@ -0,0 +1,114 @@
+package healthz
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// TargetChange - target present in both snapshots with different state.
+type TargetChange struct {
+	Scope      string
+	Dest       string
+	WasHealthy bool
+	Healthy    bool
+	Err        error // error of the later snapshot
+}
+
+func (tc TargetChange) MarshalJSON() ([]byte, error) {
+	view := struct {
+		Scope      string `json:"scope"`
+		Dest       string `json:"dest"`
+		WasHealthy bool   `json:"wasHealthy"`
+		Healthy    bool   `json:"healthy"`
+		Error      string `json:"error,omitempty"`
+	}{
+		Scope:      tc.Scope,
+		Dest:       tc.Dest,
+		WasHealthy: tc.WasHealthy,
+		Healthy:    tc.Healthy,
+	}
+
+	if tc.Err != nil {
+		view.Error = tc.Err.Error()
+	}
+
+	return json.Marshal(view)
+}
+
+// Diff - structured difference of two snapshots, lists are sorted by scope and dest.
+type Diff struct {
+	Changed []TargetChange `json:"changed,omitempty"`
+	Added   []TargetResult `json:"added,omitempty"`   // targets of the later snapshot only
+	Removed []TargetResult `json:"removed,omitempty"` // targets of the earlier snapshot only
+}
+
+// Empty - reports whether the snapshots have the same targets in the same state.
+func (d Diff) Empty() bool {
+	return len(d.Changed) == 0 && len(d.Added) == 0 && len(d.Removed) == 0
+}
+
+// Regressions - targets which became unhealthy.
+func (d Diff) Regressions() []TargetChange {
+	var list []TargetChange
+
+	for _, tc := range d.Changed {
+		if tc.WasHealthy && !tc.Healthy {
+			list = append(list, tc)
+		}
+	}
+
+	return list
+}
+
+// Compare - difference of the before and after snapshots (e.g. pre/post rollout) for canary analysis.
+func Compare(before, after Snapshot) Diff {
+	prev := make(map[string]TargetResult, len(before.Targets))
+
+	for _, tr := range before.Targets {
+		prev[targetKey(tr.Scope, tr.Dest)] = tr
+	}
+
+	var diff Diff
+
+	seen := make(map[string]bool, len(after.Targets))
+
+	for _, tr := range after.Targets {
+		key := targetKey(tr.Scope, tr.Dest)
+		seen[key] = true
+
+		was, ok := prev[key]
+
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, tr)
+		case was.Healthy() != tr.Healthy():
+			diff.Changed = append(diff.Changed, TargetChange{
+				Scope:      tr.Scope,
+				Dest:       tr.Dest,
+				WasHealthy: was.Healthy(),
+				Healthy:    tr.Healthy(),
+				Err:        tr.Err,
+			})
+		}
+	}
+
+	for _, tr := range before.Targets {
+		if !seen[targetKey(tr.Scope, tr.Dest)] {
+			diff.Removed = append(diff.Removed, tr)
+		}
+	}
+
+	sort.Slice(diff.Changed, func(a, b int) bool {
+		return targetKey(diff.Changed[a].Scope, diff.Changed[a].Dest) < targetKey(diff.Changed[b].Scope, diff.Changed[b].Dest)
+	})
+	sortTargets(diff.Added)
+	sortTargets(diff.Removed)
+
+	return diff
+}
+
+func sortTargets(list []TargetResult) {
+	sort.Slice(list, func(a, b int) bool {
+		return targetKey(list[a].Scope, list[a].Dest) < targetKey(list[b].Scope, list[b].Dest)
+	})
+}
@@ -0,0 +1,59 @@
+package healthz
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompare(t *testing.T) {
+	errDown := errors.New("down")
+
+	before := Snapshot{Targets: []TargetResult{
+		{Scope: "db", Dest: "pg"},
+		{Scope: "cache", Dest: "redis", Err: errDown},
+		{Scope: "queue", Dest: "kafka"},
+		{Scope: "legacy", Dest: "soap"},
+	}}
+	after := Snapshot{Targets: []TargetResult{
+		{Scope: "db", Dest: "pg", Err: errDown},
+		{Scope: "cache", Dest: "redis"},
+		{Scope: "queue", Dest: "kafka"},
+		{Scope: "search", Dest: "es"},
+	}}
+
+	diff := Compare(before, after)
+
+	assert.False(t, diff.Empty())
+	assert.Equal(t, []TargetChange{
+		{Scope: "cache", Dest: "redis", WasHealthy: false, Healthy: true},
+		{Scope: "db", Dest: "pg", WasHealthy: true, Healthy: false, Err: errDown},
+	}, diff.Changed)
+	assert.Equal(t, []TargetChange{{Scope: "db", Dest: "pg", WasHealthy: true, Healthy: false, Err: errDown}}, diff.Regressions())
+	assert.Equal(t, []TargetResult{{Scope: "search", Dest: "es"}}, diff.Added)
+	assert.Equal(t, []TargetResult{{Scope: "legacy", Dest: "soap"}}, diff.Removed)
+
+	body, err := json.Marshal(diff)
+	assert.NoError(t, err)
+	assert.Contains(t, string(body), `{"scope":"db","dest":"pg","wasHealthy":true,"healthy":false,"error":"down"}`)
+
+	assert.True(t, Compare(after, after).Empty())
+}
+
+func TestCompare_servedSnapshots(t *testing.T) {
+	served := []byte(`{"targets":[
+		{"scope":"db","dest":"pg","groups":8,"healthy":false,"error":"conn refused"},
+		{"scope":"cache","dest":"redis","groups":4,"healthy":true}
+	]}`)
+
+	var after Snapshot
+	assert.NoError(t, json.Unmarshal(served, &after))
+
+	before := Snapshot{Targets: []TargetResult{{Scope: "db", Dest: "pg", Groups: GroupReady}, {Scope: "cache", Dest: "redis", Groups: GroupLive}}}
+
+	regressions := Compare(before, after).Regressions()
+	assert.Len(t, regressions, 1)
+	assert.EqualError(t, regressions[0].Err, "conn refused")
+}
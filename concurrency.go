@@ -0,0 +1,88 @@
+package healthz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var errWrongConcurrency = errors.New("incorrect concurrency limit")
+
+// WithMaxConcurrency - limits simultaneous checks of the cycle, so hundreds of targets
+// don't overwhelm connection pools and the dependencies themselves. Checks are started
+// round-robin across the scopes (see fairOrder), time waited is observed by WithQueueWaitMetric.
+func WithMaxConcurrency(n int) Option {
+	return func(i *Inspector) error {
+		if n <= 0 {
+			return errWrongConcurrency
+		}
+
+		i.maxChecks = n
+
+		return nil
+	}
+}
+
+// WithScopeConcurrency - limits simultaneous checks of targets with the scope,
+// so one slow class of dependencies can't delay checks of everything else.
+func WithScopeConcurrency(scope string, n int) Option {
+	return func(i *Inspector) error {
+		if n <= 0 {
+			return errWrongConcurrency
+		}
+
+		if i.scopeSlots == nil {
+			i.scopeSlots = make(map[string]chan struct{})
+		}
+
+		i.scopeSlots[scope] = make(chan struct{}, n)
+
+		return nil
+	}
+}
+
+// acquireScope - takes a slot of the scope limit, returned func releases it.
+func (i *Inspector) acquireScope(ctx context.Context, scope string) (func(), error) {
+	slots, ok := i.scopeSlots[scope]
+	if !ok {
+		return func() {}, nil
+	}
+
+	select {
+	case slots <- struct{}{}:
+		return func() { <-slots }, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("wait for %q scope slot: %w", scope, ctx.Err())
+	}
+}
+
+// fairOrder - due targets (indexes of targets) in round-robin order across the scopes, so a scope
+// with many or slow targets can't hold all slots of WithMaxConcurrency while other scopes wait.
+// Scopes take turns in order of their first target, targets of the scope keep their order.
+func fairOrder(targets []HealthCheckTarget, due []int) []int {
+	var scopes []string
+
+	queues := make(map[string][]int)
+
+	for _, idx := range due {
+		scope := targets[idx].Service.Scope()
+		if _, ok := queues[scope]; !ok {
+			scopes = append(scopes, scope)
+		}
+
+		queues[scope] = append(queues[scope], idx)
+	}
+
+	order := make([]int, 0, len(due))
+
+	for len(order) < len(due) {
+		for _, scope := range scopes {
+			if queue := queues[scope]; len(queue) > 0 {
+				order = append(order, queue[0])
+				queues[scope] = queue[1:]
+			}
+		}
+	}
+
+	return order
+}
@@ -0,0 +1,217 @@
+package healthz
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithScopeConcurrency(t *testing.T) {
+	assert.Error(t, WithScopeConcurrency("database", 0)(New()))
+
+	var running, maxRunning atomic.Int32
+
+	callBack := func() {
+		n := running.Add(1)
+		defer running.Add(-1)
+
+		for {
+			m := maxRunning.Load()
+			if n <= m || maxRunning.CompareAndSwap(m, n) {
+				break
+			}
+		}
+
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	var targets []HealthCheckTarget
+	for range 6 {
+		targets = append(targets, HealthCheckTarget{
+			Service: &mockService{scope: "database", callBack: callBack},
+			Groups:  GroupReady,
+		})
+	}
+
+	var cacheChecked atomic.Bool
+
+	targets = append(targets, HealthCheckTarget{
+		Service: &mockService{scope: "cache", callBack: func() { cacheChecked.Store(true) }},
+		Groups:  GroupReady,
+	})
+
+	inspector := New(targets...)
+	assert.NoError(t, WithScopeConcurrency("database", 2)(inspector))
+
+	inspector.check(context.Background())
+
+	assert.LessOrEqual(t, maxRunning.Load(), int32(2))
+	assert.True(t, cacheChecked.Load())
+	assert.NoError(t, inspector.CheckGroup(GroupReady, true))
+}
+
+func TestWithMaxConcurrency(t *testing.T) {
+	assert.Error(t, WithMaxConcurrency(0)(New()))
+
+	var running, maxRunning atomic.Int32
+
+	callBack := func() {
+		n := running.Add(1)
+		defer running.Add(-1)
+
+		for {
+			m := maxRunning.Load()
+			if n <= m || maxRunning.CompareAndSwap(m, n) {
+				break
+			}
+		}
+
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	var targets []HealthCheckTarget
+	for idx := range 8 {
+		targets = append(targets, HealthCheckTarget{
+			Service: &mockService{scope: "scope", dest: string(rune('a' + idx)), callBack: callBack},
+			Groups:  GroupReady,
+		})
+	}
+
+	inspector := New(targets...)
+	assert.NoError(t, WithMaxConcurrency(3)(inspector))
+
+	inspector.check(context.Background())
+
+	assert.Equal(t, int32(3), maxRunning.Load())
+	assert.NoError(t, inspector.CheckGroup(GroupReady, true))
+}
+
+func TestWithMaxConcurrency_cycleTimeout(t *testing.T) {
+	var targets []HealthCheckTarget
+	for idx := range 4 {
+		targets = append(targets, HealthCheckTarget{
+			Service: &mockService{scope: "scope", dest: string(rune('a' + idx)), callBack: func() { time.Sleep(50 * time.Millisecond) }},
+			Groups:  GroupReady,
+		})
+	}
+
+	inspector := New(targets...)
+	assert.NoError(t, WithMaxConcurrency(1)(inspector))
+	assert.NoError(t, WithCycleTimeout(20*time.Millisecond)(inspector))
+
+	start := time.Now()
+	inspector.check(context.Background())
+
+	assert.Less(t, time.Since(start), 45*time.Millisecond, "the cycle deadline isn't blocked by the limit")
+	assert.ErrorIs(t, inspector.CheckGroup(GroupReady, true), errCycleTimeout)
+}
+
+func TestAcquireScope_canceled(t *testing.T) {
+	inspector := New()
+	assert.NoError(t, WithScopeConcurrency("database", 1)(inspector))
+
+	release, err := inspector.acquireScope(context.Background(), "database")
+	assert.NoError(t, err)
+	defer release()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = inspector.acquireScope(ctx, "database")
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestFairOrder(t *testing.T) {
+	target := func(scope, dest string) HealthCheckTarget {
+		return HealthCheckTarget{Service: &mockService{scope: scope, dest: dest}}
+	}
+
+	targets := []HealthCheckTarget{
+		target("s3", "a"), target("s3", "b"), target("s3", "c"), target("cache", "x"), target("db", "y"), target("cache", "z"),
+	}
+
+	tests := []struct {
+		name string
+		due  []int
+		want []int
+	}{
+		{name: "test.1 round-robin", due: []int{0, 1, 2, 3, 4, 5}, want: []int{0, 3, 4, 1, 5, 2}},
+		{name: "test.2 single scope", due: []int{0, 2}, want: []int{0, 2}},
+		{name: "test.3 partly due", due: []int{1, 2, 5}, want: []int{1, 5, 2}},
+		{name: "test.4 nothing due", due: []int{}, want: []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, fairOrder(targets, tt.due))
+		})
+	}
+}
+
+func TestWithMaxConcurrency_fairness(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		checked []string
+	)
+
+	service := func(scope, dest string, delay time.Duration) *mockService {
+		return &mockService{scope: scope, dest: dest, callBack: func() {
+			mu.Lock()
+			checked = append(checked, dest)
+			mu.Unlock()
+
+			time.Sleep(delay)
+		}}
+	}
+
+	inspector := New(
+		HealthCheckTarget{Service: service("s3", "a", 10*time.Millisecond), Groups: GroupReady},
+		HealthCheckTarget{Service: service("s3", "b", 10*time.Millisecond), Groups: GroupReady},
+		HealthCheckTarget{Service: service("s3", "c", 10*time.Millisecond), Groups: GroupReady},
+		HealthCheckTarget{Service: service("cache", "x", 0), Groups: GroupReady},
+	)
+	assert.NoError(t, WithMaxConcurrency(1)(inspector))
+
+	inspector.check(context.Background())
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	assert.Equal(t, []string{"a", "x", "b", "c"}, checked, "slow scope doesn't starve the fast one")
+}
+
+func TestWithScopeConcurrency_saturatedScope(t *testing.T) {
+	var cacheChecked atomic.Bool
+
+	inspector := New(
+		HealthCheckTarget{Service: &mockService{scope: "database", dest: "a"}, Groups: GroupReady},
+		HealthCheckTarget{Service: &mockService{scope: "database", dest: "b"}, Groups: GroupReady},
+		HealthCheckTarget{Service: &mockService{scope: "cache", dest: "x", callBack: func() { cacheChecked.Store(true) }},
+			Groups: GroupReady},
+	)
+	assert.NoError(t, WithScopeConcurrency("database", 1)(inspector))
+	assert.NoError(t, WithMaxConcurrency(1)(inspector))
+
+	// the database scope is saturated until the cache target is checked
+	release, err := inspector.acquireScope(context.Background(), "database")
+	assert.NoError(t, err)
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		inspector.check(context.Background())
+	}()
+
+	assert.Eventually(t, cacheChecked.Load, time.Second, time.Millisecond, "waiting scope holds the global slot")
+
+	release()
+	<-done
+
+	assert.NoError(t, inspector.CheckGroup(GroupReady, true))
+}
@@ -0,0 +1,335 @@
+package healthz
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"reflect"
+	"sync"
+	"syscall"
+	"time"
+)
+
+var (
+	errUnknownTargetType = errors.New("unknown target type")
+	errUnknownGroupName  = errors.New("unknown group name")
+	errWrongFactory      = errors.New("factory must have type and func")
+)
+
+// Duration - time.Duration unmarshaled from strings like "15s".
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string like \"15s\": %w", err)
+	}
+
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+
+	*d = Duration(v)
+
+	return nil
+}
+
+// Config - declarative settings of the inspector, e.g. loaded from a JSON file.
+// The config holds all of them: an omitted (zero) setting means its default, settings given
+// by options included, so removing one from the file resets it on reload.
+type Config struct {
+	CheckPeriod   Duration                    `json:"checkPeriod,omitempty"`
+	CycleTimeout  Duration                    `json:"cycleTimeout,omitempty"`
+	CheckTimeout  Duration                    `json:"checkTimeout,omitempty"`
+	ShutdownDelay Duration                    `json:"shutdownDelay,omitempty"`
+	Hysteresis    map[string]HysteresisConfig `json:"hysteresis,omitempty"` // by group name: startup, live, ready
+	Targets       []TargetConfig              `json:"targets,omitempty"`
+}
+
+// HysteresisConfig - see WithHysteresis.
+type HysteresisConfig struct {
+	Down Duration `json:"down"`
+	Up   Duration `json:"up"`
+}
+
+// TargetConfig - declarative target built by the factory registered for its type.
+type TargetConfig struct {
+	Type   string            `json:"type"`
+	Scope  string            `json:"scope"`
+	Dest   string            `json:"dest"`
+	Groups []string          `json:"groups"` // common, startup, live, ready
+	Params map[string]string `json:"params,omitempty"`
+	// dependencies of the target as "scope/dest", see HealthCheckTarget.DependsOn
+	DependsOn []string `json:"dependsOn,omitempty"`
+	Timeout   Duration `json:"timeout,omitempty"` // see HealthCheckTarget.Timeout
+	Period    Duration `json:"period,omitempty"`  // see HealthCheckTarget.Period
+	// see HealthCheckTarget.FailureThreshold, SuccessThreshold
+	FailureThreshold int `json:"failureThreshold,omitempty"`
+	SuccessThreshold int `json:"successThreshold,omitempty"`
+	// see HealthCheckTarget.Labels
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// TargetFactory - builds the service of declarative targets of one type.
+type TargetFactory func(tc TargetConfig) (HealthCheckable, error)
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = map[string]TargetFactory{
+		"external": externalFactory,
+	}
+)
+
+// RegisterTargetFactory - makes targets of the type available in Config.
+func RegisterTargetFactory(typ string, factory TargetFactory) error {
+	if typ == "" || factory == nil {
+		return errWrongFactory
+	}
+
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+
+	factories[typ] = factory
+
+	return nil
+}
+
+// externalFactory - target reported by external systems, params: ttl.
+func externalFactory(tc TargetConfig) (HealthCheckable, error) {
+	ttl, err := time.ParseDuration(tc.Params["ttl"])
+	if err != nil || ttl <= 0 {
+		return nil, fmt.Errorf("%w: %q", errWrongTTL, tc.Params["ttl"])
+	}
+
+	return &externalTarget{scope: tc.Scope, dest: tc.Dest, ttl: ttl}, nil
+}
+
+// ParseGroups - mask of the groups given by names.
+func ParseGroups(names []string) (ProbeGroup, error) {
+	var pg ProbeGroup
+
+	for _, name := range names {
+		g, ok := groupByName(name)
+		if !ok {
+			return 0, fmt.Errorf("%w: %q", errUnknownGroupName, name)
+		}
+
+		pg |= g
+	}
+
+	return pg, pg.validate()
+}
+
+// LoadConfig - reads JSON config file.
+func LoadConfig(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("read config: %w", err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	var cfg Config
+
+	if err := dec.Decode(&cfg); err != nil {
+		return Config{}, fmt.Errorf("decode config %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+// ApplyConfig - applies the config, could be called while the inspector runs.
+// Declarative targets are replaced (unchanged ones keep their state, metric series of removed ones
+// are deleted), programmatic ones stay. Omitted settings are reset to defaults (see Config).
+// A new check period applies at once. Nothing is applied if the config is invalid.
+func (i *Inspector) ApplyConfig(cfg Config) error {
+	if cfg.CheckPeriod < 0 {
+		return errWrongCheckPeriod
+	}
+
+	if cfg.CycleTimeout < 0 || cfg.CheckTimeout < 0 {
+		return errWrongTimeout
+	}
+
+	if cfg.ShutdownDelay < 0 {
+		return errWrongShutdownDelay
+	}
+
+	hyst := make(map[ProbeGroup]hysteresis, len(cfg.Hysteresis))
+
+	for name, hc := range cfg.Hysteresis {
+		g, err := ParseGroups([]string{name})
+		if err != nil {
+			return err
+		}
+
+		if hc.Down < 0 || hc.Up < 0 {
+			return errWrongHysteresis
+		}
+
+		hyst[g] = hysteresis{down: time.Duration(hc.Down), up: time.Duration(hc.Up)}
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	declared, err := i.buildDeclared(cfg.Targets)
+	if err != nil {
+		return err
+	}
+
+	targets, dropped, err := replaceTargets(i.targets, HealthCheckTarget.declared, declared)
+	if err != nil {
+		return err
+	}
+
+	for _, target := range dropped {
+		i.deleteTargetSeries(target.Service.Scope(), target.Service.Dest())
+	}
+
+	i.targets = targets
+	i.checkPeriod = defCheckPeriod
+	i.cycleTimeout = time.Duration(cfg.CycleTimeout)
+	i.checkTimeout = time.Duration(cfg.CheckTimeout)
+	i.shutdownDelay = time.Duration(cfg.ShutdownDelay)
+	i.hysteresis = nil
+
+	if cfg.CheckPeriod > 0 {
+		i.checkPeriod = time.Duration(cfg.CheckPeriod)
+	}
+
+	if len(hyst) > 0 {
+		i.hysteresis = hyst
+	}
+
+	i.reloaded()
+
+	return nil
+}
+
+// buildDeclared - targets of the config, reusing current ones with the same config, i.mu must be held.
+func (i *Inspector) buildDeclared(list []TargetConfig) ([]HealthCheckTarget, error) {
+	targets := make([]HealthCheckTarget, 0, len(list))
+
+	for n := range list {
+		tc := list[n]
+
+		if cur, ok := i.declaredTarget(tc); ok {
+			targets = append(targets, cur)
+
+			continue
+		}
+
+		groups, err := ParseGroups(tc.Groups)
+		if err != nil {
+			return nil, fmt.Errorf("target %s/%s: %w", tc.Scope, tc.Dest, err)
+		}
+
+		factoriesMu.RLock()
+		factory, ok := factories[tc.Type]
+		factoriesMu.RUnlock()
+
+		if !ok {
+			return nil, fmt.Errorf("target %s/%s: %w: %q", tc.Scope, tc.Dest, errUnknownTargetType, tc.Type)
+		}
+
+		svc, err := factory(tc)
+		if err != nil {
+			return nil, fmt.Errorf("target %s/%s: %w", tc.Scope, tc.Dest, err)
+		}
+
+		targets = append(targets, HealthCheckTarget{
+			Service:   svc,
+			Groups:    groups,
+			DependsOn: tc.DependsOn,
+			Timeout:   time.Duration(tc.Timeout),
+			Period:    time.Duration(tc.Period),
+
+			FailureThreshold: tc.FailureThreshold,
+			SuccessThreshold: tc.SuccessThreshold,
+			Labels:           tc.Labels,
+
+			config: &tc,
+		})
+	}
+
+	return targets, nil
+}
+
+func (i *Inspector) declaredTarget(tc TargetConfig) (HealthCheckTarget, bool) {
+	for _, target := range i.targets {
+		if target.config != nil && reflect.DeepEqual(*target.config, tc) {
+			return target, true
+		}
+	}
+
+	return HealthCheckTarget{}, false
+}
+
+// WatchConfig - loads and applies the config file, then reloads it on SIGHUP or when
+// the file is modified (checked every interval) until ctx is done.
+// onReload (could be nil) gets the result of every reload.
+func (i *Inspector) WatchConfig(ctx context.Context, path string, interval time.Duration, onReload func(error)) error {
+	if interval <= 0 {
+		return errWrongCheckPeriod
+	}
+
+	modTime, err := i.reloadConfig(path)
+	if err != nil {
+		return err
+	}
+
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+	defer signal.Stop(sighup)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-sighup:
+		case <-ticker.C:
+			info, err := os.Stat(path)
+			if err != nil || info.ModTime().Equal(modTime) {
+				continue
+			}
+		}
+
+		mt, err := i.reloadConfig(path)
+		if !mt.IsZero() {
+			modTime = mt
+		}
+
+		if onReload != nil {
+			onReload(err)
+		}
+	}
+}
+
+func (i *Inspector) reloadConfig(path string) (time.Time, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("stat config: %w", err)
+	}
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return info.ModTime(), i.ApplyConfig(cfg)
+}
@@ -0,0 +1,234 @@
+package healthz
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseGroups(t *testing.T) {
+	g, err := ParseGroups([]string{"live", "ready"})
+	assert.NoError(t, err)
+	assert.Equal(t, GroupLive|GroupReady, g)
+
+	_, err = ParseGroups([]string{"alive"})
+	assert.ErrorIs(t, err, errUnknownGroupName)
+
+	_, err = ParseGroups(nil)
+	assert.ErrorIs(t, err, errEmptyGroup)
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "healthz.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`{
+		"checkPeriod": "5s",
+		"hysteresis": {"ready": {"down": "5s", "up": "30s"}},
+		"targets": [{"type": "external", "scope": "cron", "dest": "backup", "groups": ["ready"], "params": {"ttl": "1h"}}]
+	}`), 0o600))
+
+	cfg, err := LoadConfig(path)
+	assert.NoError(t, err)
+	assert.Equal(t, Duration(5*time.Second), cfg.CheckPeriod)
+	assert.Equal(t, HysteresisConfig{Down: Duration(5 * time.Second), Up: Duration(30 * time.Second)}, cfg.Hysteresis["ready"])
+	assert.Len(t, cfg.Targets, 1)
+
+	bad := filepath.Join(dir, "bad.json")
+	assert.NoError(t, os.WriteFile(bad, []byte(`{"checkPeriods": "5s"}`), 0o600))
+
+	_, err = LoadConfig(bad)
+	assert.Error(t, err)
+}
+
+func TestApplyConfig(t *testing.T) {
+	static := HealthCheckTarget{Service: &mockService{scope: "db", dest: "pg"}, Groups: GroupReady}
+	inspector := New(static)
+
+	backup := TargetConfig{Type: "external", Scope: "cron", Dest: "backup", Groups: []string{"ready"}, Params: map[string]string{"ttl": "1h"}}
+	report := TargetConfig{Type: "external", Scope: "cron", Dest: "report", Groups: []string{"live"}, Params: map[string]string{"ttl": "1h"}}
+
+	err := inspector.ApplyConfig(Config{
+		CheckPeriod:  Duration(time.Second),
+		CycleTimeout: Duration(time.Millisecond * 500),
+		Targets:      []TargetConfig{backup, report},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, inspector.targets, 3)
+	assert.Equal(t, time.Second, inspector.checkPeriod)
+	assert.Equal(t, time.Millisecond*500, inspector.cycleTimeout)
+
+	backupSvc := inspector.targets[1].Service
+
+	// report removed, backup unchanged, settings reset to defaults
+	assert.NoError(t, inspector.ApplyConfig(Config{Targets: []TargetConfig{backup}}))
+	assert.Len(t, inspector.targets, 2)
+	assert.Same(t, static.Service, inspector.targets[0].Service)
+	assert.Same(t, backupSvc, inspector.targets[1].Service, "unchanged target keeps its state")
+	assert.Equal(t, defCheckPeriod, inspector.checkPeriod)
+	assert.Zero(t, inspector.cycleTimeout)
+
+	t.Run("Invalid config isn't applied", func(t *testing.T) {
+		err := inspector.ApplyConfig(Config{Targets: []TargetConfig{{Type: "unknown", Groups: []string{"live"}}}})
+		assert.ErrorIs(t, err, errUnknownTargetType)
+
+		err = inspector.ApplyConfig(Config{Hysteresis: map[string]HysteresisConfig{"alive": {}}})
+		assert.ErrorIs(t, err, errUnknownGroupName)
+
+		err = inspector.ApplyConfig(Config{CycleTimeout: Duration(-time.Second)})
+		assert.ErrorIs(t, err, errWrongTimeout)
+
+		err = inspector.ApplyConfig(Config{CheckPeriod: Duration(-time.Second)})
+		assert.ErrorIs(t, err, errWrongCheckPeriod)
+
+		pg := TargetConfig{Type: "external", Scope: "db", Dest: "pg", Groups: []string{"ready"}, Params: map[string]string{"ttl": "1h"}}
+		err = inspector.ApplyConfig(Config{Targets: []TargetConfig{backup, pg}})
+		assert.ErrorIs(t, err, errDuplicatedTarget, "scope/dest of the programmatic target")
+
+		err = inspector.ApplyConfig(Config{Targets: []TargetConfig{backup, backup}})
+		assert.ErrorIs(t, err, errDuplicatedTarget)
+
+		assert.Len(t, inspector.targets, 2)
+	})
+
+	t.Run("Custom factory", func(t *testing.T) {
+		assert.Error(t, RegisterTargetFactory("", nil))
+		assert.NoError(t, RegisterTargetFactory("mock", func(tc TargetConfig) (HealthCheckable, error) {
+			return &mockService{scope: tc.Scope, dest: tc.Dest}, nil
+		}))
+
+		err := inspector.ApplyConfig(Config{Targets: []TargetConfig{{Type: "mock", Scope: "m", Dest: "1", Groups: []string{"live"}}}})
+		assert.NoError(t, err)
+
+		inspector.check(context.Background())
+		assert.NoError(t, inspector.CheckGroup(GroupLive, true))
+	})
+}
+
+func TestApplyConfig_omitted(t *testing.T) {
+	full := Config{
+		CheckPeriod:   Duration(time.Second),
+		CycleTimeout:  Duration(2 * time.Second),
+		CheckTimeout:  Duration(3 * time.Second),
+		ShutdownDelay: Duration(4 * time.Second),
+		Hysteresis:    map[string]HysteresisConfig{"ready": {Down: Duration(5 * time.Second), Up: Duration(6 * time.Second)}},
+	}
+
+	assertFull := func(t *testing.T, i *Inspector) {
+		t.Helper()
+
+		assert.Equal(t, time.Second, i.checkPeriod)
+		assert.Equal(t, 2*time.Second, i.cycleTimeout)
+		assert.Equal(t, 3*time.Second, i.checkTimeout)
+		assert.Equal(t, 4*time.Second, i.shutdownDelay)
+		assert.Equal(t, map[ProbeGroup]hysteresis{GroupReady: {down: 5 * time.Second, up: 6 * time.Second}}, i.hysteresis)
+	}
+
+	assertDefaults := func(t *testing.T, i *Inspector) {
+		t.Helper()
+
+		assert.Equal(t, defCheckPeriod, i.checkPeriod)
+		assert.Zero(t, i.cycleTimeout)
+		assert.Zero(t, i.checkTimeout)
+		assert.Zero(t, i.shutdownDelay)
+		assert.Empty(t, i.hysteresis)
+	}
+
+	t.Run("test.1 omitted settings are reset", func(t *testing.T) {
+		inspector := New()
+		assert.NoError(t, inspector.ApplyConfig(full))
+		assertFull(t, inspector)
+
+		assert.NoError(t, inspector.ApplyConfig(Config{}))
+		assertDefaults(t, inspector)
+	})
+
+	t.Run("test.2 options are reset too", func(t *testing.T) {
+		inspector := New()
+		assert.NoError(t, WithCheckPeriod(time.Hour)(inspector))
+		assert.NoError(t, WithCycleTimeout(time.Minute)(inspector))
+		assert.NoError(t, WithHysteresis(GroupLive, time.Second, time.Second)(inspector))
+
+		assert.NoError(t, inspector.ApplyConfig(Config{}))
+		assertDefaults(t, inspector)
+	})
+
+	t.Run("test.3 added settings are applied", func(t *testing.T) {
+		inspector := New()
+		assert.NoError(t, inspector.ApplyConfig(Config{}))
+		assertDefaults(t, inspector)
+
+		assert.NoError(t, inspector.ApplyConfig(full))
+		assertFull(t, inspector)
+	})
+}
+
+func TestApplyConfig_reload(t *testing.T) {
+	up := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test_apply_config_up"}, []string{"scope", "dest"})
+
+	inspector := New()
+	assert.NoError(t, WithMetric(up)(inspector))
+	assert.NoError(t, WithCheckPeriod(time.Hour)(inspector))
+	assert.NoError(t, WithHysteresis(GroupReady, time.Second, time.Minute)(inspector))
+
+	backup := TargetConfig{Type: "external", Scope: "cron", Dest: "backup", Groups: []string{"ready"}, Params: map[string]string{"ttl": "1h"}}
+	assert.NoError(t, inspector.ApplyConfig(Config{Targets: []TargetConfig{backup}}))
+	assert.Equal(t, defCheckPeriod, inspector.period(), "omitted period is reset to the default")
+	assert.Empty(t, inspector.hysteresis, "omitted hysteresis is removed")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	assert.NoError(t, inspector.Start(ctx))
+	assert.Eventually(t, func() bool { return testutil.CollectAndCount(up) == 1 }, time.Second, time.Millisecond)
+
+	// the first tick is an hour away, the new period applies at once
+	assert.NoError(t, inspector.ApplyConfig(Config{CheckPeriod: Duration(5 * time.Millisecond), Targets: []TargetConfig{backup}}))
+	assert.Eventually(t, func() bool { return inspector.Snapshot().Seq > 2 }, time.Second, time.Millisecond)
+
+	assert.NoError(t, inspector.ApplyConfig(Config{CheckPeriod: Duration(5 * time.Millisecond)}))
+	assert.Zero(t, testutil.CollectAndCount(up), "series of the removed target are deleted")
+
+	assert.NoError(t, inspector.Stop(context.Background()))
+}
+
+func TestWatchConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "healthz.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`{"checkPeriod": "5s"}`), 0o600))
+
+	inspector := New()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	reloaded := make(chan error, 10)
+	done := make(chan error, 1)
+
+	go func() {
+		done <- inspector.WatchConfig(ctx, path, 5*time.Millisecond, func(err error) { reloaded <- err })
+	}()
+
+	assert.Eventually(t, func() bool { return inspector.period() == 5*time.Second }, time.Second, time.Millisecond)
+
+	// modification time must differ from the first write
+	assert.NoError(t, os.WriteFile(path, []byte(`{"checkPeriod": "7s"}`), 0o600))
+	assert.NoError(t, os.Chtimes(path, time.Now().Add(time.Minute), time.Now().Add(time.Minute)))
+
+	select {
+	case err := <-reloaded:
+		assert.NoError(t, err)
+	case <-time.After(testTimeout):
+		t.Fatal("config wasn't reloaded")
+	}
+
+	assert.Equal(t, 7*time.Second, inspector.period())
+
+	cancel()
+	assert.NoError(t, <-done)
+}
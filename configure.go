@@ -0,0 +1,119 @@
+package healthz
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+var errNotHotOption = errors.New("option can't be applied to the live inspector")
+
+// coldOption - option of the settings read once (at Start or by the handlers), set reports
+// whether it changed the staged inspector of Configure.
+type coldOption struct {
+	name string
+	set  bool
+}
+
+// coldOptions - options of the settings beyond the ones Configure applies, the list must follow
+// new options: the staged inspector holds only hot settings, so any other field set is a cold option.
+func (i *Inspector) coldOptions() []coldOption {
+	return []coldOption{
+		{name: "WithAdaptiveSchedule", set: i.adaptive != adaptive{}},
+		{name: "WithCancellationAudit", set: i.auditGrace != 0},
+		{name: "WithBackoff", set: i.backoffMax != 0},
+		{name: "WithBootstrapTimeouts", set: i.bootstrap != bootstrap{}},
+		{name: "WithRefreshQuery", set: i.refreshQuery},
+		{name: "WithScopeConcurrency", set: i.scopeSlots != nil},
+		{name: "WithEmptyGroupPolicy", set: i.emptyGroup != EmptyGroupHealthy},
+		{name: "WithExecutionLog", set: i.execLog != nil},
+		{name: "WithResponseFormat or WithResponseStrategy", set: i.response.HealthyStatus != 0 ||
+			i.response.UnhealthyStatus != 0 || i.response.Formatter.Format != nil},
+		{name: "WithGroupHeader", set: i.groupHeader != nil},
+		{name: "WithHistory", set: i.history != nil},
+		{name: "WithHistoryRetention", set: i.retention != nil},
+		{name: "WithTargetsFromProvider", set: i.providers != nil},
+		{name: "WithStartupLatch", set: i.latchStartup},
+		{name: "WithOnDemandChecks", set: i.onDemand != nil},
+		{name: "WithLogger", set: i.logger != nil},
+		{name: "WithLogLevels", set: i.logLevels != LogLevels{}},
+		{name: "WithGroupMessages", set: i.groupMsgs != nil},
+		{name: "WithTargetMessages", set: i.targetMsgs != nil},
+		{name: "WithMetric", set: i.metric != nil},
+		{name: "WithMetricErrorsCounter", set: i.metricErrors != nil},
+		{name: "WithOutcomeMetric", set: i.outcomeMetric != nil},
+		{name: "WithLatencyMetric", set: i.latencyMetric != nil},
+		{name: "WithDurationMetric", set: i.durationHist != nil},
+		{name: "WithQueueWaitMetric", set: i.queueWaitHist != nil},
+		{name: "WithScopeMetric", set: i.scopeMetric != nil},
+		{name: "WithTargetMetric", set: i.targetMetric != nil},
+		{name: "WithMetricLabels", set: i.metricLabels != nil},
+		{name: "WithMetricNamespace", set: i.namespace != ""},
+		{name: "WithSelfMetrics", set: i.cycleDuration != nil},
+		{name: "WithPushgateway", set: i.pushgateway != nil},
+		{name: "WithTextfileExporter", set: i.textfile != nil},
+		{name: "WithErrorRedactor", set: i.redactor != nil},
+		{name: "WithRetries", set: i.retry.attempts != 0 || i.retry.backoff != 0},
+		{name: "WithRetryBudget", set: i.retry.maxTokens != 0 || i.retry.ratio != 0},
+		{name: "WithRoutes", set: i.routes != nil},
+		{name: "WithOnStateChange", set: i.subscribers.callbacks != nil},
+		{name: "WithThresholds", set: i.thresholds != thresholds{}},
+		{name: "WithTracer", set: i.tracer != nil || i.exemplar != nil},
+		{name: "WithWebhook", set: i.webhook != nil},
+	}
+}
+
+// Configure - applies the options to the inspector at once (all or none), safe while the check loop
+// runs: WithTargets, WithExternalTarget, WithCheckPeriod, WithCycleTimeout, WithCheckTimeout,
+// WithMaxConcurrency, WithHysteresis and WithShutdownDelay. The check loop rereads them every cycle,
+// a new check period applies at once. WithTargets replaces the programmatic targets only (declarative
+// and provided ones stay), metric series of the removed ones are deleted. Options changing other
+// settings are rejected, apply them before Start (or to WithOverrides).
+func (i *Inspector) Configure(opts ...Option) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	staged := &Inspector{
+		targets:       slices.DeleteFunc(slices.Clone(i.targets), HealthCheckTarget.declaredOrProvided),
+		checkPeriod:   i.checkPeriod,
+		cycleTimeout:  i.cycleTimeout,
+		checkTimeout:  i.checkTimeout,
+		maxChecks:     i.maxChecks,
+		hysteresis:    maps.Clone(i.hysteresis),
+		shutdownDelay: i.shutdownDelay,
+	}
+
+	for n, opt := range opts {
+		if err := opt(staged); err != nil {
+			return fmt.Errorf("option #%d: %w", n, err)
+		}
+
+		for _, cold := range staged.coldOptions() {
+			if cold.set {
+				return fmt.Errorf("%w: option #%d is %s", errNotHotOption, n, cold.name)
+			}
+		}
+	}
+
+	targets, dropped, err := replaceTargets(i.targets, HealthCheckTarget.programmatic, staged.targets)
+	if err != nil {
+		return err
+	}
+
+	for _, target := range dropped {
+		i.deleteTargetSeries(target.Service.Scope(), target.Service.Dest())
+	}
+
+	i.targets = targets
+	i.checkPeriod = staged.checkPeriod
+	i.cycleTimeout = staged.cycleTimeout
+	i.checkTimeout = staged.checkTimeout
+	i.maxChecks = staged.maxChecks
+	i.hysteresis = staged.hysteresis
+	i.shutdownDelay = staged.shutdownDelay
+
+	i.reloaded()
+
+	return nil
+}
@@ -0,0 +1,141 @@
+package healthz
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInspector_Configure(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       []Option
+		wantErr    error
+		wantPeriod time.Duration
+	}{
+		{
+			name:       "test.1 hot options",
+			opts:       []Option{WithCheckPeriod(time.Minute), WithCheckTimeout(time.Second), WithMaxConcurrency(2)},
+			wantPeriod: time.Minute,
+		},
+		{
+			name:       "test.2 cold option",
+			opts:       []Option{WithCheckPeriod(time.Minute), WithStartupLatch()},
+			wantErr:    errNotHotOption,
+			wantPeriod: defCheckPeriod,
+		},
+		{
+			name:       "test.3 cold option of a struct setting",
+			opts:       []Option{WithThresholds(3, 1)},
+			wantErr:    errNotHotOption,
+			wantPeriod: defCheckPeriod,
+		},
+		{
+			name:       "test.4 failed option",
+			opts:       []Option{WithCheckPeriod(time.Minute), WithCheckTimeout(0)},
+			wantErr:    errWrongTimeout,
+			wantPeriod: defCheckPeriod,
+		},
+		{
+			name: "test.5 metric",
+			opts: []Option{WithMetric(prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "up"},
+				[]string{"scope", "dest"}))},
+			wantErr:    errNotHotOption,
+			wantPeriod: defCheckPeriod,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inspector := New(HealthCheckTarget{Service: &mockService{scope: "db", dest: "pg"}, Groups: GroupReady})
+
+			err := inspector.Configure(tt.opts...)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantPeriod, inspector.period(), "options are applied all or none")
+
+			if tt.wantErr != nil {
+				assert.False(t, inspector.latchStartup)
+				assert.Nil(t, inspector.metric)
+			}
+		})
+	}
+}
+
+func TestInspector_Configure_targets(t *testing.T) {
+	up := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test_configure_up"}, []string{"scope", "dest"})
+
+	inspector := New(HealthCheckTarget{Service: &mockService{scope: "db", dest: "pg"}, Groups: GroupReady})
+	assert.NoError(t, WithMetric(up)(inspector))
+	assert.NoError(t, WithCheckPeriod(time.Hour)(inspector))
+	assert.NoError(t, WithTargetsFromProvider(func() []HealthCheckTarget {
+		return []HealthCheckTarget{{Service: &mockService{scope: "cache", dest: "redis"}, Groups: GroupReady}}
+	})(inspector))
+	assert.NoError(t, inspector.ApplyConfig(Config{CheckPeriod: Duration(time.Hour), Targets: []TargetConfig{
+		{Type: "external", Scope: "cron", Dest: "backup", Groups: []string{"live"}, Params: map[string]string{"ttl": "1h"}},
+	}}))
+
+	assert.NoError(t, inspector.Start(context.Background()))
+	defer inspector.Stop(context.Background())
+
+	assert.Eventually(t, func() bool { return testutil.CollectAndCount(up) == 3 }, time.Second, time.Millisecond)
+
+	err := inspector.Configure(WithTargets(HealthCheckTarget{Service: &mockService{scope: "cache", dest: "redis"}, Groups: GroupLive}))
+	assert.ErrorIs(t, err, errDuplicatedTarget, "scope/dest of the provided target")
+
+	err = inspector.Configure(WithCheckPeriod(5*time.Millisecond),
+		WithTargets(HealthCheckTarget{Service: &mockService{scope: "db", dest: "replica"}, Groups: GroupReady}))
+	assert.NoError(t, err)
+
+	dests := func() []string {
+		inspector.mu.RLock()
+		defer inspector.mu.RUnlock()
+
+		var list []string
+
+		for _, target := range inspector.targets {
+			list = append(list, target.Service.Dest())
+		}
+
+		return list
+	}
+	assert.Equal(t, []string{"replica", "backup", "redis"}, dests(), "declarative and provided targets stay")
+
+	// the first tick is an hour away, the new period applies at once
+	assert.Eventually(t, func() bool { return inspector.Snapshot().Seq > 2 }, time.Second, time.Millisecond)
+	assert.Equal(t, 3, testutil.CollectAndCount(up))
+	assert.False(t, up.DeleteLabelValues("db", "pg"), "series of the replaced target are deleted")
+}
+
+func TestInspector_Configure_running(t *testing.T) {
+	inspector := New(HealthCheckTarget{Service: &mockService{scope: "db", dest: "pg"}, Groups: GroupReady})
+	assert.NoError(t, WithCheckPeriod(time.Millisecond)(inspector))
+	assert.NoError(t, inspector.Start(context.Background()))
+
+	defer inspector.Stop(context.Background())
+
+	var wg sync.WaitGroup
+
+	for n := range 10 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			assert.NoError(t, inspector.Configure(
+				WithCheckPeriod(time.Duration(n+1)*time.Millisecond),
+				WithTargets(HealthCheckTarget{Service: &mockService{scope: "db", dest: "pg"}, Groups: GroupReady}),
+				WithHysteresis(GroupReady, 0, time.Millisecond),
+			))
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Eventually(t, func() bool { return inspector.CheckGroup(GroupReady, true) == nil },
+		time.Second, time.Millisecond)
+}
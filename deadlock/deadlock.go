@@ -0,0 +1,164 @@
+// Package deadlock - liveness self-deadlock detection: key application mutexes and worker pools
+// are probed to respond within a bound, the checker is a healthz target of GroupLive.
+package deadlock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/art-frela/healthz"
+)
+
+const defTimeout = time.Second * 5
+
+var (
+	errMissName   = errors.New("miss probe name")
+	errMissProbe  = errors.New("miss probe")
+	errDuplicated = errors.New("probe is already registered")
+	errNoResponse = errors.New("no response")
+)
+
+// Probe - touches a key resource (locks and unlocks the mutex, passes a no-op job through the worker
+// pool), returns when the resource responded.
+type Probe func(ctx context.Context) error
+
+type probe struct {
+	fn      Probe
+	running bool // the previous call hasn't returned yet
+}
+
+// Checker - verifies the registered probes respond within the timeout.
+type Checker struct {
+	scope   string
+	timeout time.Duration
+
+	mu     sync.Mutex
+	probes map[string]*probe
+}
+
+var _ healthz.HealthCheckable = (*Checker)(nil)
+
+// NewChecker - checker with the probes timeout (5s if not positive).
+func NewChecker(scope string, timeout time.Duration) *Checker {
+	if timeout <= 0 {
+		timeout = defTimeout
+	}
+
+	return &Checker{
+		scope:   scope,
+		timeout: timeout,
+		probes:  make(map[string]*probe),
+	}
+}
+
+// Register - adds the probe of the resource.
+func (c *Checker) Register(name string, fn Probe) error {
+	if name == "" {
+		return errMissName
+	}
+
+	if fn == nil {
+		return errMissProbe
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.probes[name]; ok {
+		return fmt.Errorf("%w: %s", errDuplicated, name)
+	}
+
+	c.probes[name] = &probe{fn: fn}
+
+	return nil
+}
+
+// RegisterLocker - adds the probe locking and unlocking the mutex.
+func (c *Checker) RegisterLocker(name string, l sync.Locker) error {
+	if l == nil {
+		return errMissProbe
+	}
+
+	return c.Register(name, func(context.Context) error {
+		l.Lock()
+		l.Unlock()
+
+		return nil
+	})
+}
+
+// Target - the checker as liveness target: the process should be restarted when it is deadlocked.
+func (c *Checker) Target() healthz.HealthCheckTarget {
+	return healthz.HealthCheckTarget{Service: c, Groups: healthz.GroupLive}
+}
+
+func (c *Checker) Scope() string { return c.scope }
+func (c *Checker) Dest() string  { return "deadlock" }
+
+// Health - runs all probes, a probe still running since the previous check isn't started again
+// and is reported at once.
+func (c *Checker) Health(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	c.mu.Lock()
+
+	names := make([]string, 0, len(c.probes))
+	for name := range c.probes {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var (
+		errs    []error
+		results = make(map[string]chan error, len(names))
+	)
+
+	for _, name := range names {
+		p := c.probes[name]
+		if p.running {
+			errs = append(errs, fmt.Errorf("%s: %w since the previous check", name, errNoResponse))
+
+			continue
+		}
+
+		p.running = true
+		done := make(chan error, 1)
+		results[name] = done
+
+		go func() {
+			err := p.fn(ctx)
+
+			c.mu.Lock()
+			p.running = false
+			c.mu.Unlock()
+
+			done <- err
+		}()
+	}
+
+	c.mu.Unlock()
+
+	for _, name := range names {
+		done, ok := results[name]
+		if !ok {
+			continue
+		}
+
+		select {
+		case err := <-done:
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			}
+		case <-ctx.Done():
+			errs = append(errs, fmt.Errorf("%s: %w within %s", name, errNoResponse, c.timeout))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,78 @@
+package deadlock
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/art-frela/healthz"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChecker(t *testing.T) {
+	var (
+		cacheMu sync.Mutex
+		stateMu sync.RWMutex
+	)
+
+	jobs := make(chan func())
+
+	go func() {
+		for job := range jobs {
+			job()
+		}
+	}()
+	defer close(jobs)
+
+	checker := NewChecker("app", 50*time.Millisecond)
+	assert.NoError(t, checker.RegisterLocker("cache", &cacheMu))
+	assert.NoError(t, checker.RegisterLocker("state", &stateMu))
+	assert.NoError(t, checker.Register("workers", func(ctx context.Context) error {
+		done := make(chan struct{})
+
+		select {
+		case jobs <- func() { close(done) }:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		<-done
+
+		return nil
+	}))
+
+	assert.NoError(t, checker.Health(context.Background()))
+
+	cacheMu.Lock() // deadlocked
+
+	err := checker.Health(context.Background())
+	assert.ErrorIs(t, err, errNoResponse)
+	assert.ErrorContains(t, err, "cache: no response within 50ms")
+
+	err = checker.Health(context.Background())
+	assert.ErrorContains(t, err, "cache: no response since the previous check")
+
+	cacheMu.Unlock()
+
+	assert.Eventually(t, func() bool { return checker.Health(context.Background()) == nil }, time.Second, time.Millisecond)
+
+	target := checker.Target()
+	assert.Equal(t, healthz.GroupLive, target.Groups)
+	assert.Equal(t, "deadlock", target.Service.Dest())
+}
+
+func TestChecker_Register(t *testing.T) {
+	checker := NewChecker("app", 0)
+	assert.Equal(t, defTimeout, checker.timeout)
+
+	assert.ErrorIs(t, checker.Register("", func(context.Context) error { return nil }), errMissName)
+	assert.ErrorIs(t, checker.Register("nil", nil), errMissProbe)
+	assert.ErrorIs(t, checker.RegisterLocker("nil", nil), errMissProbe)
+
+	assert.NoError(t, checker.Register("pool", func(context.Context) error { return errors.New("pool closed") }))
+	assert.ErrorIs(t, checker.Register("pool", func(context.Context) error { return nil }), errDuplicated)
+
+	assert.ErrorContains(t, checker.Health(context.Background()), "pool: pool closed")
+}
@@ -0,0 +1,49 @@
+package healthz
+
+import "errors"
+
+// Status - health of a target or of a probe group: degraded ones pass probes
+// but are surfaced in outputs and metrics (e.g. a read-only replica is down).
+type Status string
+
+const (
+	StatusHealthy   Status = "healthy"
+	StatusDegraded  Status = "degraded"
+	StatusUnhealthy Status = "unhealthy"
+)
+
+// DegradedError - implemented by errors meaning the target works but degraded,
+// checks may return them instead of wrapping by Degraded (e.g. checks.QuotaLowError).
+type DegradedError interface {
+	error
+	Degraded() bool
+}
+
+// degradedError - error wrapped by Degraded.
+type degradedError struct{ err error }
+
+func (de degradedError) Error() string  { return de.err.Error() }
+func (de degradedError) Unwrap() error  { return de.err }
+func (de degradedError) Degraded() bool { return true }
+
+// Degraded - marks the check error as degraded: the check passes with StatusDegraded
+// and the error as its reason (TargetResult.Degraded). Returns nil for nil.
+func Degraded(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return degradedError{err: err}
+}
+
+// splitDegraded - error of the check and reason of its degradation, only one of them is set.
+// Joined errors aren't looked into: a failure joined with a degradation is a failure.
+func splitDegraded(err error) (error, error) {
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		if de, ok := e.(DegradedError); ok && de.Degraded() {
+			return nil, err
+		}
+	}
+
+	return err, nil
+}
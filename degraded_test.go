@@ -0,0 +1,76 @@
+package healthz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_splitDegraded(t *testing.T) {
+	fail := errors.New("fail")
+	replica := Degraded(errors.New("replica is down"))
+
+	tests := []struct {
+		name         string
+		err          error
+		wantErr      bool
+		wantDegraded bool
+	}{
+		{name: "test.1 nil"},
+		{name: "test.2 failure", err: fail, wantErr: true},
+		{name: "test.3 degraded", err: replica, wantDegraded: true},
+		{name: "test.4 wrapped degraded", err: fmt.Errorf("pg: %w", replica), wantDegraded: true},
+		{name: "test.5 joined with failure", err: errors.Join(fail, replica), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err, degraded := splitDegraded(tt.err)
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.wantDegraded, degraded != nil)
+		})
+	}
+
+	assert.NoError(t, Degraded(nil))
+}
+
+func TestDegraded(t *testing.T) {
+	outcomes := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_outcomes_total"}, []string{"scope", "dest", "outcome"})
+
+	pg := &mockService{scope: "db", dest: "pg", healthErr: Degraded(errors.New("replica is down"))}
+	inspector := New(HealthCheckTarget{Service: pg, Groups: GroupReady})
+	assert.NoError(t, WithOutcomeMetric(outcomes)(inspector))
+	assert.NoError(t, WithResponseFormat(FormatJSONDetailed)(inspector))
+
+	inspector.check(context.Background())
+
+	assert.NoError(t, inspector.CheckGroup(GroupReady, true), "degraded passes")
+
+	tr := inspector.Snapshot().Targets[0]
+	assert.True(t, tr.Healthy())
+	assert.Equal(t, StatusDegraded, tr.Status())
+	assert.EqualError(t, tr.Degraded, "replica is down")
+	assert.Equal(t, 1.0, testutil.ToFloat64(outcomes.WithLabelValues("db", "pg", OutcomeDegraded)))
+
+	w := httptest.NewRecorder()
+	inspector.HealthHandler(GroupReady, true, nil)(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"status":"degraded","group":"ready"`)
+	assert.Contains(t, w.Body.String(), `"status":"degraded","error":"replica is down"`)
+
+	w = httptest.NewRecorder()
+	inspector.StatusHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/status?status=degraded", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"degraded":"replica is down"`)
+	assert.Contains(t, w.Body.String(), `"total":1`)
+
+	pg.healthErr = nil
+	inspector.check(context.Background())
+	assert.Equal(t, StatusHealthy, inspector.Snapshot().Targets[0].Status())
+}
@@ -0,0 +1,178 @@
+package healthz
+
+import (
+	"net/url"
+	"strings"
+)
+
+// credentialKeys - keys of key=value connection strings dropped by NormalizeDest.
+var credentialKeys = map[string]bool{
+	"user":     true,
+	"username": true,
+	"user id":  true,
+	"uid":      true,
+	"password": true,
+	"passwd":   true,
+	"pwd":      true,
+}
+
+// NormalizeDest - connection string/URL without credentials and query params, safe for
+// metric label and display value, for example:
+//
+//	"postgres://user:pass@db:5432/app?sslmode=disable" -> "postgres://db:5432/app"
+//	"user:pass@tcp(db:3306)/app?parseTime=true"        -> "tcp(db:3306)/app"
+//	"host=db port=5432 user=app password=secret"       -> "host=db port=5432"
+//	"host=db password='my secret'"                     -> "host=db"
+//	"postgres:///app?host=/var/run/postgresql"         -> "postgres:///app"
+//	"file:test.db?_auth_pass=secret"                   -> "file:test.db"
+func NormalizeDest(raw string) string {
+	s := strings.TrimSpace(raw)
+
+	// any URL, host-less ones (unix sockets, sqlite files) too, but not "user:pass@tcp(host)/db"
+	if u, err := url.Parse(s); err == nil && u.Scheme != "" && !strings.Contains(u.Opaque, "@") {
+		u.User = nil
+		u.RawQuery = ""
+		u.ForceQuery = false
+		u.Fragment = ""
+
+		return u.String()
+	}
+
+	// key=value list, but not "user:pass@tcp(host)/db?param=value"
+	if strings.Contains(s, "=") && (strings.ContainsAny(s, " ;") || !strings.Contains(s, "@")) {
+		return normalizeKeyValue(s)
+	}
+
+	s, _, _ = strings.Cut(s, "?")
+
+	if at := strings.LastIndex(s, "@"); at >= 0 {
+		s = s[at+1:]
+	}
+
+	return s
+}
+
+// normalizeKeyValue - drops credentials from "k=v k=v" (libpq) or "k=v;k=v" (ADO) strings.
+func normalizeKeyValue(s string) string {
+	sep, parts := ";", splitADO(s)
+
+	if len(parts) == 1 { // no ";" outside quoted values
+		sep, parts = " ", splitLibpq(s)
+	}
+
+	kept := make([]string, 0, len(parts))
+
+	for _, part := range parts {
+		key, _, _ := strings.Cut(part, "=")
+		key = strings.ToLower(strings.TrimSpace(key))
+
+		if key == "" || credentialKeys[key] {
+			continue
+		}
+
+		kept = append(kept, strings.TrimSpace(part))
+	}
+
+	return strings.Join(kept, sep)
+}
+
+// splitLibpq - "k=v" pairs of the libpq string: spaces around "=" are allowed, values are
+// single-quoted (could contain spaces) or not, backslash escapes the next char in both.
+func splitLibpq(s string) []string {
+	var parts []string
+
+	for pos := 0; pos < len(s); {
+		pos = skipSpaces(s, pos)
+		if pos == len(s) {
+			break
+		}
+
+		start := pos
+		for pos < len(s) && s[pos] != '=' && !isSpace(s[pos]) {
+			pos++
+		}
+
+		key := s[start:pos]
+
+		pos = skipSpaces(s, pos)
+		if pos == len(s) || s[pos] != '=' { // not a pair, libpq rejects it
+			parts = append(parts, key)
+
+			continue
+		}
+
+		start = skipSpaces(s, pos+1)
+		pos = libpqValueEnd(s, start)
+		parts = append(parts, key+"="+s[start:pos])
+	}
+
+	return parts
+}
+
+// splitADO - "k=v" pairs of the ADO string separated by ";", values quoted by ' or " (doubled to escape)
+// could contain ";", as well as quoted values of libpq strings.
+func splitADO(s string) []string {
+	var (
+		parts []string
+		quote byte
+		start int
+		eq    = -1 // last "=" of the pair
+	)
+
+	for pos := 0; pos < len(s); pos++ {
+		c := s[pos]
+
+		switch {
+		case quote != 0:
+			if c == quote && pos+1 < len(s) && s[pos+1] == quote {
+				pos++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '=':
+			eq = pos
+		case (c == '\'' || c == '"') && eq >= start && strings.TrimSpace(s[eq+1:pos]) == "":
+			quote = c
+		case c == ';':
+			parts = append(parts, s[start:pos])
+			start = pos + 1
+		}
+	}
+
+	return append(parts, s[start:])
+}
+
+// libpqValueEnd - end of the libpq value starting at pos.
+func libpqValueEnd(s string, pos int) int {
+	quoted := pos < len(s) && s[pos] == '\''
+	if quoted {
+		pos++
+	}
+
+	for pos < len(s) {
+		switch c := s[pos]; {
+		case c == '\\':
+			pos += 2
+		case quoted && c == '\'':
+			return pos + 1
+		case !quoted && isSpace(c):
+			return pos
+		default:
+			pos++
+		}
+	}
+
+	return len(s)
+}
+
+func skipSpaces(s string, pos int) int {
+	for pos < len(s) && isSpace(s[pos]) {
+		pos++
+	}
+
+	return pos
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
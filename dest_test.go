@@ -0,0 +1,40 @@
+package healthz
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeDest(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{name: "test.1 url with credentials and query", raw: "postgres://user:pass@db:5432/app?sslmode=disable", want: "postgres://db:5432/app"},
+		{name: "test.2 url with user only", raw: "redis://default@cache:6379/0", want: "redis://cache:6379/0"},
+		{name: "test.3 clean url", raw: "http://api.local/health", want: "http://api.local/health"},
+		{name: "test.4 mysql dsn", raw: "user:p@ss@tcp(db:3306)/app?parseTime=true", want: "tcp(db:3306)/app"},
+		{name: "test.5 libpq key-value", raw: "host=db port=5432 user=app password=secret dbname=app", want: "host=db port=5432 dbname=app"},
+		{name: "test.6 ado key-value", raw: "Server=db;User ID=sa;Password=secret;Database=app", want: "Server=db;Database=app"},
+		{name: "test.7 host:port", raw: " kafka-1.domain.local:8321 ", want: "kafka-1.domain.local:8321"},
+		{name: "test.8 empty", raw: "", want: ""},
+		{name: "test.9 libpq quoted password", raw: "host=db password='my secret' dbname=app", want: "host=db dbname=app"},
+		{name: "test.10 libpq escaped quote", raw: `host=db password='it\'s x' user = 'a b' port=5432`, want: "host=db port=5432"},
+		{name: "test.11 libpq quoted separator", raw: "host=db password='a;b c' dbname=app", want: "host=db dbname=app"},
+		{name: "test.12 libpq backslash", raw: `host=db password=se\ cret dbname=app`, want: "host=db dbname=app"},
+		{name: "test.13 ado quoted password", raw: `Server=db;Password="a;b ""c""";Database=app`, want: "Server=db;Database=app"},
+		{name: "test.14 ado single quoted", raw: "Server=db;Pwd='x;y';Database=app", want: "Server=db;Database=app"},
+		{name: "test.15 unix socket url", raw: "postgres:///db?host=/var/run/postgresql&password=secret", want: "postgres:///db"},
+		{name: "test.16 unix socket url with user", raw: "postgres://app:secret@/db?host=/tmp", want: "postgres:///db"},
+		{name: "test.17 sqlite file", raw: "file:test.db?_auth_user=app&_auth_pass=secret", want: "file:test.db"},
+		{name: "test.18 sqlite memory", raw: "file::memory:?cache=shared", want: "file::memory:"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NormalizeDest(tt.raw))
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package healthz
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNoTargets - probe group has no targets to check, the verdict depends on EmptyGroupPolicy.
+var ErrNoTargets = errors.New("no health check targets in the group")
+
+var errWrongEmptyPolicy = errors.New("wrong empty group policy")
+
+// EmptyGroupPolicy - verdict of the probe group without targets.
+type EmptyGroupPolicy int
+
+const (
+	EmptyGroupHealthy   EmptyGroupPolicy = iota // group without targets is healthy (default)
+	EmptyGroupUnhealthy                         // group without targets fails with ErrNoTargets
+)
+
+func (p EmptyGroupPolicy) String() string {
+	switch p {
+	case EmptyGroupHealthy:
+		return "healthy"
+	case EmptyGroupUnhealthy:
+		return "unhealthy"
+	}
+
+	return fmt.Sprintf("EmptyGroupPolicy(%d)", int(p))
+}
+
+// WithEmptyGroupPolicy - verdict of probe groups without targets,
+// e.g. EmptyGroupUnhealthy to not report ready an instance with forgotten targets.
+func WithEmptyGroupPolicy(policy EmptyGroupPolicy) Option {
+	return func(i *Inspector) error {
+		if policy != EmptyGroupHealthy && policy != EmptyGroupUnhealthy {
+			return fmt.Errorf("%w: %d", errWrongEmptyPolicy, policy)
+		}
+
+		i.emptyGroup = policy
+
+		return nil
+	}
+}
+
+// apply - resolves ErrNoTargets of the single group by the policy.
+func (p EmptyGroupPolicy) apply(err error) error {
+	if errors.Is(err, ErrNoTargets) && p == EmptyGroupHealthy {
+		return nil
+	}
+
+	return err
+}
+
+// warnEmptyGroups - logs probe groups without targets on start.
+func (i *Inspector) warnEmptyGroups() {
+	i.mu.RLock()
+	targets := i.targets
+	i.mu.RUnlock()
+
+	var covered ProbeGroup
+
+	for _, target := range targets {
+		covered |= target.Groups
+	}
+
+	if empty := (GroupStartup | GroupLive | GroupReady) &^ covered; empty != 0 {
+		i.log().Warn("healthz: probe groups have no targets",
+			"groups", empty.String(), "policy", i.emptyGroup.String())
+	}
+}
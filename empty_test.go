@@ -0,0 +1,85 @@
+package healthz
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmptyGroupPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  EmptyGroupPolicy
+		targets []HealthCheckTarget
+		group   ProbeGroup
+		wantErr error
+	}{
+		{
+			name:    "test.1 no targets, default policy",
+			policy:  EmptyGroupHealthy,
+			group:   GroupReady,
+			wantErr: nil,
+		},
+		{
+			name:    "test.2 no targets, unhealthy policy",
+			policy:  EmptyGroupUnhealthy,
+			group:   GroupReady,
+			wantErr: ErrNoTargets,
+		},
+		{
+			name:    "test.3 group without targets, unhealthy policy",
+			policy:  EmptyGroupUnhealthy,
+			targets: []HealthCheckTarget{{Service: &mockService{}, Groups: GroupLive}},
+			group:   GroupReady,
+			wantErr: ErrNoTargets,
+		},
+		{
+			name:    "test.4 group with targets, unhealthy policy",
+			policy:  EmptyGroupUnhealthy,
+			targets: []HealthCheckTarget{{Service: &mockService{}, Groups: GroupLive}},
+			group:   GroupLive,
+			wantErr: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inspector := New(tt.targets...)
+			assert.NoError(t, WithEmptyGroupPolicy(tt.policy)(inspector))
+
+			assert.ErrorIs(t, inspector.CheckGroup(tt.group, true), errNoYetChecked)
+
+			inspector.check(context.Background())
+
+			for _, needAll := range []bool{true, false} {
+				err := inspector.CheckGroup(tt.group, needAll)
+				if tt.wantErr == nil {
+					assert.NoError(t, err)
+				} else {
+					assert.ErrorIs(t, err, tt.wantErr)
+				}
+			}
+		})
+	}
+
+	assert.ErrorIs(t, WithEmptyGroupPolicy(EmptyGroupPolicy(5))(New()), errWrongEmptyPolicy)
+}
+
+func TestWarnEmptyGroups(t *testing.T) {
+	var buf bytes.Buffer
+
+	prev := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buf, nil)))
+
+	defer slog.SetDefault(prev)
+
+	New(HealthCheckTarget{Service: &mockService{}, Groups: GroupLive | GroupReady}).warnEmptyGroups()
+	assert.Contains(t, buf.String(), "groups=startup policy=healthy")
+
+	buf.Reset()
+	New(HealthCheckTarget{Service: &mockService{}, Groups: GroupStartup | GroupLive | GroupReady}).warnEmptyGroups()
+	assert.Empty(t, buf.String())
+}
@@ -0,0 +1,82 @@
+// Package envoyhealthz - reports readiness of the healthz inspector to the Envoy sidecar through its admin
+// endpoint (POST /healthcheck/ok, /healthcheck/fail), so mesh routing reacts to the same signals as
+// the Kubernetes probes: while the ready group fails Envoy fails its health checks and gets drained.
+// Reporting to an HDS management server isn't covered, it needs the xDS client of the mesh.
+package envoyhealthz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/art-frela/healthz"
+	"github.com/art-frela/healthz/lbsync"
+)
+
+const defTimeout = 5 * time.Second
+
+var (
+	errWrongAdminURL = errors.New("incorrect envoy admin url")
+	errAdminStatus   = errors.New("unexpected envoy admin response status")
+)
+
+// Admin - lbsync.Registrar over the Envoy admin endpoint: Register passes the health checks of Envoy,
+// Deregister fails them.
+type Admin struct {
+	url    string
+	client *http.Client
+}
+
+// NewAdmin - admin of Envoy at adminURL (e.g. http://127.0.0.1:9901),
+// client could be nil (default with 5s timeout).
+func NewAdmin(adminURL string, client *http.Client) (*Admin, error) {
+	u, err := url.Parse(adminURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("%w: %q", errWrongAdminURL, adminURL)
+	}
+
+	if client == nil {
+		client = &http.Client{Timeout: defTimeout}
+	}
+
+	return &Admin{url: strings.TrimSuffix(adminURL, "/"), client: client}, nil
+}
+
+// New - syncer of the inspector readiness to Envoy at adminURL, run it by Run.
+func New(inspector *healthz.Inspector, adminURL string, client *http.Client) (*lbsync.Syncer, error) {
+	admin, err := NewAdmin(adminURL, client)
+	if err != nil {
+		return nil, err
+	}
+
+	return lbsync.New(inspector, admin)
+}
+
+func (a *Admin) Register(ctx context.Context) error   { return a.post(ctx, "/healthcheck/ok") }
+func (a *Admin) Deregister(ctx context.Context) error { return a.post(ctx, "/healthcheck/fail") }
+
+func (a *Admin) post(ctx context.Context, path string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.url+path, http.NoBody)
+	if err != nil {
+		return err
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: %s %d", errAdminStatus, path, resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package envoyhealthz
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/art-frela/healthz"
+	"github.com/stretchr/testify/assert"
+)
+
+type service struct{}
+
+func (service) Health(context.Context) error { return nil }
+func (service) Scope() string                { return "envoy" }
+func (service) Dest() string                 { return "app" }
+
+func TestAdmin(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		paths []string
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+
+			return
+		}
+
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	last := func() string {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if len(paths) == 0 {
+			return ""
+		}
+
+		return paths[len(paths)-1]
+	}
+
+	inspector := healthz.New(healthz.HealthCheckTarget{Service: service{}, Groups: healthz.GroupReady})
+	assert.NoError(t, healthz.WithCheckPeriod(10*time.Millisecond)(inspector))
+
+	syncer, err := New(inspector, srv.URL+"/", nil)
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	assert.NoError(t, inspector.Start(ctx))
+
+	go func() { _ = syncer.Run(ctx) }()
+
+	assert.Eventually(t, func() bool { return last() == "/healthcheck/ok" }, time.Second, time.Millisecond)
+
+	assert.NoError(t, inspector.Stop(context.Background()))
+	assert.Equal(t, "/healthcheck/fail", last(), "envoy fails health checks when stopping")
+
+	_, err = NewAdmin("127.0.0.1:9901", nil)
+	assert.ErrorIs(t, err, errWrongAdminURL)
+
+	srv.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusNotFound) })
+
+	admin, err := NewAdmin(srv.URL, nil)
+	assert.NoError(t, err)
+	assert.True(t, errors.Is(admin.Register(context.Background()), errAdminStatus))
+}
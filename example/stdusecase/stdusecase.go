@@ -17,7 +17,10 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
-const stopTimeout = time.Second * 5
+const (
+	stopTimeout      = time.Second * 5
+	propagationDelay = time.Second
+)
 
 // metric for update
 var serviceUp = promauto.NewGaugeVec(
@@ -53,6 +56,7 @@ func (c *Controller) start(ctx context.Context, hostPort string) {
 	mux.HandleFunc("/healthz/startup", c.hlz.HealthHandler(healthz.GroupStartup, false, nil))
 	mux.HandleFunc("/healthz/live", c.hlz.HealthHandler(healthz.GroupLive, false, nil))
 	mux.HandleFunc("/healthz/ready", c.hlz.HealthHandler(healthz.GroupReady, true, nil))
+	mux.Handle("/healthz/scopes", c.hlz.ScopesHandler())
 
 	mux.HandleFunc("/metrics", promhttp.Handler().ServeHTTP)
 
@@ -85,10 +89,8 @@ func (c *Controller) stop() {
 	stopCTX, cancel := context.WithTimeout(context.Background(), stopTimeout)
 	defer cancel()
 
-	c.hlz.Stop(stopCTX)
-
-	if err := c.srv.Shutdown(stopCTX); err != nil {
-		log.Printf("http server shutdown error: %s", err)
+	if err := healthz.GracefulShutdown(stopCTX, c.hlz, propagationDelay, c.srv); err != nil {
+		log.Printf("graceful shutdown error: %s", err)
 	}
 }
 
@@ -153,3 +155,6 @@ func main() {
 
 // GET /healthz/ready
 // Unhealthy
+
+// GET /healthz/scopes
+// [{"scope":"database","healthy":0,"total":1},{"scope":"kafka","healthy":1,"total":1}]
@@ -0,0 +1,233 @@
+package healthz
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var errWrongRotation = errors.New("incorrect execution log rotation")
+
+// ExecutionRecord - one execution (attempt) of the target check.
+type ExecutionRecord struct {
+	Scope    string
+	Dest     string
+	Cycle    uint64
+	Attempt  int
+	Started  time.Time
+	Duration time.Duration
+	Outcome  string // OutcomeOK, OutcomeDegraded, OutcomeError, OutcomeTimeout
+	Err      error  // error or reason of the degradation
+}
+
+func (er ExecutionRecord) MarshalJSON() ([]byte, error) {
+	view := struct {
+		Scope           string    `json:"scope"`
+		Dest            string    `json:"dest"`
+		Cycle           uint64    `json:"cycle"`
+		Attempt         int       `json:"attempt"`
+		Started         time.Time `json:"started"`
+		DurationSeconds float64   `json:"durationSeconds"`
+		Outcome         string    `json:"outcome"`
+		Error           string    `json:"error,omitempty"`
+	}{
+		Scope:           er.Scope,
+		Dest:            er.Dest,
+		Cycle:           er.Cycle,
+		Attempt:         er.Attempt,
+		Started:         er.Started,
+		DurationSeconds: er.Duration.Seconds(),
+		Outcome:         er.Outcome,
+	}
+
+	if er.Err != nil {
+		view.Error = er.Err.Error()
+	}
+
+	return json.Marshal(view)
+}
+
+// ExecutionLog - appender of the check executions, see WithExecutionLog.
+type ExecutionLog interface {
+	Append(records ...ExecutionRecord) error
+}
+
+// WithExecutionLog - records every check execution, retries included, to the log: a forensic trail
+// of intermittent dependency failures. Errors are redacted (see WithErrorRedactor),
+// failed appends are logged (slog) and don't affect checks.
+func WithExecutionLog(log ExecutionLog) Option {
+	return func(i *Inspector) error {
+		i.execLog = log
+
+		return nil
+	}
+}
+
+// logExecution - appends the attempt of the check started at started, the shadow inspector (see Rollout) appends nothing.
+func (i *Inspector) logExecution(target HealthCheckTarget, info CheckInfo, started time.Time, res *serviceCheckResult) {
+	if i.execLog == nil || i.shadow.Load() {
+		return
+	}
+
+	record := ExecutionRecord{
+		Scope:    target.Service.Scope(),
+		Dest:     target.Service.Dest(),
+		Cycle:    info.Cycle,
+		Attempt:  info.Attempt,
+		Started:  started,
+		Duration: time.Since(started),
+		Outcome:  outcome(res.err, res.degraded),
+		Err:      i.redact(errors.Join(res.err, res.degraded)),
+	}
+
+	if err := i.execLog.Append(record); err != nil {
+		i.log().Warn("healthz: check execution not logged", "scope", record.Scope, "dest", record.Dest, "error", err)
+	}
+}
+
+// WriterExecutionLog - execution log written to w as JSON lines, e.g. os.Stdout.
+type WriterExecutionLog struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewWriterExecutionLog - execution log of JSON lines written to w.
+func NewWriterExecutionLog(w io.Writer) *WriterExecutionLog {
+	return &WriterExecutionLog{w: w}
+}
+
+func (wl *WriterExecutionLog) Append(records ...ExecutionRecord) error {
+	body, err := encodeLines(records)
+	if err != nil {
+		return err
+	}
+
+	wl.mu.Lock()
+	defer wl.mu.Unlock()
+
+	_, err = wl.w.Write(body)
+
+	return err
+}
+
+// FileExecutionLog - execution log appended to a file as JSON lines and rotated by size:
+// the full file is renamed to "<path>.1" (older ones shift up to "<path>.<backups>", the oldest is removed).
+type FileExecutionLog struct {
+	mu      sync.Mutex
+	path    string
+	maxSize int64
+	backups int
+	file    *os.File
+	size    int64
+}
+
+// NewFileExecutionLog - opens (or creates) the log file rotated when it exceeds maxSize bytes,
+// keeping backups rotated files (none if zero).
+func NewFileExecutionLog(path string, maxSize int64, backups int) (*FileExecutionLog, error) {
+	if maxSize <= 0 || backups < 0 {
+		return nil, errWrongRotation
+	}
+
+	fl := &FileExecutionLog{path: path, maxSize: maxSize, backups: backups}
+
+	if err := fl.open(); err != nil {
+		return nil, err
+	}
+
+	return fl, nil
+}
+
+func (fl *FileExecutionLog) open() error {
+	file, err := os.OpenFile(fl.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("open execution log: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+
+		return fmt.Errorf("stat execution log: %w", err)
+	}
+
+	fl.file, fl.size = file, info.Size()
+
+	return nil
+}
+
+func (fl *FileExecutionLog) Append(records ...ExecutionRecord) error {
+	body, err := encodeLines(records)
+	if err != nil {
+		return err
+	}
+
+	fl.mu.Lock()
+	defer fl.mu.Unlock()
+
+	if fl.size > 0 && fl.size+int64(len(body)) > fl.maxSize {
+		if err := fl.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := fl.file.Write(body)
+	fl.size += int64(n)
+
+	if err != nil {
+		return fmt.Errorf("write execution log: %w", err)
+	}
+
+	return nil
+}
+
+// rotate - shifts the backups and reopens the empty log, mu must be held.
+func (fl *FileExecutionLog) rotate() error {
+	if err := fl.file.Close(); err != nil {
+		return fmt.Errorf("close execution log: %w", err)
+	}
+
+	if fl.backups == 0 {
+		_ = os.Remove(fl.path)
+	}
+
+	for n := fl.backups; n > 0; n-- {
+		from := fl.path
+		if n > 1 {
+			from += "." + strconv.Itoa(n-1)
+		}
+
+		if err := os.Rename(from, fl.path+"."+strconv.Itoa(n)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("rotate execution log: %w", err)
+		}
+	}
+
+	return fl.open()
+}
+
+// Close - closes the log file.
+func (fl *FileExecutionLog) Close() error {
+	fl.mu.Lock()
+	defer fl.mu.Unlock()
+
+	return fl.file.Close()
+}
+
+func encodeLines(records []ExecutionRecord) ([]byte, error) {
+	var body []byte
+
+	for _, r := range records {
+		line, err := json.Marshal(r)
+		if err != nil {
+			return nil, fmt.Errorf("encode execution record: %w", err)
+		}
+
+		body = append(append(body, line...), '\n')
+	}
+
+	return body, nil
+}
@@ -0,0 +1,108 @@
+package healthz
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithExecutionLog(t *testing.T) {
+	var buf bytes.Buffer
+
+	svc := &mockService{scope: "db", dest: "pg", healthErr: errors.New("postgres://user:pass@pg/db refused")}
+	inspector := New(HealthCheckTarget{Service: svc, Groups: GroupReady})
+	assert.NoError(t, WithExecutionLog(NewWriterExecutionLog(&buf))(inspector))
+	assert.NoError(t, WithRetries(2, 0)(inspector))
+	assert.NoError(t, WithErrorRedactor(RedactCredentials)(inspector))
+
+	inspector.check(context.Background())
+
+	var records []map[string]any
+
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var r map[string]any
+
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &r))
+		records = append(records, r)
+	}
+
+	if assert.Len(t, records, 2, "every attempt is recorded") {
+		for n, r := range records {
+			assert.Equal(t, "pg", r["dest"])
+			assert.Equal(t, float64(1), r["cycle"])
+			assert.Equal(t, float64(n+1), r["attempt"])
+			assert.Equal(t, OutcomeError, r["outcome"])
+			assert.NotContains(t, r["error"], "pass", "errors are redacted")
+			assert.Contains(t, r, "started")
+			assert.Contains(t, r, "durationSeconds")
+		}
+	}
+}
+
+func TestWithExecutionLog_shadow(t *testing.T) {
+	var buf bytes.Buffer
+
+	active := New(HealthCheckTarget{Service: &mockService{scope: "db", dest: "pg"}, Groups: GroupReady})
+	assert.NoError(t, WithExecutionLog(NewWriterExecutionLog(&buf))(active))
+
+	active.check(context.Background())
+	assert.NotZero(t, buf.Len())
+
+	rollout, err := NewRollout(active)
+	assert.NoError(t, err)
+
+	candidate, err := active.WithConfig(Config{CheckPeriod: Duration(5 * time.Millisecond)})
+	assert.NoError(t, err)
+
+	buf.Reset()
+
+	assert.NoError(t, rollout.StartShadow(context.Background(), candidate))
+	assert.Eventually(t, func() bool {
+		return candidate.Snapshot().Seq > 1
+	}, time.Second, time.Millisecond)
+
+	assert.NoError(t, rollout.AbortShadow(context.Background()))
+	assert.Zero(t, buf.Len(), "the shadow doesn't share the execution log")
+}
+
+func TestFileExecutionLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checks.log")
+
+	fl, err := NewFileExecutionLog(path, 200, 2)
+	assert.NoError(t, err)
+
+	record := ExecutionRecord{Scope: "db", Dest: "pg", Cycle: 1, Attempt: 1, Started: time.Now(), Outcome: OutcomeOK}
+
+	for range 10 {
+		assert.NoError(t, fl.Append(record))
+	}
+
+	assert.NoError(t, fl.Close())
+
+	for _, name := range []string{path, path + ".1", path + ".2"} {
+		info, err := os.Stat(name)
+		if assert.NoError(t, err, name) {
+			assert.LessOrEqual(t, info.Size(), int64(200), name)
+		}
+	}
+
+	_, err = os.Stat(path + ".3")
+	assert.True(t, os.IsNotExist(err), "only backups are kept")
+
+	data, err := os.ReadFile(path + ".1")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasSuffix(string(data), "\n"), "whole lines are rotated")
+
+	_, err = NewFileExecutionLog(path, 0, 1)
+	assert.ErrorIs(t, err, errWrongRotation)
+}
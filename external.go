@@ -0,0 +1,195 @@
+package healthz
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const maxExternalBody = 1 << 16
+
+var (
+	errExternalNotReported = errors.New("external status not reported yet")
+	errExternalExpired     = errors.New("external status expired")
+	errExternalUnhealthy   = errors.New("reported unhealthy")
+	errWrongTTL            = errors.New("incorrect ttl")
+)
+
+// ExternalStatus - status of the external target pushed by an external system.
+type ExternalStatus struct {
+	Scope   string `json:"scope"`
+	Dest    string `json:"dest"`
+	Healthy bool   `json:"healthy"`
+	Error   string `json:"error,omitempty"`
+}
+
+// externalTarget - target whose health is reported by external systems (synthetic monitors, cron jobs).
+type externalTarget struct {
+	scope string
+	dest  string
+	ttl   time.Duration
+
+	mu         sync.RWMutex
+	err        error
+	reportedAt time.Time
+}
+
+func (et *externalTarget) Scope() string { return et.scope }
+func (et *externalTarget) Dest() string  { return et.dest }
+
+func (et *externalTarget) Health(context.Context) error {
+	et.mu.RLock()
+	defer et.mu.RUnlock()
+
+	switch {
+	case et.reportedAt.IsZero():
+		return errExternalNotReported
+	case time.Since(et.reportedAt) > et.ttl:
+		return errExternalExpired
+	default:
+		return et.err
+	}
+}
+
+func (et *externalTarget) report(status ExternalStatus) {
+	var err error
+
+	if !status.Healthy {
+		err = errExternalUnhealthy
+		if status.Error != "" {
+			err = errors.New(status.Error)
+		}
+	}
+
+	et.mu.Lock()
+	defer et.mu.Unlock()
+
+	et.err = err
+	et.reportedAt = time.Now()
+}
+
+// WithExternalTarget - adds a target reported by external systems via ExternalHandler,
+// the reported status is valid for ttl, then the target is unhealthy until the next report.
+func WithExternalTarget(scope, dest string, groups ProbeGroup, ttl time.Duration) Option {
+	return func(i *Inspector) error {
+		if err := groups.validate(); err != nil {
+			return err
+		}
+
+		if ttl <= 0 {
+			return errWrongTTL
+		}
+
+		i.targets = append(i.targets, HealthCheckTarget{
+			Service: &externalTarget{scope: scope, dest: dest, ttl: ttl},
+			Groups:  groups,
+		})
+
+		return nil
+	}
+}
+
+// ExternalHandler - receives ExternalStatus as JSON by POST, the request must be signed by the secret
+// like webhooks (see SignRequest): the body signature, timestamp within 5m and an event id not
+// received within them, so a captured request can't be replayed. The status is taken into account
+// on the next check cycle.
+func (i *Inspector) ExternalHandler(secret []byte) http.Handler {
+	receiver, _ := NewWebhookReceiver(secret, 0) // nil without the secret, requests are rejected
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+
+			return
+		}
+
+		if receiver == nil {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxExternalBody))
+		if err != nil {
+			http.Error(w, "bad status: "+err.Error(), http.StatusBadRequest)
+
+			return
+		}
+
+		if err := receiver.Verify(r.Header, body); err != nil {
+			http.Error(w, "unauthorized: "+err.Error(), http.StatusUnauthorized)
+
+			return
+		}
+
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		i.receiveExternal(w, r)
+	})
+}
+
+// ExternalTokenHandler - ExternalHandler for senders unable to sign requests, the request must have
+// header "Authorization: Bearer <token>". The token is static, a captured request could be replayed,
+// prefer ExternalHandler.
+func (i *Inspector) ExternalTokenHandler(token string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+
+			return
+		}
+
+		bearer, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if token == "" || subtle.ConstantTimeCompare([]byte(bearer), []byte(token)) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+
+			return
+		}
+
+		i.receiveExternal(w, r)
+	})
+}
+
+// receiveExternal - reports the ExternalStatus of the request body to its target.
+func (i *Inspector) receiveExternal(w http.ResponseWriter, r *http.Request) {
+	var status ExternalStatus
+
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxExternalBody)).Decode(&status); err != nil {
+		http.Error(w, "bad status: "+err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	target := i.externalTarget(status.Scope, status.Dest)
+	if target == nil {
+		http.Error(w, "unknown target", http.StatusNotFound)
+
+		return
+	}
+
+	target.report(status)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (i *Inspector) externalTarget(scope, dest string) *externalTarget {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	for _, target := range i.targets {
+		if et, ok := target.Service.(*externalTarget); ok && et.scope == scope && et.dest == dest {
+			return et
+		}
+	}
+
+	return nil
+}
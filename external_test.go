@@ -0,0 +1,109 @@
+package healthz
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExternalTarget(t *testing.T) {
+	inspector := New()
+	assert.Error(t, WithExternalTarget("cron", "backup", 0, time.Minute)(inspector))
+	assert.Error(t, WithExternalTarget("cron", "backup", GroupReady, 0)(inspector))
+	assert.NoError(t, WithExternalTarget("cron", "backup", GroupReady, 30*time.Millisecond)(inspector))
+
+	handler := inspector.ExternalHandler([]byte("s3cr3t"))
+
+	post := func(secret, body string) int {
+		req := httptest.NewRequest(http.MethodPost, "/healthz/external", strings.NewReader(body))
+		SignRequest(req, []byte(secret), "", []byte(body))
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		return w.Code
+	}
+
+	inspector.check(context.Background())
+	assert.ErrorIs(t, inspector.CheckGroup(GroupReady, true), errExternalNotReported)
+
+	assert.Equal(t, http.StatusUnauthorized, post("wrong", `{"scope":"cron","dest":"backup","healthy":true}`))
+	assert.Equal(t, http.StatusNotFound, post("s3cr3t", `{"scope":"cron","dest":"other","healthy":true}`))
+	assert.Equal(t, http.StatusBadRequest, post("s3cr3t", `{`))
+
+	assert.Equal(t, http.StatusNoContent, post("s3cr3t", `{"scope":"cron","dest":"backup","healthy":true}`))
+	inspector.check(context.Background())
+	assert.NoError(t, inspector.CheckGroup(GroupReady, true))
+
+	assert.Equal(t, http.StatusNoContent, post("s3cr3t", `{"scope":"cron","dest":"backup","healthy":false,"error":"disk full"}`))
+	inspector.check(context.Background())
+	assert.EqualError(t, inspector.CheckGroup(GroupReady, true), "disk full")
+
+	assert.Equal(t, http.StatusNoContent, post("s3cr3t", `{"scope":"cron","dest":"backup","healthy":true}`))
+	time.Sleep(40 * time.Millisecond)
+	inspector.check(context.Background())
+	assert.ErrorIs(t, inspector.CheckGroup(GroupReady, true), errExternalExpired)
+
+	t.Run("Replayed and stale requests", func(t *testing.T) {
+		body := `{"scope":"cron","dest":"backup","healthy":true}`
+
+		send := func(req *http.Request) int {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			return w.Code
+		}
+
+		req := httptest.NewRequest(http.MethodPost, "/healthz/external", strings.NewReader(body))
+		SignRequest(req, []byte("s3cr3t"), "event-1", []byte(body))
+		assert.Equal(t, http.StatusNoContent, send(req))
+
+		replayed := httptest.NewRequest(http.MethodPost, "/healthz/external", strings.NewReader(body))
+		replayed.Header = req.Header.Clone()
+		assert.Equal(t, http.StatusUnauthorized, send(replayed))
+
+		tampered := httptest.NewRequest(http.MethodPost, "/healthz/external", strings.NewReader(`{"scope":"cron","dest":"backup"}`))
+		SignRequest(tampered, []byte("s3cr3t"), "", []byte(body))
+		assert.Equal(t, http.StatusUnauthorized, send(tampered))
+
+		stale := httptest.NewRequest(http.MethodPost, "/healthz/external", strings.NewReader(body))
+		past := time.Now().Add(-time.Hour)
+		stale.Header.Set(HeaderWebhookTimestamp, strconv.FormatInt(past.Unix(), 10))
+		stale.Header.Set(HeaderWebhookID, "event-2")
+		stale.Header.Set(HeaderWebhookSignature, SignWebhook([]byte("s3cr3t"), "event-2", past, []byte(body)))
+		assert.Equal(t, http.StatusUnauthorized, send(stale))
+
+		w := httptest.NewRecorder()
+		inspector.ExternalHandler(nil).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/healthz/external", strings.NewReader(body)))
+		assert.Equal(t, http.StatusUnauthorized, w.Code, "no secret, no access")
+	})
+
+	t.Run("Token", func(t *testing.T) {
+		token := inspector.ExternalTokenHandler("s3cr3t")
+
+		post := func(bearer string) int {
+			req := httptest.NewRequest(http.MethodPost, "/healthz/external", strings.NewReader(`{"scope":"cron","dest":"backup","healthy":true}`))
+			req.Header.Set("Authorization", "Bearer "+bearer)
+
+			w := httptest.NewRecorder()
+			token.ServeHTTP(w, req)
+
+			return w.Code
+		}
+
+		assert.Equal(t, http.StatusUnauthorized, post("wrong"))
+		assert.Equal(t, http.StatusNoContent, post("s3cr3t"))
+	})
+
+	t.Run("Only POST", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz/external", nil))
+		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	})
+}
@@ -0,0 +1,129 @@
+package healthz
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Values of the "status" query param of StatusHandler, also StatusDegraded (ok includes degraded targets).
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+var errWrongQuery = errors.New("incorrect query param")
+
+// targetQuery - filter and page of the targets requested by query params.
+type targetQuery struct {
+	scopes []string
+	dests  []string
+	status string
+	groups ProbeGroup
+	offset int
+	limit  int // zero means no limit
+}
+
+// parseTargetQuery - params: scope, dest (repeated or comma separated), status (ok, fail, degraded),
+// group (names, e.g. "ready"), offset, limit. Reports whether any param is set.
+func parseTargetQuery(r *http.Request) (targetQuery, bool, error) {
+	q := r.URL.Query()
+
+	tq := targetQuery{
+		scopes: listParam(q["scope"]),
+		dests:  listParam(q["dest"]),
+		status: q.Get("status"),
+	}
+
+	switch tq.status {
+	case "", StatusOK, StatusFail, string(StatusDegraded):
+	default:
+		return targetQuery{}, false, fmt.Errorf("%w: status %q, want %s, %s or %s",
+			errWrongQuery, tq.status, StatusOK, StatusFail, StatusDegraded)
+	}
+
+	if names := listParam(q["group"]); len(names) > 0 {
+		groups, err := ParseGroups(names)
+		if err != nil {
+			return targetQuery{}, false, fmt.Errorf("%w: group: %w", errWrongQuery, err)
+		}
+
+		tq.groups = groups
+	}
+
+	for name, dst := range map[string]*int{"offset": &tq.offset, "limit": &tq.limit} {
+		v := q.Get(name)
+		if v == "" {
+			continue
+		}
+
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return targetQuery{}, false, fmt.Errorf("%w: %s %q", errWrongQuery, name, v)
+		}
+
+		*dst = n
+	}
+
+	set := false
+
+	for _, name := range []string{"scope", "dest", "status", "group", "offset", "limit"} {
+		set = set || q.Has(name)
+	}
+
+	return tq, set, nil
+}
+
+func listParam(values []string) []string {
+	var list []string
+
+	for _, v := range values {
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+	}
+
+	return list
+}
+
+func (tq targetQuery) match(tr TargetResult) bool {
+	switch {
+	case len(tq.scopes) > 0 && !slices.Contains(tq.scopes, tr.Scope):
+		return false
+	case len(tq.dests) > 0 && !slices.Contains(tq.dests, tr.Dest):
+		return false
+	case tq.status == StatusOK && !tr.Healthy(), tq.status == StatusFail && tr.Healthy():
+		return false
+	case tq.status == string(StatusDegraded) && tr.Status() != StatusDegraded:
+		return false
+	case tq.groups != 0 && tr.Groups&tq.groups == 0:
+		return false
+	default:
+		return true
+	}
+}
+
+// apply - page of the matching targets and count of all matching ones.
+func (tq targetQuery) apply(targets []TargetResult) ([]TargetResult, int) {
+	matched := make([]TargetResult, 0, len(targets))
+
+	for _, tr := range targets {
+		if tq.match(tr) {
+			matched = append(matched, tr)
+		}
+	}
+
+	total := len(matched)
+	page := matched[min(tq.offset, total):]
+
+	if tq.limit > 0 && len(page) > tq.limit {
+		page = page[:tq.limit]
+	}
+
+	return page, total
+}
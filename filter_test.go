@@ -0,0 +1,69 @@
+package healthz
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusHandler_filter(t *testing.T) {
+	fail := errors.New("fail")
+
+	inspector := New(
+		HealthCheckTarget{Service: &mockService{scope: "database", dest: "pg-1"}, Groups: GroupReady},
+		HealthCheckTarget{Service: &mockService{scope: "database", dest: "pg-2", healthErr: fail}, Groups: GroupReady},
+		HealthCheckTarget{Service: &mockService{scope: "database", dest: "pg-3", healthErr: fail}, Groups: GroupReady},
+		HealthCheckTarget{Service: &mockService{scope: "cache", dest: "redis", healthErr: fail}, Groups: GroupLive},
+		HealthCheckTarget{Service: &mockService{scope: "kafka", dest: "k-1"}, Groups: GroupLive},
+	)
+	inspector.check(context.Background())
+
+	tests := []struct {
+		name  string
+		query string
+		dests []string
+		total int
+	}{
+		{name: "test.1 scope", query: "?scope=database", dests: []string{"pg-1", "pg-2", "pg-3"}, total: 3},
+		{name: "test.2 scope and status", query: "?scope=database&status=fail", dests: []string{"pg-2", "pg-3"}, total: 2},
+		{name: "test.3 several scopes", query: "?scope=cache,kafka", dests: []string{"redis", "k-1"}, total: 2},
+		{name: "test.4 group and status", query: "?group=live&status=ok", dests: []string{"k-1"}, total: 1},
+		{name: "test.5 page", query: "?status=fail&limit=2&offset=1", dests: []string{"pg-3", "redis"}, total: 3},
+		{name: "test.6 offset out of range", query: "?offset=10", dests: []string{}, total: 5},
+		{name: "test.7 dest", query: "?dest=pg-2&dest=redis", dests: []string{"pg-2", "redis"}, total: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			inspector.StatusHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz/status"+tt.query, nil))
+
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			var body struct {
+				Targets []TargetResult `json:"targets"`
+				Total   int            `json:"total"`
+			}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+
+			dests := []string{}
+			for _, tr := range body.Targets {
+				dests = append(dests, tr.Dest)
+			}
+
+			assert.Equal(t, tt.dests, dests)
+			assert.Equal(t, tt.total, body.Total)
+		})
+	}
+
+	for _, query := range []string{"?status=down", "?limit=-1", "?offset=x", "?group=unknown"} {
+		w := httptest.NewRecorder()
+		inspector.StatusHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz/status"+query, nil))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
@@ -0,0 +1,347 @@
+package healthz
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Names of the built-in formatters.
+const (
+	FormatPlain        = "plain"
+	FormatJSON         = "json"
+	FormatKubeVerbose  = "kube-verbose"
+	FormatJSONDetailed = "json-detailed"
+	FormatHealthJSON   = "health+json" // application/health+json of draft-inadarei-api-health-check
+)
+
+var (
+	errUnknownFormat  = errors.New("unknown response format")
+	errWrongFormatter = errors.New("formatter must have name and Format func")
+)
+
+// ProbeReport - evaluation of a probe group passed to a Formatter.
+type ProbeReport struct {
+	Group   ProbeGroup
+	Err     error
+	Targets []TargetResult // targets of the group
+	Changed []Change       // recent transitions of the group targets
+	// start of the evaluated check cycle, zero if not yet checked
+	CheckedAt time.Time
+	Seq       uint64 // sequence number of the evaluated result, see Snapshot.Seq
+	// custom texts of the group and of the targets keyed by "scope/dest",
+	// see WithGroupMessages, WithTargetMessages
+	Messages       Messages
+	TargetMessages map[string]Messages
+}
+
+// Status - status of the group: unhealthy if it failed, degraded if it passed with degraded targets.
+func (r ProbeReport) Status() Status {
+	if r.Err != nil {
+		return StatusUnhealthy
+	}
+
+	for _, tr := range r.Targets {
+		if tr.Status() == StatusDegraded {
+			return StatusDegraded
+		}
+	}
+
+	return StatusHealthy
+}
+
+// Age - how old the evaluation is, zero if not yet checked.
+func (r ProbeReport) Age() time.Duration {
+	if r.CheckedAt.IsZero() {
+		return 0
+	}
+
+	return time.Since(r.CheckedAt)
+}
+
+// Formatter - renders the body of probe responses.
+type Formatter struct {
+	ContentType string
+	Format      func(ProbeReport) []byte
+}
+
+var (
+	formattersMu sync.RWMutex
+	formatters   = map[string]Formatter{
+		FormatPlain:        {ContentType: "text/plain; charset=utf-8", Format: formatPlain},
+		FormatJSON:         {ContentType: "application/json", Format: formatJSON},
+		FormatKubeVerbose:  {ContentType: "text/plain; charset=utf-8", Format: formatKubeVerbose},
+		FormatJSONDetailed: {ContentType: "application/json", Format: formatJSONDetailed},
+		FormatHealthJSON:   {ContentType: "application/health+json", Format: formatHealthJSON},
+	}
+)
+
+// RegisterFormatter - adds (or replaces) a formatter selectable by name via WithResponseFormat.
+func RegisterFormatter(name string, f Formatter) error {
+	if name == "" || f.Format == nil {
+		return errWrongFormatter
+	}
+
+	formattersMu.Lock()
+	defer formattersMu.Unlock()
+
+	formatters[name] = f
+
+	return nil
+}
+
+func lookupFormatter(name string) (Formatter, error) {
+	formattersMu.RLock()
+	defer formattersMu.RUnlock()
+
+	f, ok := formatters[name]
+	if !ok {
+		return Formatter{}, fmt.Errorf("%w: %q", errUnknownFormat, name)
+	}
+
+	return f, nil
+}
+
+// WithResponseFormat - selects the formatter of the response strategy used by HealthHandler.
+func WithResponseFormat(name string) Option {
+	return func(i *Inspector) error {
+		f, err := lookupFormatter(name)
+		if err != nil {
+			return err
+		}
+
+		i.response.Formatter = f
+
+		return nil
+	}
+}
+
+func formatPlain(r ProbeReport) []byte {
+	return []byte(r.Messages.text(r.Err == nil, string(DefResponseProcessor(r.Err))))
+}
+
+// probeView - json output of the group evaluation.
+type probeView struct {
+	Status     string     `json:"status"`
+	Group      string     `json:"group"`
+	Error      string     `json:"error,omitempty"`
+	Message    string     `json:"message,omitempty"`
+	CheckedAt  *time.Time `json:"checkedAt,omitempty"`
+	AgeSeconds *float64   `json:"ageSeconds,omitempty"`
+	Changed    []Change   `json:"changed,omitempty"`
+}
+
+func newProbeView(r ProbeReport) probeView {
+	view := probeView{
+		Status:  "ok",
+		Group:   r.Group.String(),
+		Message: r.Messages.text(r.Err == nil, ""),
+		Changed: r.Changed,
+	}
+
+	switch r.Status() {
+	case StatusUnhealthy:
+		view.Status = string(StatusUnhealthy)
+		view.Error = r.Err.Error()
+	case StatusDegraded:
+		view.Status = string(StatusDegraded)
+	}
+
+	if !r.CheckedAt.IsZero() {
+		age := r.Age().Seconds()
+
+		view.CheckedAt = &r.CheckedAt
+		view.AgeSeconds = &age
+	}
+
+	return view
+}
+
+func formatJSON(r ProbeReport) []byte {
+	body, _ := json.Marshal(newProbeView(r))
+
+	return body
+}
+
+// targetView - per target part of the json-detailed output.
+type targetView struct {
+	Scope           string     `json:"scope"`
+	Dest            string     `json:"dest"`
+	Status          string     `json:"status"`
+	Error           string     `json:"error,omitempty"`
+	Message         string     `json:"message,omitempty"`
+	CheckedAt       *time.Time `json:"checkedAt,omitempty"`
+	DurationSeconds *float64   `json:"durationSeconds,omitempty"`
+}
+
+// formatJSONDetailed - json output with the result of every target of the group.
+func formatJSONDetailed(r ProbeReport) []byte {
+	targets := make([]targetView, 0, len(r.Targets))
+
+	for _, tr := range r.Targets {
+		tv := targetView{
+			Scope:   tr.Scope,
+			Dest:    tr.Dest,
+			Status:  "ok",
+			Message: r.TargetMessages[targetKey(tr.Scope, tr.Dest)].text(tr.Err == nil, ""),
+		}
+
+		switch tr.Status() {
+		case StatusUnhealthy:
+			tv.Status = string(StatusUnhealthy)
+			tv.Error = tr.Err.Error()
+		case StatusDegraded:
+			tv.Status = string(StatusDegraded)
+			tv.Error = tr.Degraded.Error()
+		case StatusMaintenance, StatusDisabled:
+			tv.Status = string(tr.Status())
+		}
+
+		if !tr.CheckedAt.IsZero() {
+			tv.CheckedAt = &tr.CheckedAt
+		}
+
+		if tr.Duration > 0 {
+			d := tr.Duration.Seconds()
+			tv.DurationSeconds = &d
+		}
+
+		targets = append(targets, tv)
+	}
+
+	body, _ := json.Marshal(struct {
+		probeView
+		Targets []targetView `json:"targets"`
+	}{newProbeView(r), targets})
+
+	return body
+}
+
+// healthJSONStatus - status values of the health+json format.
+func healthJSONStatus(s Status) string {
+	switch s {
+	case StatusUnhealthy:
+		return "fail"
+	case StatusDegraded:
+		return "warn"
+	default:
+		return "pass"
+	}
+}
+
+// healthJSONCheck - entry of the checks map of the health+json output.
+type healthJSONCheck struct {
+	ComponentID   string     `json:"componentId"`
+	ComponentType string     `json:"componentType"`
+	ObservedValue *float64   `json:"observedValue,omitempty"`
+	ObservedUnit  string     `json:"observedUnit,omitempty"`
+	Status        string     `json:"status"`
+	Time          *time.Time `json:"time,omitempty"`
+	Output        string     `json:"output,omitempty"`
+}
+
+// formatHealthJSON - output of draft-inadarei-api-health-check: checks are keyed by "scope:dest",
+// componentType is the scope and observedValue is the duration of the last check in seconds.
+func formatHealthJSON(r ProbeReport) []byte {
+	checks := make(map[string][]healthJSONCheck, len(r.Targets))
+
+	for _, tr := range r.Targets {
+		check := healthJSONCheck{
+			ComponentID:   tr.Dest,
+			ComponentType: tr.Scope,
+			Status:        healthJSONStatus(tr.Status()),
+		}
+
+		switch tr.Status() {
+		case StatusUnhealthy:
+			check.Output = tr.Err.Error()
+		case StatusDegraded:
+			check.Output = tr.Degraded.Error()
+		}
+
+		if !tr.CheckedAt.IsZero() {
+			check.Time = &tr.CheckedAt
+		}
+
+		if tr.Duration > 0 {
+			d := tr.Duration.Seconds()
+			check.ObservedValue, check.ObservedUnit = &d, "s"
+		}
+
+		key := tr.Scope + ":" + tr.Dest
+		checks[key] = append(checks[key], check)
+	}
+
+	view := struct {
+		Status      string                       `json:"status"`
+		Description string                       `json:"description,omitempty"`
+		Output      string                       `json:"output,omitempty"`
+		Checks      map[string][]healthJSONCheck `json:"checks"`
+	}{
+		Status:      healthJSONStatus(r.Status()),
+		Description: r.Messages.text(r.Err == nil, ""),
+		Checks:      checks,
+	}
+
+	if r.Err != nil {
+		view.Output = r.Err.Error()
+	}
+
+	body, _ := json.Marshal(view)
+
+	return body
+}
+
+// formatKubeVerbose - output like kube-apiserver /readyz?verbose.
+func formatKubeVerbose(r ProbeReport) []byte {
+	var buf bytes.Buffer
+
+	for _, tr := range r.Targets {
+		msgs := r.TargetMessages[targetKey(tr.Scope, tr.Dest)]
+
+		switch {
+		case tr.Err == nil && tr.Degraded != nil:
+			fmt.Fprintf(&buf, "[+]%s/%s degraded: %s\n", tr.Scope, tr.Dest, oneLine(tr.Degraded.Error()))
+
+			continue
+		case tr.Err == nil:
+			fmt.Fprintf(&buf, "[+]%s/%s %s\n", tr.Scope, tr.Dest, msgs.text(true, "ok"))
+
+			continue
+		}
+
+		fmt.Fprintf(&buf, "[-]%s/%s %s: %s\n", tr.Scope, tr.Dest, msgs.text(false, "failed"), oneLine(tr.Err.Error()))
+	}
+
+	if len(r.Changed) > 0 {
+		buf.WriteString("changed:\n")
+
+		for _, c := range r.Changed {
+			state := "healthy"
+			if !c.Healthy {
+				state = "unhealthy"
+			}
+
+			fmt.Fprintf(&buf, "  %s %s/%s %s\n", c.Time.Format(time.RFC3339), c.Scope, c.Dest, state)
+		}
+	}
+
+	switch {
+	case r.Messages.text(r.Err == nil, "") != "":
+		fmt.Fprintf(&buf, "%s\n", r.Messages.text(r.Err == nil, ""))
+	case r.Err != nil:
+		fmt.Fprintf(&buf, "%s check failed\n", r.Group)
+	default:
+		fmt.Fprintf(&buf, "%s check passed\n", r.Group)
+	}
+
+	return buf.Bytes()
+}
+
+func oneLine(s string) string {
+	return strings.ReplaceAll(s, "\n", "; ")
+}
@@ -0,0 +1,277 @@
+package healthz
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProbeGroup_String(t *testing.T) {
+	assert.Equal(t, "ready", GroupReady.String())
+	assert.Equal(t, "live|ready", (GroupLive | GroupReady).String())
+	assert.Equal(t, "common|startup|live|ready", AllGroups.String())
+	assert.Equal(t, "ready|16", (GroupReady | 16).String())
+}
+
+func TestResponseFormats(t *testing.T) {
+	inspector := New(
+		HealthCheckTarget{Service: &mockService{scope: "database", dest: "pg-1"}, Groups: GroupReady},
+		HealthCheckTarget{Service: &mockService{scope: "kafka", dest: "k-1", healthErr: errors.New("fail")}, Groups: GroupReady},
+		HealthCheckTarget{Service: &mockService{scope: "cache", dest: "redis"}, Groups: GroupLive},
+	)
+	inspector.check(context.Background())
+
+	tests := []struct {
+		name        string
+		format      string
+		contentType string
+		body        string
+		freshness   bool // body has checkedAt and ageSeconds
+	}{
+		{
+			name:        "test.1 plain",
+			format:      FormatPlain,
+			contentType: "text/plain; charset=utf-8",
+			body:        "Unhealthy",
+		},
+		{
+			name:        "test.2 json",
+			format:      FormatJSON,
+			contentType: "application/json",
+			body:        `{"status":"unhealthy","group":"ready","error":"fail"}`,
+			freshness:   true,
+		},
+		{
+			name:        "test.3 kube-verbose",
+			format:      FormatKubeVerbose,
+			contentType: "text/plain; charset=utf-8",
+			body:        "[+]database/pg-1 ok\n[-]kafka/k-1 failed: fail\nready check failed\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.NoError(t, WithResponseFormat(tt.format)(inspector))
+
+			w := httptest.NewRecorder()
+			inspector.HealthHandler(GroupReady, true, nil)(w, httptest.NewRequest(http.MethodGet, "/healthz/ready", nil))
+
+			assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+			assert.Equal(t, tt.contentType, w.Header().Get("Content-Type"))
+			assert.NotEmpty(t, w.Header().Get(HeaderCheckedAt))
+			assert.NotEmpty(t, w.Header().Get(HeaderAge))
+
+			if !tt.freshness {
+				assert.Equal(t, tt.body, w.Body.String())
+
+				return
+			}
+
+			var body map[string]any
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Contains(t, body, "checkedAt")
+			assert.Contains(t, body, "ageSeconds")
+
+			delete(body, "checkedAt")
+			delete(body, "ageSeconds")
+
+			stripped, _ := json.Marshal(body)
+			assert.JSONEq(t, tt.body, string(stripped))
+		})
+	}
+}
+
+func TestFormatJSONDetailed(t *testing.T) {
+	inspector := New(
+		HealthCheckTarget{Service: &mockService{scope: "database", dest: "pg-1"}, Groups: GroupReady},
+		HealthCheckTarget{Service: &mockService{scope: "kafka", dest: "k-1", healthErr: errors.New("fail")}, Groups: GroupReady},
+		HealthCheckTarget{Service: &mockService{scope: "cache", dest: "redis"}, Groups: GroupLive},
+	)
+	assert.NoError(t, WithResponseFormat(FormatJSONDetailed)(inspector))
+	assert.NoError(t, WithTargetMessages("kafka", "k-1", Messages{Unhealthy: "DOWN"})(inspector))
+	inspector.check(context.Background())
+
+	w := httptest.NewRecorder()
+	inspector.HealthHandler(GroupReady, true, nil)(w, httptest.NewRequest(http.MethodGet, "/healthz/ready", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	var body struct {
+		Status  string `json:"status"`
+		Group   string `json:"group"`
+		Targets []struct {
+			Scope           string    `json:"scope"`
+			Dest            string    `json:"dest"`
+			Status          string    `json:"status"`
+			Error           string    `json:"error"`
+			Message         string    `json:"message"`
+			CheckedAt       time.Time `json:"checkedAt"`
+			DurationSeconds *float64  `json:"durationSeconds"`
+		} `json:"targets"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+
+	assert.Equal(t, "unhealthy", body.Status)
+	assert.Equal(t, "ready", body.Group)
+
+	if assert.Len(t, body.Targets, 2) {
+		assert.Equal(t, "database", body.Targets[0].Scope)
+		assert.Equal(t, "ok", body.Targets[0].Status)
+		assert.Empty(t, body.Targets[0].Error)
+
+		assert.Equal(t, "k-1", body.Targets[1].Dest)
+		assert.Equal(t, "unhealthy", body.Targets[1].Status)
+		assert.Equal(t, "fail", body.Targets[1].Error)
+		assert.Equal(t, "DOWN", body.Targets[1].Message)
+
+		for _, tv := range body.Targets {
+			assert.False(t, tv.CheckedAt.IsZero())
+			assert.NotNil(t, tv.DurationSeconds)
+		}
+	}
+}
+
+func TestFormatHealthJSON(t *testing.T) {
+	inspector := New(
+		HealthCheckTarget{Service: &mockService{scope: "database", dest: "pg-1"}, Groups: GroupReady},
+		HealthCheckTarget{Service: &mockService{scope: "kafka", dest: "k-1", healthErr: errors.New("fail")}, Groups: GroupReady},
+		HealthCheckTarget{Service: &mockService{scope: "quota", dest: "api", healthErr: Degraded(errors.New("low"))}, Groups: GroupLive},
+	)
+	assert.NoError(t, WithResponseFormat(FormatHealthJSON)(inspector))
+	inspector.check(context.Background())
+
+	type check struct {
+		ComponentID   string    `json:"componentId"`
+		ComponentType string    `json:"componentType"`
+		ObservedValue *float64  `json:"observedValue"`
+		ObservedUnit  string    `json:"observedUnit"`
+		Status        string    `json:"status"`
+		Time          time.Time `json:"time"`
+		Output        string    `json:"output"`
+	}
+
+	var body struct {
+		Status string             `json:"status"`
+		Output string             `json:"output"`
+		Checks map[string][]check `json:"checks"`
+	}
+
+	w := httptest.NewRecorder()
+	inspector.HealthHandler(GroupReady, true, nil)(w, httptest.NewRequest(http.MethodGet, "/healthz/ready", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "application/health+json", w.Header().Get("Content-Type"))
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+
+	assert.Equal(t, "fail", body.Status)
+	assert.NotEmpty(t, body.Output)
+
+	if assert.Len(t, body.Checks, 2) {
+		pg := body.Checks["database:pg-1"]
+		if assert.Len(t, pg, 1) {
+			assert.Equal(t, "pg-1", pg[0].ComponentID)
+			assert.Equal(t, "database", pg[0].ComponentType)
+			assert.Equal(t, "pass", pg[0].Status)
+			assert.Equal(t, "s", pg[0].ObservedUnit)
+			assert.NotNil(t, pg[0].ObservedValue)
+			assert.False(t, pg[0].Time.IsZero())
+		}
+
+		k := body.Checks["kafka:k-1"]
+		if assert.Len(t, k, 1) {
+			assert.Equal(t, "fail", k[0].Status)
+			assert.Equal(t, "fail", k[0].Output)
+		}
+	}
+
+	body.Checks, body.Output = nil, ""
+
+	w = httptest.NewRecorder()
+	inspector.HealthHandler(GroupLive, true, nil)(w, httptest.NewRequest(http.MethodGet, "/healthz/live", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "warn", body.Status)
+	assert.Empty(t, body.Output)
+
+	if assert.Len(t, body.Checks["quota:api"], 1) {
+		assert.Equal(t, "warn", body.Checks["quota:api"][0].Status)
+		assert.Equal(t, "low", body.Checks["quota:api"][0].Output)
+	}
+}
+
+func TestRegisterFormatter(t *testing.T) {
+	assert.Error(t, RegisterFormatter("", Formatter{Format: formatPlain}))
+	assert.Error(t, RegisterFormatter("nil-func", Formatter{}))
+	assert.Error(t, WithResponseFormat("not-registered")(New()))
+
+	err := RegisterFormatter("legacy", Formatter{
+		ContentType: "text/plain",
+		Format: func(r ProbeReport) []byte {
+			if r.Err != nil {
+				return []byte("DOWN")
+			}
+
+			return []byte("UP")
+		},
+	})
+	assert.NoError(t, err)
+
+	inspector := New(HealthCheckTarget{Service: &mockService{}, Groups: GroupLive})
+	inspector.check(context.Background())
+	assert.NoError(t, WithResponseFormat("legacy")(inspector))
+
+	w := httptest.NewRecorder()
+	inspector.HealthHandler(GroupLive, true, nil)(w, httptest.NewRequest(http.MethodGet, "/healthz/live", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "UP", w.Body.String())
+}
+
+func TestCustomMessages(t *testing.T) {
+	inspector := New(
+		HealthCheckTarget{Service: &mockService{scope: "database", dest: "pg-1"}, Groups: GroupReady | GroupStartup},
+		HealthCheckTarget{Service: &mockService{scope: "kafka", dest: "k-1", healthErr: errors.New("fail")}, Groups: GroupReady},
+	)
+	assert.NoError(t, WithGroupMessages(GroupReady, Messages{Healthy: "READY", Unhealthy: "DEGRADED"})(inspector))
+	assert.NoError(t, WithGroupMessages(GroupStartup, Messages{Healthy: "STARTED"})(inspector))
+	assert.NoError(t, WithTargetMessages("kafka", "k-1", Messages{Unhealthy: "DOWN"})(inspector))
+	inspector.check(context.Background())
+
+	tests := []struct {
+		name   string
+		format string
+		group  ProbeGroup
+		body   string
+	}{
+		{name: "test.1 plain unhealthy", format: FormatPlain, group: GroupReady, body: "DEGRADED"},
+		{name: "test.2 plain healthy", format: FormatPlain, group: GroupStartup, body: "STARTED"},
+		{name: "test.3 kube-verbose", format: FormatKubeVerbose, group: GroupReady, body: "[+]database/pg-1 ok\n[-]kafka/k-1 DOWN: fail\nDEGRADED\n"},
+		{name: "test.4 plain without messages", format: FormatPlain, group: GroupLive, body: "OK"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.NoError(t, WithResponseFormat(tt.format)(inspector))
+
+			w := httptest.NewRecorder()
+			inspector.HealthHandler(tt.group, true, nil)(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+			assert.Equal(t, tt.body, w.Body.String())
+		})
+	}
+
+	assert.NoError(t, WithResponseFormat(FormatJSON)(inspector))
+
+	w := httptest.NewRecorder()
+	inspector.HealthHandler(GroupReady, true, nil)(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Contains(t, w.Body.String(), `"message":"DEGRADED"`)
+
+	assert.ErrorIs(t, WithGroupMessages(GroupReady, Messages{})(inspector), errEmptyMessages)
+	assert.Error(t, WithGroupMessages(251, Messages{Healthy: "UP"})(inspector))
+}
@@ -0,0 +1,71 @@
+// Package fxhealthz - Uber fx integration of the healthz inspector: one fx.Provide
+// (or Module) wires the inspector, its targets and the lifecycle hooks.
+package fxhealthz
+
+import (
+	"context"
+
+	"github.com/art-frela/healthz"
+	"go.uber.org/fx"
+)
+
+// Names of the value groups collected by the module.
+const (
+	TargetsGroup = "healthz.targets"
+	OptionsGroup = "healthz.options"
+)
+
+// Module - provides *healthz.Inspector built of the TargetsGroup targets and OptionsGroup options,
+// the inspector is started and stopped by the fx lifecycle.
+var Module = fx.Module("healthz", fx.Provide(New))
+
+// Params - dependencies of the inspector.
+type Params struct {
+	fx.In
+
+	Lifecycle fx.Lifecycle
+	Targets   []healthz.HealthCheckTarget `group:"healthz.targets"`
+	Options   []healthz.Option            `group:"healthz.options"`
+}
+
+// New - inspector of the targets registered in the lifecycle.
+func New(p Params) (*healthz.Inspector, error) {
+	inspector := healthz.New(p.Targets...)
+
+	for _, opt := range p.Options {
+		if err := opt(inspector); err != nil {
+			return nil, err
+		}
+	}
+
+	// the start context of fx is done after start, the check loop lives until OnStop
+	runCtx, cancel := context.WithCancel(context.Background())
+
+	p.Lifecycle.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			return inspector.Start(runCtx)
+		},
+		OnStop: func(ctx context.Context) error {
+			defer cancel()
+
+			return inspector.Stop(ctx)
+		},
+	})
+
+	return inspector, nil
+}
+
+// AsTarget - annotates constructor returning healthz.HealthCheckTarget to contribute it
+// to the TargetsGroup:
+//
+//	fx.Provide(fxhealthz.AsTarget(newDBTarget))
+func AsTarget(constructor any) any {
+	return fx.Annotate(constructor, fx.ResultTags(`group:"`+TargetsGroup+`"`))
+}
+
+// AsOption - annotates constructor returning healthz.Option to apply it to the inspector:
+//
+//	fx.Provide(fxhealthz.AsOption(func() healthz.Option { return healthz.WithCheckPeriod(5 * time.Second) }))
+func AsOption(constructor any) any {
+	return fx.Annotate(constructor, fx.ResultTags(`group:"`+OptionsGroup+`"`))
+}
@@ -0,0 +1,59 @@
+package fxhealthz
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/art-frela/healthz"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+type service struct{ dest string }
+
+func (s *service) Health(context.Context) error { return nil }
+func (s *service) Scope() string                { return "fx" }
+func (s *service) Dest() string                 { return s.dest }
+
+func TestModule(t *testing.T) {
+	var inspector *healthz.Inspector
+
+	app := fxtest.New(t,
+		Module,
+		fx.Provide(
+			AsTarget(func() healthz.HealthCheckTarget {
+				return healthz.HealthCheckTarget{Service: &service{dest: "a"}, Groups: healthz.GroupReady}
+			}),
+			AsTarget(func() healthz.HealthCheckTarget {
+				return healthz.HealthCheckTarget{Service: &service{dest: "b"}, Groups: healthz.GroupLive}
+			}),
+			AsOption(func() healthz.Option { return healthz.WithCheckPeriod(time.Second) }),
+		),
+		fx.Populate(&inspector),
+	)
+
+	app.RequireStart()
+
+	assert.Equal(t, healthz.StateRunning, inspector.Status().State)
+	assert.Eventually(t, func() bool {
+		return inspector.CheckGroup(healthz.GroupLive|healthz.GroupReady, true) == nil
+	}, time.Second, time.Millisecond)
+	assert.Len(t, inspector.Snapshot().Targets, 2)
+
+	app.RequireStop()
+
+	assert.Equal(t, healthz.StateStopped, inspector.Status().State)
+}
+
+func TestModule_wrongOption(t *testing.T) {
+	app := fx.New(
+		Module,
+		fx.Provide(AsOption(func() healthz.Option { return healthz.WithCheckPeriod(-1) })),
+		fx.Invoke(func(*healthz.Inspector) {}),
+		fx.NopLogger,
+	)
+
+	assert.Error(t, app.Err())
+}
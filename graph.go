@@ -0,0 +1,181 @@
+package healthz
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+var errUnknownDependency = errors.New("unknown dependency target")
+
+// GraphNode - target of the dependency graph, ID is "scope/dest".
+type GraphNode struct {
+	ID        string `json:"id"`
+	Scope     string `json:"scope"`
+	Dest      string `json:"dest"`
+	Healthy   bool   `json:"healthy"`
+	Error     string `json:"error,omitempty"`
+	RootCause bool   `json:"rootCause,omitempty"` // unhealthy while all its dependencies are healthy
+}
+
+// GraphEdge - dependency of the From target on the To target.
+type GraphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Graph - targets and their declared dependencies (HealthCheckTarget.DependsOn).
+type Graph struct {
+	Nodes []GraphNode `json:"nodes"`
+	Edges []GraphEdge `json:"edges"`
+}
+
+// Graph - dependency topology of the targets with results of the last check cycle,
+// shows why readiness is failing through the dependency chain.
+func (i *Inspector) Graph() Graph {
+	if i.parent != nil {
+		return i.parent.Graph().scoped(i.scope)
+	}
+
+	i.mu.RLock()
+	targets := i.targets
+	i.mu.RUnlock()
+
+	results := make(map[string]TargetResult)
+
+	for _, tr := range i.result().targets {
+		results[targetKey(tr.Scope, tr.Dest)] = tr
+	}
+
+	var graph Graph
+
+	idx := make(map[string]int)
+
+	addNode := func(scope, dest string) {
+		id := targetKey(scope, dest)
+		if _, ok := idx[id]; ok {
+			return
+		}
+
+		node := GraphNode{ID: id, Scope: scope, Dest: dest, Healthy: true}
+
+		tr, ok := results[id]
+
+		switch {
+		case !ok:
+			node.Healthy = false
+			node.Error = errNoYetChecked.Error()
+		case tr.Err != nil:
+			node.Healthy = false
+			node.Error = i.redact(tr.Err).Error()
+		}
+
+		idx[id] = len(graph.Nodes)
+		graph.Nodes = append(graph.Nodes, node)
+	}
+
+	for _, target := range targets {
+		addNode(target.Service.Scope(), target.Service.Dest())
+	}
+
+	for _, target := range targets {
+		from := targetKey(target.Service.Scope(), target.Service.Dest())
+
+		for _, to := range target.DependsOn {
+			if _, ok := idx[to]; !ok {
+				scope, dest, _ := strings.Cut(to, "/")
+				idx[to] = len(graph.Nodes)
+				graph.Nodes = append(graph.Nodes, GraphNode{ID: to, Scope: scope, Dest: dest, Error: errUnknownDependency.Error()})
+			}
+
+			graph.Edges = append(graph.Edges, GraphEdge{From: from, To: to})
+		}
+	}
+
+	for n, node := range graph.Nodes {
+		if node.Healthy {
+			continue
+		}
+
+		graph.Nodes[n].RootCause = true
+
+		for _, e := range graph.Edges {
+			if e.From == node.ID && !graph.Nodes[idx[e.To]].Healthy {
+				graph.Nodes[n].RootCause = false
+
+				break
+			}
+		}
+	}
+
+	return graph
+}
+
+// scoped - nodes of the scope with their edges and dependencies.
+func (g Graph) scoped(scope string) Graph {
+	keep := make(map[string]bool)
+
+	var next Graph
+
+	for _, e := range g.Edges {
+		if strings.HasPrefix(e.From, scope+"/") {
+			next.Edges = append(next.Edges, e)
+			keep[e.To] = true
+		}
+	}
+
+	for _, node := range g.Nodes {
+		if node.Scope == scope || keep[node.ID] {
+			next.Nodes = append(next.Nodes, node)
+		}
+	}
+
+	return next
+}
+
+// DOT - graph in the Graphviz DOT language, unhealthy targets are red, root causes are bold.
+func (g Graph) DOT() []byte {
+	var buf bytes.Buffer
+
+	buf.WriteString("digraph healthz {\n")
+
+	for _, node := range g.Nodes {
+		attrs := `color="green"`
+
+		switch {
+		case node.RootCause:
+			attrs = `color="red", style="bold"`
+		case !node.Healthy:
+			attrs = `color="red"`
+		}
+
+		fmt.Fprintf(&buf, "  %q [%s];\n", node.ID, attrs)
+	}
+
+	for _, e := range g.Edges {
+		fmt.Fprintf(&buf, "  %q -> %q;\n", e.From, e.To)
+	}
+
+	buf.WriteString("}\n")
+
+	return buf.Bytes()
+}
+
+// GraphHandler - serves the dependency graph as JSON (or by a negotiated codec) or as DOT with ?format=dot,
+// e.g. on /healthz/graph.
+func (i *Inspector) GraphHandler() http.Handler {
+	return Gzip(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		graph := i.Graph()
+
+		if r.URL.Query().Get("format") != "dot" {
+			writeEncoded(w, r, http.StatusOK, graph)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/vnd.graphviz; charset=utf-8")
+		w.Write(graph.DOT())
+	}))
+}
@@ -0,0 +1,76 @@
+package healthz
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGraph(t *testing.T) {
+	inspector := New(
+		HealthCheckTarget{Service: &mockService{scope: "api", dest: "orders"}, Groups: GroupReady, DependsOn: []string{"db/pg", "cache/redis"}},
+		HealthCheckTarget{Service: &mockService{scope: "db", dest: "pg", healthErr: errors.New("conn refused")}, Groups: GroupReady},
+		HealthCheckTarget{Service: &mockService{scope: "cache", dest: "redis"}, Groups: GroupReady, DependsOn: []string{"cache/sentinel"}},
+	)
+	inspector.check(context.Background())
+
+	graph := inspector.Graph()
+	assert.Len(t, graph.Nodes, 4)
+	assert.Equal(t, []GraphEdge{
+		{From: "api/orders", To: "db/pg"},
+		{From: "api/orders", To: "cache/redis"},
+		{From: "cache/redis", To: "cache/sentinel"},
+	}, graph.Edges)
+
+	nodes := make(map[string]GraphNode)
+	for _, node := range graph.Nodes {
+		nodes[node.ID] = node
+	}
+
+	assert.True(t, nodes["api/orders"].Healthy)
+	assert.False(t, nodes["db/pg"].Healthy)
+	assert.True(t, nodes["db/pg"].RootCause)
+	assert.Equal(t, "conn refused", nodes["db/pg"].Error)
+	assert.Equal(t, errUnknownDependency.Error(), nodes["cache/sentinel"].Error)
+
+	t.Run("Failure through the chain", func(t *testing.T) {
+		inspector.targets[0].Service.(*mockService).healthErr = errors.New("db is down")
+		inspector.check(context.Background())
+
+		for _, node := range inspector.Graph().Nodes {
+			if node.ID == "api/orders" {
+				assert.False(t, node.Healthy)
+				assert.False(t, node.RootCause, "dependency is unhealthy")
+			}
+		}
+	})
+
+	t.Run("Handler", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		inspector.GraphHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, PathGraph, nil))
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+		var body Graph
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Len(t, body.Nodes, 4)
+
+		w = httptest.NewRecorder()
+		inspector.GraphHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, PathGraph+"?format=dot", nil))
+		assert.Contains(t, w.Body.String(), `"db/pg" [color="red", style="bold"];`)
+		assert.Contains(t, w.Body.String(), `"api/orders" -> "db/pg";`)
+	})
+
+	t.Run("Child", func(t *testing.T) {
+		child, err := inspector.Child("cache")
+		assert.NoError(t, err)
+
+		graph := child.Graph()
+		assert.Len(t, graph.Nodes, 2)
+		assert.Equal(t, []GraphEdge{{From: "cache/redis", To: "cache/sentinel"}}, graph.Edges)
+	})
+}
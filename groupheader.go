@@ -0,0 +1,47 @@
+package healthz
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// HeaderGroup - request header selecting the groups evaluated by HealthHandler, see WithGroupHeader.
+const HeaderGroup = "X-Healthz-Group"
+
+var errMissTrust = errors.New("group header needs a trust check")
+
+// WithGroupHeader - HealthHandler evaluates the groups given by HeaderGroup (names separated by comma,
+// e.g. "ready,live") instead of its own group, so one endpoint serves several probe types behind smart
+// load balancers. The header is honored for requests passing trusted (e.g. by the remote address
+// or a header set by the proxy) and ignored for others, unknown group names get 400 Bad Request.
+func WithGroupHeader(trusted func(r *http.Request) bool) Option {
+	return func(i *Inspector) error {
+		if trusted == nil {
+			return errMissTrust
+		}
+
+		i.groupHeader = trusted
+
+		return nil
+	}
+}
+
+// headerGroup - groups of the trusted request header or the group if there is none.
+func (i *Inspector) headerGroup(r *http.Request, group ProbeGroup) (ProbeGroup, error) {
+	if i.groupHeader == nil {
+		return group, nil
+	}
+
+	value := r.Header.Get(HeaderGroup)
+	if value == "" || !i.groupHeader(r) {
+		return group, nil
+	}
+
+	names := strings.Split(value, ",")
+	for n := range names {
+		names[n] = strings.TrimSpace(names[n])
+	}
+
+	return ParseGroups(names)
+}
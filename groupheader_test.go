@@ -0,0 +1,68 @@
+package healthz
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithGroupHeader(t *testing.T) {
+	assert.ErrorIs(t, WithGroupHeader(nil)(New()), errMissTrust)
+
+	targets := []HealthCheckTarget{
+		{Service: &mockService{scope: "app", dest: "loop"}, Groups: GroupLive},
+		{Service: &mockService{scope: "db", dest: "pg", healthErr: errors.New("down")}, Groups: GroupReady},
+	}
+
+	other := New(targets...)
+	other.check(context.Background())
+
+	plain := other.HealthHandler(GroupLive, true, nil)
+
+	inspector := New(targets...)
+	inspector.check(context.Background())
+
+	assert.NoError(t, WithGroupHeader(func(r *http.Request) bool {
+		return r.Header.Get("X-Proxy") == "lb"
+	})(inspector))
+
+	handler := inspector.HealthHandler(GroupLive, true, nil)
+
+	tests := []struct {
+		name     string
+		handler  http.HandlerFunc
+		groups   string
+		trusted  bool
+		wantCode int
+	}{
+		{name: "test.1 no header", handler: handler, trusted: true, wantCode: http.StatusOK},
+		{name: "test.2 trusted", handler: handler, groups: "ready", trusted: true, wantCode: http.StatusServiceUnavailable},
+		{name: "test.3 untrusted", handler: handler, groups: "ready", wantCode: http.StatusOK},
+		{name: "test.4 several groups", handler: handler, groups: "live, ready", trusted: true, wantCode: http.StatusServiceUnavailable},
+		{name: "test.5 same group", handler: handler, groups: "live", trusted: true, wantCode: http.StatusOK},
+		{name: "test.6 unknown group", handler: handler, groups: "bogus", trusted: true, wantCode: http.StatusBadRequest},
+		{name: "test.7 no option", handler: plain, groups: "ready", trusted: true, wantCode: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+			if tt.groups != "" {
+				req.Header.Set(HeaderGroup, tt.groups)
+			}
+
+			if tt.trusted {
+				req.Header.Set("X-Proxy", "lb")
+			}
+
+			rec := httptest.NewRecorder()
+			tt.handler(rec, req)
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package healthz
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+var (
+	errWrongGroupName = errors.New("incorrect group name")
+	errTooManyGroups  = errors.New("too many probe groups")
+)
+
+var (
+	groupsMu   sync.RWMutex
+	groupNames = map[ProbeGroup]string{
+		GroupCommon:  "common",
+		GroupStartup: "startup",
+		GroupLive:    "live",
+		GroupReady:   "ready",
+	}
+	nextGroup = GroupReady << 1 // bit of the next registered group, zero if all bits are taken
+)
+
+// RegisterGroup - named probe group beyond the built-in ones, e.g. "migrations" or "payments-critical",
+// targets join it by the returned bit like any other group. Registering a known name returns its group,
+// so packages could share groups by name. Names mustn't contain spaces, "/", "," and "|".
+func RegisterGroup(name string) (ProbeGroup, error) {
+	if name == "" || strings.ContainsAny(name, " \t/,|") {
+		return 0, fmt.Errorf("%w: %q", errWrongGroupName, name)
+	}
+
+	groupsMu.Lock()
+	defer groupsMu.Unlock()
+
+	for g, n := range groupNames {
+		if n == name {
+			return g, nil
+		}
+	}
+
+	if nextGroup == 0 {
+		return 0, fmt.Errorf("%w: %q", errTooManyGroups, name)
+	}
+
+	g := nextGroup
+	nextGroup <<= 1
+	groupNames[g] = name
+
+	return g, nil
+}
+
+func groupByName(name string) (ProbeGroup, bool) {
+	groupsMu.RLock()
+	defer groupsMu.RUnlock()
+
+	for g, n := range groupNames {
+		if n == name {
+			return g, true
+		}
+	}
+
+	return 0, false
+}
+
+func groupName(g ProbeGroup) string {
+	groupsMu.RLock()
+	defer groupsMu.RUnlock()
+
+	return groupNames[g]
+}
+
+// knownGroups - mask of the built-in and registered groups.
+func knownGroups() ProbeGroup {
+	groupsMu.RLock()
+	defer groupsMu.RUnlock()
+
+	return nextGroup - 1 // all bits once nextGroup overflowed
+}
+
+// groupBits - single groups of the mask.
+func groupBits(pg ProbeGroup) []ProbeGroup {
+	var list []ProbeGroup
+
+	pg &= knownGroups()
+
+	for g := GroupCommon; g != 0 && g <= pg; g <<= 1 {
+		if pg&g != 0 {
+			list = append(list, g)
+		}
+	}
+
+	return list
+}
+
+// CheckGroupByName - CheckGroup of the group given by name, built-in or registered by RegisterGroup.
+func (i *Inspector) CheckGroupByName(name string, needAllHealthy bool) error {
+	group, err := ParseGroups([]string{name})
+	if err != nil {
+		return err
+	}
+
+	return i.CheckGroup(group, needAllHealthy)
+}
+
+// HealthHandlerByName - HealthHandler of the group given by name, built-in or registered by RegisterGroup.
+func (i *Inspector) HealthHandlerByName(name string, needAllHealthy bool, toResponse func(error) []byte) (http.HandlerFunc, error) {
+	group, err := ParseGroups([]string{name})
+	if err != nil {
+		return nil, err
+	}
+
+	return i.HealthHandler(group, needAllHealthy, toResponse), nil
+}
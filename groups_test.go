@@ -0,0 +1,81 @@
+package healthz
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterGroup(t *testing.T) {
+	tests := []struct {
+		name    string
+		group   string
+		want    ProbeGroup
+		wantErr bool
+	}{
+		{name: "test.1 built-in", group: "ready", want: GroupReady},
+		{name: "test.2 empty", group: "", wantErr: true},
+		{name: "test.3 separator", group: "a,b", wantErr: true},
+		{name: "test.4 slash", group: "a/b", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RegisterGroup(tt.group)
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, errWrongGroupName)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	migrations, err := RegisterGroup("migrations")
+	assert.NoError(t, err)
+	assert.Zero(t, migrations&AllGroups, "custom group gets own bit")
+
+	again, err := RegisterGroup("migrations")
+	assert.NoError(t, err)
+	assert.Equal(t, migrations, again)
+
+	assert.Equal(t, "ready|migrations", (GroupReady | migrations).String())
+
+	parsed, err := ParseGroups([]string{"live", "migrations"})
+	assert.NoError(t, err)
+	assert.Equal(t, GroupLive|migrations, parsed)
+}
+
+func TestCheckGroupByName(t *testing.T) {
+	warmCache, err := RegisterGroup("warm-cache")
+	assert.NoError(t, err)
+
+	inspector := New(
+		HealthCheckTarget{Service: &mockService{scope: "cache", dest: "redis"}, Groups: GroupReady | warmCache},
+		HealthCheckTarget{Service: &mockService{scope: "cache", dest: "local", healthErr: errors.New("cold")}, Groups: warmCache},
+	)
+	inspector.check(context.Background())
+
+	assert.NoError(t, inspector.CheckGroupByName("ready", true))
+	assert.Error(t, inspector.CheckGroupByName("warm-cache", true))
+	assert.NoError(t, inspector.CheckGroupByName("warm-cache", false))
+	assert.ErrorIs(t, inspector.CheckGroupByName("not-registered", true), errUnknownGroupName)
+
+	_, err = inspector.HealthHandlerByName("not-registered", true, nil)
+	assert.ErrorIs(t, err, errUnknownGroupName)
+
+	handler, err := inspector.HealthHandlerByName("warm-cache", true, nil)
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest(http.MethodGet, "/healthz/warm-cache", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
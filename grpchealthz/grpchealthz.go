@@ -0,0 +1,149 @@
+// Package grpchealthz - the healthz inspector as the standard gRPC health service
+// (grpc.health.v1.Health), for deployments probing gRPC health instead of HTTP.
+package grpchealthz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/art-frela/healthz"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+const defWatchInterval = time.Second
+
+var errMissGroup = errors.New("service must have probe group")
+
+// Service - probe evaluated for a gRPC service name.
+type Service struct {
+	Group          healthz.ProbeGroup
+	NeedAllHealthy bool
+	Scope          string // restricts the probe to targets of the scope if set
+}
+
+// DefaultServices - the whole server ("") and "ready" need all ready targets healthy,
+// "startup" and "live" pass if any target of the group is healthy (like healthz.Inspector.Handler).
+func DefaultServices() map[string]Service {
+	return map[string]Service{
+		"":        {Group: healthz.GroupReady, NeedAllHealthy: true},
+		"startup": {Group: healthz.GroupStartup},
+		"live":    {Group: healthz.GroupLive},
+		"ready":   {Group: healthz.GroupReady, NeedAllHealthy: true},
+	}
+}
+
+// Server - grpc_health_v1.HealthServer reporting health of the inspector:
+//
+//	healthpb.RegisterHealthServer(grpcServer, srv)
+type Server struct {
+	healthpb.UnimplementedHealthServer
+
+	WatchInterval time.Duration // how often Watch re-evaluates the probe, default 1s
+
+	probes map[string]probe
+}
+
+type probe struct {
+	inspector *healthz.Inspector
+	group     healthz.ProbeGroup
+	needAll   bool
+}
+
+// NewServer - health service of the inspector, services map gRPC service names to probes,
+// DefaultServices if nil.
+func NewServer(inspector *healthz.Inspector, services map[string]Service) (*Server, error) {
+	if services == nil {
+		services = DefaultServices()
+	}
+
+	srv := &Server{
+		WatchInterval: defWatchInterval,
+		probes:        make(map[string]probe, len(services)),
+	}
+
+	for name, svc := range services {
+		if svc.Group == 0 {
+			return nil, fmt.Errorf("service %q: %w", name, errMissGroup)
+		}
+
+		p := probe{inspector: inspector, group: svc.Group, needAll: svc.NeedAllHealthy}
+
+		if svc.Scope != "" {
+			child, err := inspector.Child(svc.Scope)
+			if err != nil {
+				return nil, fmt.Errorf("service %q: %w", name, err)
+			}
+
+			p.inspector = child
+		}
+
+		srv.probes[name] = p
+	}
+
+	return srv, nil
+}
+
+// Check - status of the service, NotFound for services not mapped.
+func (s *Server) Check(_ context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	p, ok := s.probes[req.GetService()]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "unknown service %q", req.GetService())
+	}
+
+	return &healthpb.HealthCheckResponse{Status: p.status()}, nil
+}
+
+// Watch - sends the status of the service and then its every change until the client leaves,
+// SERVICE_UNKNOWN for services not mapped.
+func (s *Server) Watch(req *healthpb.HealthCheckRequest, stream healthpb.Health_WatchServer) error {
+	ctx := stream.Context()
+
+	p, ok := s.probes[req.GetService()]
+	if !ok {
+		if err := stream.Send(&healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVICE_UNKNOWN}); err != nil {
+			return err
+		}
+
+		<-ctx.Done()
+
+		return status.FromContextError(ctx.Err()).Err()
+	}
+
+	interval := s.WatchInterval
+	if interval <= 0 {
+		interval = defWatchInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	last := healthpb.HealthCheckResponse_UNKNOWN
+
+	for {
+		if cur := p.status(); cur != last {
+			if err := stream.Send(&healthpb.HealthCheckResponse{Status: cur}); err != nil {
+				return err
+			}
+
+			last = cur
+		}
+
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p probe) status() healthpb.HealthCheckResponse_ServingStatus {
+	if err := p.inspector.CheckGroup(p.group, p.needAll); err != nil {
+		return healthpb.HealthCheckResponse_NOT_SERVING
+	}
+
+	return healthpb.HealthCheckResponse_SERVING
+}
@@ -0,0 +1,130 @@
+package grpchealthz
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/art-frela/healthz"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type service struct {
+	scope, dest string
+	down        atomic.Bool
+}
+
+func (s *service) Health(context.Context) error {
+	if s.down.Load() {
+		return errors.New("down")
+	}
+
+	return nil
+}
+
+func (s *service) Scope() string { return s.scope }
+func (s *service) Dest() string  { return s.dest }
+
+func TestServer(t *testing.T) {
+	db := &service{scope: "database", dest: "pg"}
+	cache := &service{scope: "cache", dest: "redis"}
+	cache.down.Store(true)
+
+	inspector := healthz.New(
+		healthz.HealthCheckTarget{Service: db, Groups: healthz.GroupLive | healthz.GroupReady},
+		healthz.HealthCheckTarget{Service: cache, Groups: healthz.GroupReady},
+	)
+	assert.NoError(t, healthz.WithCheckPeriod(10*time.Millisecond)(inspector))
+
+	services := DefaultServices()
+	services["db"] = Service{Group: healthz.GroupReady, NeedAllHealthy: true, Scope: "database"}
+
+	srv, err := NewServer(inspector, services)
+	assert.NoError(t, err)
+	srv.WatchInterval = 5 * time.Millisecond
+
+	client := startServer(t, srv)
+	ctx := context.Background()
+
+	assert.NoError(t, inspector.Start(ctx))
+	defer inspector.Stop(ctx)
+
+	assert.Eventually(t, func() bool { return inspector.CheckGroup(healthz.GroupLive, false) == nil }, time.Second, time.Millisecond)
+
+	tests := []struct {
+		name    string
+		service string
+		want    healthpb.HealthCheckResponse_ServingStatus
+	}{
+		{name: "test.1 server", service: "", want: healthpb.HealthCheckResponse_NOT_SERVING},
+		{name: "test.2 ready", service: "ready", want: healthpb.HealthCheckResponse_NOT_SERVING},
+		{name: "test.3 live", service: "live", want: healthpb.HealthCheckResponse_SERVING},
+		{name: "test.4 scope", service: "db", want: healthpb.HealthCheckResponse_SERVING},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: tt.service})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, resp.GetStatus())
+		})
+	}
+
+	_, err = client.Check(ctx, &healthpb.HealthCheckRequest{Service: "unknown"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	watchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := client.Watch(watchCtx, &healthpb.HealthCheckRequest{Service: "ready"})
+	assert.NoError(t, err)
+
+	resp, err := stream.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, resp.GetStatus())
+
+	cache.down.Store(false)
+
+	resp, err = stream.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.GetStatus(), "change is sent")
+
+	unknown, err := client.Watch(watchCtx, &healthpb.HealthCheckRequest{Service: "unknown"})
+	assert.NoError(t, err)
+
+	resp, err = unknown.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVICE_UNKNOWN, resp.GetStatus())
+}
+
+func TestNewServer_wrongService(t *testing.T) {
+	_, err := NewServer(healthz.New(), map[string]Service{"ready": {}})
+	assert.ErrorIs(t, err, errMissGroup)
+}
+
+func startServer(t *testing.T, srv *Server) healthpb.HealthClient {
+	ln := bufconn.Listen(1 << 20)
+
+	gs := grpc.NewServer()
+	healthpb.RegisterHealthServer(gs, srv)
+
+	go gs.Serve(ln)
+	t.Cleanup(gs.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return ln.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	assert.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return healthpb.NewHealthClient(conn)
+}
@@ -0,0 +1,101 @@
+package healthz
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// gzipMinSize - responses smaller than this are sent as is, compression doesn't pay off.
+const gzipMinSize = 1024
+
+// Gzip - compresses response of the handler when the client accepts gzip
+// and the body is large enough. Used for status like endpoints with many targets.
+func Gzip(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+
+		if !acceptsGzip(r) {
+			next.ServeHTTP(w, r)
+
+			return
+		}
+
+		bw := &bufferedWriter{header: w.Header(), status: http.StatusOK}
+		next.ServeHTTP(bw, r)
+
+		body := bw.buf.Bytes()
+
+		if len(body) >= gzipMinSize && w.Header().Get("Content-Encoding") == "" {
+			var zipped bytes.Buffer
+
+			zw := gzip.NewWriter(&zipped)
+			if _, err := zw.Write(body); err == nil && zw.Close() == nil {
+				body = zipped.Bytes()
+
+				w.Header().Set("Content-Encoding", "gzip")
+			}
+		}
+
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		w.WriteHeader(bw.status)
+		w.Write(body)
+	})
+}
+
+// acceptsGzip - reports whether Accept-Encoding of the request accepts gzip. Codings are case-insensitive
+// ("x-gzip" is gzip too), "*" with its quality applies if gzip isn't listed.
+func acceptsGzip(r *http.Request) bool {
+	var gzipListed, gzipOK, anyListed, anyOK bool
+
+	for _, part := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		enc, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+
+		switch strings.ToLower(strings.TrimSpace(enc)) {
+		case "gzip", "x-gzip":
+			gzipListed = true
+			gzipOK = gzipOK || !refused(quality(params))
+		case "*":
+			anyListed = true
+			anyOK = anyOK || !refused(quality(params))
+		}
+	}
+
+	if gzipListed {
+		return gzipOK
+	}
+
+	return anyListed && anyOK
+}
+
+// quality - value of the q parameter of the coding, empty if not given.
+func quality(params string) string {
+	for _, param := range strings.Split(params, ";") {
+		if key, value, _ := strings.Cut(param, "="); strings.EqualFold(strings.TrimSpace(key), "q") {
+			return strings.TrimSpace(value)
+		}
+	}
+
+	return ""
+}
+
+// refused - reports whether the quality value q ("0", "0.0", "0.000") refuses the coding or media type,
+// malformed values don't.
+func refused(q string) bool {
+	v, err := strconv.ParseFloat(q, 64)
+
+	return err == nil && v == 0
+}
+
+// bufferedWriter - collects the response to decide on compression after the handler is done.
+type bufferedWriter struct {
+	header http.Header
+	status int
+	buf    bytes.Buffer
+}
+
+func (bw *bufferedWriter) Header() http.Header         { return bw.header }
+func (bw *bufferedWriter) Write(p []byte) (int, error) { return bw.buf.Write(p) }
+func (bw *bufferedWriter) WriteHeader(status int)      { bw.status = status }
@@ -0,0 +1,140 @@
+package healthz
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGzip(t *testing.T) {
+	large := strings.Repeat("healthz ", gzipMinSize)
+
+	handler := func(body string) http.Handler {
+		return Gzip(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(body))
+		}))
+	}
+
+	tests := []struct {
+		name           string
+		body           string
+		acceptEncoding string
+		wantGzip       bool
+	}{
+		{
+			name:           "test.1 large accepted",
+			body:           large,
+			acceptEncoding: "deflate, gzip",
+			wantGzip:       true,
+		},
+		{
+			name:           "test.2 large not accepted",
+			body:           large,
+			acceptEncoding: "deflate",
+			wantGzip:       false,
+		},
+		{
+			name:           "test.3 small accepted",
+			body:           "OK",
+			acceptEncoding: "gzip",
+			wantGzip:       false,
+		},
+		{
+			name:           "test.4 large refused by q=0",
+			body:           large,
+			acceptEncoding: "gzip;q=0",
+			wantGzip:       false,
+		},
+		{
+			name:           "test.5 large refused by q=0.000",
+			body:           large,
+			acceptEncoding: "br, gzip; q=0.000",
+			wantGzip:       false,
+		},
+		{
+			name:           "test.6 large refused by Q=0.0",
+			body:           large,
+			acceptEncoding: "gzip;Q=0.0",
+			wantGzip:       false,
+		},
+		{
+			name:           "test.7 large accepted by q=0.5",
+			body:           large,
+			acceptEncoding: "gzip;q=0.5",
+			wantGzip:       true,
+		},
+		{
+			name:           "test.8 large accepted in upper case",
+			body:           large,
+			acceptEncoding: "GZIP",
+			wantGzip:       true,
+		},
+		{
+			name:           "test.9 large accepted as x-gzip",
+			body:           large,
+			acceptEncoding: "x-gzip",
+			wantGzip:       true,
+		},
+		{
+			name:           "test.10 large accepted by wildcard",
+			body:           large,
+			acceptEncoding: "br, *",
+			wantGzip:       true,
+		},
+		{
+			name:           "test.11 large refused by wildcard q=0",
+			body:           large,
+			acceptEncoding: "br, *;q=0",
+			wantGzip:       false,
+		},
+		{
+			name:           "test.12 large refused gzip over wildcard",
+			body:           large,
+			acceptEncoding: "*, Gzip;q=0",
+			wantGzip:       false,
+		},
+		{
+			name:           "test.13 large accepted gzip over refusing wildcard",
+			body:           large,
+			acceptEncoding: "*;q=0, gzip;q=0.1",
+			wantGzip:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/healthz/status", nil)
+			req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+
+			w := httptest.NewRecorder()
+			handler(tt.body).ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+			assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+
+			body := w.Body.Bytes()
+
+			if !tt.wantGzip {
+				assert.Empty(t, w.Header().Get("Content-Encoding"))
+				assert.Equal(t, tt.body, string(body))
+
+				return
+			}
+
+			assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+			assert.Less(t, len(body), len(tt.body))
+
+			zr, err := gzip.NewReader(w.Body)
+			assert.NoError(t, err)
+
+			unzipped, err := io.ReadAll(zr)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.body, string(unzipped))
+		})
+	}
+}
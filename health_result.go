@@ -1,54 +1,109 @@
 package healthz
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"time"
+)
 
 var errNoYetChecked = errors.New("not yet checked")
 
 type healthResult struct {
-	startUp []error
-	live    []error
-	ready   []error
+	checked     bool
+	checkedAt   time.Time // start of the check cycle
+	seq         uint64    // sequence number of the publication, see Snapshot.Seq
+	publishedAt time.Time
+	targets     []TargetResult
 }
 
 func newHealthResult() *healthResult {
-	return &healthResult{
-		startUp: []error{errNoYetChecked},
-		live:    []error{errNoYetChecked},
-		ready:   []error{errNoYetChecked},
+	return &healthResult{}
+}
+
+func (hr *healthResult) clone() *healthResult {
+	next := &healthResult{
+		checked:     hr.checked,
+		checkedAt:   hr.checkedAt,
+		seq:         hr.seq,
+		publishedAt: hr.publishedAt,
+		targets:     make([]TargetResult, len(hr.targets)),
 	}
+	copy(next.targets, hr.targets)
+
+	return next
 }
 
 func (hr *healthResult) add(res serviceCheckResult) {
-	if res.target.Groups&GroupStartup != 0 {
-		hr.startUp = append(hr.startUp, res.err)
+	hr.targets[res.idx] = TargetResult{
+		Scope:    res.target.Service.Scope(),
+		Dest:     res.target.Service.Dest(),
+		Groups:   res.target.Groups,
+		Err:      res.err,
+		Degraded: res.degraded,
+		Details:  res.details,
+
+		CheckedAt: hr.checkedAt,
+		Duration:  res.duration,
+
+		groupErrs: res.groupErrs,
 	}
+}
 
-	if res.target.Groups&GroupLive != 0 {
-		hr.live = append(hr.live, res.err)
+// health - verdict of the group mask: every probe group of the mask is evaluated by itself
+// (by the policy, see EmptyGroupPolicy for groups without targets),
+// the mask is healthy only if all its groups are.
+func (hr *healthResult) health(group ProbeGroup, policy GroupPolicy, empty EmptyGroupPolicy) error {
+	if !hr.checked {
+		return errNoYetChecked
 	}
 
-	if res.target.Groups&GroupReady != 0 {
-		hr.ready = append(hr.ready, res.err)
+	groups := probeBits(group)
+	if len(groups) == 1 {
+		return empty.apply(hr.groupHealth(groups[0], policy))
 	}
+
+	var errs []error
+
+	for _, g := range groups {
+		if err := empty.apply(hr.groupHealth(g, policy)); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", g, err))
+		}
+	}
+
+	return errors.Join(errs...)
 }
 
-func (hr *healthResult) health(group ProbeGroup, needAllHealthy bool) error {
-	var list []error
+// probeBits - single groups the health of the group combines, the common group counts only alone.
+func probeBits(group ProbeGroup) []ProbeGroup {
+	if groups := groupBits(group &^ GroupCommon); len(groups) > 0 {
+		return groups
+	}
+
+	return groupBits(group)
+}
+
+// groupHealth - verdict of the single group.
+func (hr *healthResult) groupHealth(group ProbeGroup, policy GroupPolicy) error {
+	list := hr.errors(group)
 
-	switch {
-	case group&GroupLive != 0:
-		list = hr.live
-	case group&GroupReady != 0:
-		list = hr.ready
-	case group&GroupStartup != 0:
-		list = hr.startUp
+	if len(list) == 0 {
+		return ErrNoTargets
 	}
 
-	if needAllHealthy {
-		return accureError(list)
+	return policy.verdict(list)
+}
+
+// errors - check results of the group targets.
+func (hr *healthResult) errors(group ProbeGroup) []error {
+	var list []error
+
+	for _, tr := range hr.targets {
+		if tr.Groups&group != 0 && tr.manual == "" { // maintenance and disabled targets don't count
+			list = append(list, tr.errFor(group))
+		}
 	}
 
-	return accureNoError(list)
+	return list
 }
 
 func accureError(list []error) error {
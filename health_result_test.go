@@ -0,0 +1,97 @@
+package healthz
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckGroup_CombinedMask(t *testing.T) {
+	errLive := errors.New("live fail")
+	errReady := errors.New("ready fail")
+
+	tests := []struct {
+		name    string
+		targets []HealthCheckTarget
+		needAll bool
+		wantErr []error
+	}{
+		{
+			name: "test.1 both groups healthy",
+			targets: []HealthCheckTarget{
+				{Service: &mockService{dest: "l"}, Groups: GroupLive},
+				{Service: &mockService{dest: "r"}, Groups: GroupReady},
+			},
+			needAll: true,
+		},
+		{
+			name: "test.2 ready unhealthy fails the mask",
+			targets: []HealthCheckTarget{
+				{Service: &mockService{dest: "l"}, Groups: GroupLive},
+				{Service: &mockService{dest: "r", healthErr: errReady}, Groups: GroupReady},
+			},
+			needAll: true,
+			wantErr: []error{errReady},
+		},
+		{
+			name: "test.3 both unhealthy, errors of both groups",
+			targets: []HealthCheckTarget{
+				{Service: &mockService{dest: "l", healthErr: errLive}, Groups: GroupLive},
+				{Service: &mockService{dest: "r", healthErr: errReady}, Groups: GroupReady},
+			},
+			needAll: true,
+			wantErr: []error{errLive, errReady},
+		},
+		{
+			name: "test.4 any healthy per group",
+			targets: []HealthCheckTarget{
+				{Service: &mockService{dest: "l1", healthErr: errLive}, Groups: GroupLive},
+				{Service: &mockService{dest: "l2"}, Groups: GroupLive},
+				{Service: &mockService{dest: "r"}, Groups: GroupReady},
+			},
+			needAll: false,
+		},
+		{
+			name: "test.5 any healthy isn't shared across groups",
+			targets: []HealthCheckTarget{
+				{Service: &mockService{dest: "l"}, Groups: GroupLive},
+				{Service: &mockService{dest: "r", healthErr: errReady}, Groups: GroupReady},
+			},
+			needAll: false,
+			wantErr: []error{errReady},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inspector := New(tt.targets...)
+			inspector.check(context.Background())
+
+			err := inspector.CheckGroup(GroupLive|GroupReady, tt.needAll)
+			if len(tt.wantErr) == 0 {
+				assert.NoError(t, err)
+
+				return
+			}
+
+			for _, want := range tt.wantErr {
+				assert.ErrorIs(t, err, want)
+			}
+		})
+	}
+}
+
+func TestCheckGroup_CombinedMaskEmptyGroup(t *testing.T) {
+	inspector := New(HealthCheckTarget{Service: &mockService{}, Groups: GroupLive})
+	inspector.check(context.Background())
+
+	assert.NoError(t, inspector.CheckGroup(GroupLive|GroupReady, true), "empty ready group is healthy by default")
+
+	assert.NoError(t, WithEmptyGroupPolicy(EmptyGroupUnhealthy)(inspector))
+
+	err := inspector.CheckGroup(GroupLive|GroupReady, true)
+	assert.ErrorIs(t, err, ErrNoTargets)
+	assert.ErrorContains(t, err, "ready: ")
+}
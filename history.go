@@ -0,0 +1,525 @@
+package healthz
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// HistoryDest - dest of the internal target reporting health of the history store.
+const HistoryDest = "history"
+
+var (
+	errWrongHistorySize = errors.New("incorrect history size")
+	errMissHistory      = errors.New("history is not configured")
+	errWrongTimeRange   = errors.New("incorrect time range")
+	errHistoryRecord    = errors.New("history record is too long, the file is corrupted")
+)
+
+// maxHistoryRecord - limit of the length prefix of the codec records, see NewCodecFileHistory.
+const maxHistoryRecord = 16 << 20
+
+// HistoryEntry - health of one target after a check cycle.
+type HistoryEntry struct {
+	Time    time.Time `json:"time"`
+	Scope   string    `json:"scope"`
+	Dest    string    `json:"dest"`
+	Healthy bool      `json:"healthy"`
+	Error   string    `json:"error,omitempty"`
+}
+
+// HistoryStore - storage of the check results.
+type HistoryStore interface {
+	Append(entries ...HistoryEntry) error
+	Query(from, to time.Time) ([]HistoryEntry, error) // entries within [from, to), oldest first
+}
+
+// WithHistory - keeps results of every check cycle in the store.
+func WithHistory(store HistoryStore) Option {
+	return func(i *Inspector) error {
+		i.history = store
+
+		return nil
+	}
+}
+
+// History - stored results within [from, to).
+func (i *Inspector) History(from, to time.Time) ([]HistoryEntry, error) {
+	if i.parent != nil {
+		return i.childHistory(from, to)
+	}
+
+	if i.history == nil {
+		return nil, errMissHistory
+	}
+
+	return i.history.Query(from, to)
+}
+
+// HistoryHandler - serves stored results as JSON (or by a negotiated codec), the range is set by query params
+// "from" and "to" (RFC3339) or "window" (duration till now), default window is 1h.
+func (i *Inspector) HistoryHandler() http.Handler {
+	return Gzip(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		from, to, err := parseTimeRange(r, time.Hour)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+
+			return
+		}
+
+		entries, err := i.History(from, to)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+
+			return
+		}
+
+		writeEncoded(w, r, http.StatusOK, entries)
+	}))
+}
+
+func parseTimeRange(r *http.Request, defWindow time.Duration) (time.Time, time.Time, error) {
+	q := r.URL.Query()
+	to := time.Now()
+
+	if v := q.Get("to"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("%w: to: %w", errWrongTimeRange, err)
+		}
+
+		to = t
+	}
+
+	from := to.Add(-defWindow)
+
+	if v := q.Get("window"); v != "" {
+		window, err := time.ParseDuration(v)
+		if err != nil || window <= 0 {
+			return time.Time{}, time.Time{}, fmt.Errorf("%w: window %q", errWrongTimeRange, v)
+		}
+
+		from = to.Add(-window)
+	}
+
+	if v := q.Get("from"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("%w: from: %w", errWrongTimeRange, err)
+		}
+
+		from = t
+	}
+
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, errWrongTimeRange
+	}
+
+	return from, to, nil
+}
+
+// recordHistory - appends the cycle results to the history, returns the internal target of the store.
+func (i *Inspector) recordHistory(targets []TargetResult, at time.Time) TargetResult {
+	entries := make([]HistoryEntry, 0, len(targets))
+
+	for _, tr := range targets {
+		entry := HistoryEntry{Time: at, Scope: tr.Scope, Dest: tr.Dest, Healthy: tr.Healthy()}
+		if err := i.redact(tr.Err); err != nil {
+			entry.Error = err.Error()
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return TargetResult{
+		Scope:  InternalScope,
+		Dest:   HistoryDest,
+		Groups: GroupCommon,
+		Err:    i.history.Append(entries...),
+	}
+}
+
+func inRange(t, from, to time.Time) bool {
+	return !t.Before(from) && t.Before(to)
+}
+
+// MemoryHistory - in-memory ring buffer of the last entries.
+type MemoryHistory struct {
+	mu      sync.RWMutex
+	entries []HistoryEntry
+	next    int
+	full    bool
+}
+
+// NewMemoryHistory - ring buffer keeping the last size entries.
+func NewMemoryHistory(size int) (*MemoryHistory, error) {
+	if size <= 0 {
+		return nil, errWrongHistorySize
+	}
+
+	return &MemoryHistory{entries: make([]HistoryEntry, size)}, nil
+}
+
+func (mh *MemoryHistory) Append(entries ...HistoryEntry) error {
+	mh.mu.Lock()
+	defer mh.mu.Unlock()
+
+	for _, e := range entries {
+		mh.entries[mh.next] = e
+		mh.next = (mh.next + 1) % len(mh.entries)
+
+		if mh.next == 0 {
+			mh.full = true
+		}
+	}
+
+	return nil
+}
+
+func (mh *MemoryHistory) Query(from, to time.Time) ([]HistoryEntry, error) {
+	mh.mu.RLock()
+	defer mh.mu.RUnlock()
+
+	var list []HistoryEntry
+
+	for _, e := range mh.orderedLocked() {
+		if inRange(e.Time, from, to) {
+			list = append(list, e)
+		}
+	}
+
+	return list, nil
+}
+
+// Prune - see HistoryPruner.
+func (mh *MemoryHistory) Prune(before time.Time, maxPerTarget int) (int, error) {
+	mh.mu.Lock()
+	defer mh.mu.Unlock()
+
+	ordered := mh.orderedLocked()
+	kept := retainEntries(ordered, before, maxPerTarget)
+
+	entries := make([]HistoryEntry, len(mh.entries))
+	copy(entries, kept)
+
+	mh.entries = entries
+	mh.next = len(kept) % len(entries)
+	mh.full = len(kept) == len(entries)
+
+	return len(ordered) - len(kept), nil
+}
+
+// Len - see HistoryPruner.
+func (mh *MemoryHistory) Len() int {
+	mh.mu.RLock()
+	defer mh.mu.RUnlock()
+
+	if mh.full {
+		return len(mh.entries)
+	}
+
+	return mh.next
+}
+
+// orderedLocked - stored entries, oldest first, mu must be held.
+func (mh *MemoryHistory) orderedLocked() []HistoryEntry {
+	if !mh.full {
+		return mh.entries[:mh.next]
+	}
+
+	return append(append([]HistoryEntry{}, mh.entries[mh.next:]...), mh.entries[:mh.next]...)
+}
+
+// FileHistory - history appended to a file as JSON lines (or records of a codec), survives restarts.
+type FileHistory struct {
+	mu    sync.Mutex
+	path  string
+	file  *os.File
+	codec Codec // JSON lines if nil, see NewCodecFileHistory
+	count int   // entries in the file
+}
+
+// NewFileHistory - opens (or creates) the history file of JSON lines.
+func NewFileHistory(path string) (*FileHistory, error) {
+	return openFileHistory(path, nil)
+}
+
+// NewCodecFileHistory - opens (or creates) the history file of entries encoded by the codec,
+// e.g. codecs.MsgPack for a compact binary file. Every record is prefixed by its length
+// (4 bytes, big-endian), so the codec output could contain any bytes.
+func NewCodecFileHistory(path string, codec Codec) (*FileHistory, error) {
+	if codec == nil || codec.ContentType() == "" {
+		return nil, errWrongCodec
+	}
+
+	return openFileHistory(path, codec)
+}
+
+func openFileHistory(path string, codec Codec) (*FileHistory, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open history file: %w", err)
+	}
+
+	fh := &FileHistory{path: path, file: file, codec: codec}
+
+	entries, size, err := fh.readLocked()
+	if err == nil {
+		err = fh.truncateTorn(size)
+	}
+
+	if err != nil {
+		file.Close()
+
+		return nil, err
+	}
+
+	fh.count = len(entries)
+
+	return fh, nil
+}
+
+// truncateTorn - cuts the torn trailing record (e.g. of a crash in the middle of Append) off the file
+// of complete records of size bytes, so new entries aren't appended to it.
+func (fh *FileHistory) truncateTorn(size int64) error {
+	info, err := fh.file.Stat()
+	if err != nil {
+		return fmt.Errorf("stat history file: %w", err)
+	}
+
+	if info.Size() == size {
+		return nil
+	}
+
+	if err := fh.file.Truncate(size); err != nil {
+		return fmt.Errorf("truncate history file: %w", err)
+	}
+
+	return nil
+}
+
+func (fh *FileHistory) Append(entries ...HistoryEntry) error {
+	fh.mu.Lock()
+	defer fh.mu.Unlock()
+
+	w := bufio.NewWriter(fh.file)
+
+	if err := fh.encode(w, entries); err != nil {
+		return err
+	}
+
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("write history file: %w", err)
+	}
+
+	fh.count += len(entries)
+
+	return nil
+}
+
+func (fh *FileHistory) Query(from, to time.Time) ([]HistoryEntry, error) {
+	fh.mu.Lock()
+	defer fh.mu.Unlock()
+
+	entries, _, err := fh.readLocked()
+	if err != nil {
+		return nil, err
+	}
+
+	var list []HistoryEntry
+
+	for _, e := range entries {
+		if inRange(e.Time, from, to) {
+			list = append(list, e)
+		}
+	}
+
+	return list, nil
+}
+
+// Prune - see HistoryPruner, the file is rewritten.
+func (fh *FileHistory) Prune(before time.Time, maxPerTarget int) (int, error) {
+	fh.mu.Lock()
+	defer fh.mu.Unlock()
+
+	entries, _, err := fh.readLocked()
+	if err != nil {
+		return 0, err
+	}
+
+	kept := retainEntries(entries, before, maxPerTarget)
+	if len(kept) == len(entries) {
+		return 0, nil
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(fh.path), filepath.Base(fh.path)+".*")
+	if err != nil {
+		return 0, fmt.Errorf("create history file: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // no-op after rename
+
+	w := bufio.NewWriter(tmp)
+
+	if err := fh.encode(w, kept); err != nil {
+		tmp.Close()
+
+		return 0, err
+	}
+
+	if err := errors.Join(w.Flush(), tmp.Close()); err != nil {
+		return 0, fmt.Errorf("write history file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), fh.path); err != nil {
+		return 0, fmt.Errorf("replace history file: %w", err)
+	}
+
+	file, err := os.OpenFile(fh.path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return 0, fmt.Errorf("open history file: %w", err)
+	}
+
+	fh.file.Close()
+	fh.file, fh.count = file, len(kept)
+
+	return len(entries) - len(kept), nil
+}
+
+// Len - see HistoryPruner.
+func (fh *FileHistory) Len() int {
+	fh.mu.Lock()
+	defer fh.mu.Unlock()
+
+	return fh.count
+}
+
+// encode - writes the entries as JSON lines or length-prefixed records of the codec.
+func (fh *FileHistory) encode(w io.Writer, entries []HistoryEntry) error {
+	if fh.codec == nil {
+		enc := json.NewEncoder(w)
+
+		for _, e := range entries {
+			if err := enc.Encode(e); err != nil {
+				return fmt.Errorf("encode history entry: %w", err)
+			}
+		}
+
+		return nil
+	}
+
+	for _, e := range entries {
+		body, err := fh.codec.Marshal(e)
+		if err != nil {
+			return fmt.Errorf("encode history entry: %w", err)
+		}
+
+		if _, err := w.Write(binary.BigEndian.AppendUint32(nil, uint32(len(body)))); err != nil {
+			return fmt.Errorf("write history file: %w", err)
+		}
+
+		if _, err := w.Write(body); err != nil {
+			return fmt.Errorf("write history file: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// readLocked - all entries of the file, oldest first, mu must be held. A torn trailing record (the line
+// without the newline, the record shorter than its prefix) is skipped, size - bytes of the complete records.
+func (fh *FileHistory) readLocked() ([]HistoryEntry, int64, error) {
+	file, err := os.Open(fh.path)
+	if err != nil {
+		return nil, 0, fmt.Errorf("open history file: %w", err)
+	}
+	defer file.Close()
+
+	r := bufio.NewReader(file)
+
+	if fh.codec != nil {
+		return fh.readRecords(r)
+	}
+
+	var (
+		list []HistoryEntry
+		size int64
+	)
+
+	for {
+		line, err := r.ReadBytes('\n') // no line length limit, unlike bufio.Scanner
+		if errors.Is(err, io.EOF) {
+			return list, size, nil
+		}
+
+		if err != nil {
+			return nil, 0, fmt.Errorf("read history file: %w", err)
+		}
+
+		var e HistoryEntry
+
+		if err := json.Unmarshal(line, &e); err != nil {
+			return nil, 0, fmt.Errorf("decode history entry: %w", err)
+		}
+
+		list = append(list, e)
+		size += int64(len(line))
+	}
+}
+
+// readRecords - entries of the length-prefixed records of the codec, see readLocked.
+func (fh *FileHistory) readRecords(r io.Reader) ([]HistoryEntry, int64, error) {
+	var (
+		list   []HistoryEntry
+		size   int64
+		header [4]byte
+	)
+
+	for {
+		if _, err := io.ReadFull(r, header[:]); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return list, size, nil
+			}
+
+			return nil, 0, fmt.Errorf("read history file: %w", err)
+		}
+
+		length := binary.BigEndian.Uint32(header[:])
+		if length > maxHistoryRecord {
+			return nil, 0, fmt.Errorf("%w: %d bytes", errHistoryRecord, length)
+		}
+
+		body := make([]byte, length)
+		if _, err := io.ReadFull(r, body); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return list, size, nil
+			}
+
+			return nil, 0, fmt.Errorf("read history file: %w", err)
+		}
+
+		var e HistoryEntry
+
+		if err := fh.codec.Unmarshal(body, &e); err != nil {
+			return nil, 0, fmt.Errorf("decode history entry: %w", err)
+		}
+
+		list = append(list, e)
+		size += int64(len(header) + len(body))
+	}
+}
+
+// Close - closes the history file.
+func (fh *FileHistory) Close() error {
+	fh.mu.Lock()
+	defer fh.mu.Unlock()
+
+	return fh.file.Close()
+}
@@ -0,0 +1,202 @@
+package healthz
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testHistoryStore(t *testing.T, store HistoryStore) {
+	t.Helper()
+
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for n := range 5 {
+		err := store.Append(HistoryEntry{Time: base.Add(time.Duration(n) * time.Minute), Scope: "db", Dest: "pg", Healthy: n%2 == 0})
+		assert.NoError(t, err)
+	}
+
+	entries, err := store.Query(base.Add(time.Minute), base.Add(3*time.Minute))
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, base.Add(time.Minute), entries[0].Time.UTC())
+	assert.False(t, entries[0].Healthy)
+	assert.True(t, entries[1].Healthy)
+}
+
+func TestMemoryHistory(t *testing.T) {
+	_, err := NewMemoryHistory(0)
+	assert.Error(t, err)
+
+	store, err := NewMemoryHistory(10)
+	assert.NoError(t, err)
+	testHistoryStore(t, store)
+
+	t.Run("Ring keeps the last entries", func(t *testing.T) {
+		store, err := NewMemoryHistory(3)
+		assert.NoError(t, err)
+
+		base := time.Now()
+		for n := range 5 {
+			assert.NoError(t, store.Append(HistoryEntry{Time: base.Add(time.Duration(n) * time.Second), Dest: "pg"}))
+		}
+
+		entries, err := store.Query(base, base.Add(time.Minute))
+		assert.NoError(t, err)
+		assert.Len(t, entries, 3)
+		assert.Equal(t, base.Add(2*time.Second), entries[0].Time)
+		assert.Equal(t, base.Add(4*time.Second), entries[2].Time)
+	})
+}
+
+func TestFileHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+
+	store, err := NewFileHistory(path)
+	assert.NoError(t, err)
+	testHistoryStore(t, store)
+	assert.NoError(t, store.Close())
+
+	// reopened file keeps entries
+	store, err = NewFileHistory(path)
+	assert.NoError(t, err)
+	defer store.Close()
+
+	entries, err := store.Query(time.Time{}, time.Now())
+	assert.NoError(t, err)
+	assert.Len(t, entries, 5)
+}
+
+func TestCodecFileHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.bin")
+
+	_, err := NewCodecFileHistory(path, nil)
+	assert.ErrorIs(t, err, errWrongCodec)
+
+	store, err := NewCodecFileHistory(path, JSONCodec)
+	assert.NoError(t, err)
+	testHistoryStore(t, store)
+	assert.NoError(t, store.Close())
+
+	store, err = NewCodecFileHistory(path, JSONCodec)
+	assert.NoError(t, err)
+	defer store.Close()
+
+	entries, err := store.Query(time.Time{}, time.Now())
+	assert.NoError(t, err)
+	assert.Len(t, entries, 5)
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.NotEqual(t, byte('{'), data[0], "records are length-prefixed")
+}
+
+func TestFileHistory_torn(t *testing.T) {
+	tests := []struct {
+		name string
+		open func(path string) (*FileHistory, error)
+		torn []byte
+	}{
+		{name: "test.1 json lines", open: NewFileHistory, torn: []byte(`{"time":"2024-01-0`)},
+		{name: "test.2 codec prefix", open: func(path string) (*FileHistory, error) {
+			return NewCodecFileHistory(path, JSONCodec)
+		}, torn: []byte{0, 0}},
+		{name: "test.3 codec body", open: func(path string) (*FileHistory, error) {
+			return NewCodecFileHistory(path, JSONCodec)
+		}, torn: []byte{0, 0, 0, 40, '{'}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "history")
+			at := time.Now()
+
+			store, err := tt.open(path)
+			assert.NoError(t, err)
+			assert.NoError(t, store.Append(HistoryEntry{Time: at, Scope: "db", Dest: "pg", Healthy: true}))
+			assert.NoError(t, store.Close())
+
+			// crash in the middle of Append
+			file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+			assert.NoError(t, err)
+			_, err = file.Write(tt.torn)
+			assert.NoError(t, err)
+			assert.NoError(t, file.Close())
+
+			store, err = tt.open(path)
+			assert.NoError(t, err)
+			defer store.Close()
+
+			assert.Equal(t, 1, store.Len())
+			assert.NoError(t, store.Append(HistoryEntry{Time: at, Scope: "db", Dest: "replica"}))
+
+			entries, err := store.Query(time.Time{}, at.Add(time.Second))
+			assert.NoError(t, err)
+			assert.Len(t, entries, 2, "the torn record is cut off")
+		})
+	}
+}
+
+func TestFileHistory_longLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+
+	store, err := NewFileHistory(path)
+	assert.NoError(t, err)
+	defer store.Close()
+
+	at := time.Now()
+	assert.NoError(t, store.Append(HistoryEntry{Time: at, Scope: "db", Dest: "pg", Error: strings.Repeat("x", 100<<10)}))
+
+	entries, err := store.Query(time.Time{}, at.Add(time.Second))
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1, "lines beyond 64 KiB are read")
+}
+
+func TestInspectorHistory(t *testing.T) {
+	inspector := New(
+		HealthCheckTarget{Service: &mockService{scope: "db", dest: "pg"}, Groups: GroupReady},
+		HealthCheckTarget{Service: &mockService{scope: "kafka", dest: "k-1", healthErr: errors.New("fail")}, Groups: GroupReady},
+	)
+
+	_, err := inspector.History(time.Time{}, time.Now())
+	assert.ErrorIs(t, err, errMissHistory)
+
+	store, err := NewMemoryHistory(100)
+	assert.NoError(t, err)
+	assert.NoError(t, WithHistory(store)(inspector))
+
+	inspector.check(context.Background())
+	inspector.check(context.Background())
+
+	entries, err := inspector.History(time.Now().Add(-time.Minute), time.Now())
+	assert.NoError(t, err)
+	assert.Len(t, entries, 4)
+	assert.Equal(t, "fail", entries[1].Error)
+
+	targets := inspector.Snapshot().Targets
+	assert.Equal(t, HistoryDest, targets[len(targets)-1].Dest)
+
+	t.Run("Handler", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		inspector.HistoryHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz/history?window=5m", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var got []HistoryEntry
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+		assert.Len(t, got, 4)
+	})
+
+	t.Run("Handler bad range", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		inspector.HistoryHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz/history?window=-1h", nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
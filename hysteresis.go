@@ -0,0 +1,156 @@
+package healthz
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+var (
+	errWrongHysteresis = errors.New("incorrect hysteresis durations")
+	errRecovering      = errors.New("recovering")
+)
+
+// hysteresis - debounce of the group health flips.
+type hysteresis struct {
+	down time.Duration // failures must last to flip to unhealthy
+	up   time.Duration // stability must last to flip back to healthy
+}
+
+type hysteresisKey struct {
+	group  ProbeGroup
+	policy GroupPolicy
+}
+
+// groupVerdict - reported health of the group under hysteresis.
+type groupVerdict struct {
+	initialized  bool
+	healthy      bool
+	err          error     // error of the flip to unhealthy
+	pendingSince time.Time // raw health differs from reported since
+}
+
+// WithHysteresis - the group flips to unhealthy only after failures last down
+// and back to healthy only after it is stable for up. Combined groups without own settings
+// (e.g. GroupLive|GroupReady) are debounced per single group.
+func WithHysteresis(group ProbeGroup, down, up time.Duration) Option {
+	return func(i *Inspector) error {
+		if err := group.validate(); err != nil {
+			return err
+		}
+
+		if down < 0 || up < 0 {
+			return errWrongHysteresis
+		}
+
+		if i.hysteresis == nil {
+			i.hysteresis = make(map[ProbeGroup]hysteresis)
+		}
+
+		i.hysteresis[group] = hysteresis{down: down, up: up}
+
+		return nil
+	}
+}
+
+type groupVerdicts struct {
+	mu       sync.Mutex
+	verdicts map[hysteresisKey]*groupVerdict
+}
+
+// debouncedHealth - health of the group under hysteresis: the group with own settings is debounced as is,
+// a combined one (e.g. Live|Ready of /healthz) otherwise per single group by its settings, joined like health.
+func (i *Inspector) debouncedHealth(res *healthResult, group ProbeGroup, policy GroupPolicy) error {
+	raw := res.health(group, policy, i.emptyGroup)
+
+	groups := probeBits(group)
+	if !res.checked || len(groups) < 2 || i.debounced(group) || !slices.ContainsFunc(groups, i.debounced) {
+		return i.applyHysteresis(group, policy, raw, res.checkedAt)
+	}
+
+	var errs []error
+
+	for _, g := range groups {
+		if err := i.applyHysteresis(g, policy, res.health(g, policy, i.emptyGroup), res.checkedAt); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", g, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// debounced - reports whether the group has own hysteresis settings.
+func (i *Inspector) debounced(group ProbeGroup) bool {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	_, ok := i.hysteresis[group]
+
+	return ok
+}
+
+// applyHysteresis - reported health of the group given its raw health observed at checkedAt.
+func (i *Inspector) applyHysteresis(group ProbeGroup, policy GroupPolicy, raw error, checkedAt time.Time) error {
+	i.mu.RLock()
+	h, ok := i.hysteresis[group]
+	i.mu.RUnlock()
+
+	if !ok {
+		return raw
+	}
+
+	i.verdicts.mu.Lock()
+	defer i.verdicts.mu.Unlock()
+
+	if i.verdicts.verdicts == nil {
+		i.verdicts.verdicts = make(map[hysteresisKey]*groupVerdict)
+	}
+
+	key := hysteresisKey{group: group, policy: policy}
+
+	v, ok := i.verdicts.verdicts[key]
+	if !ok {
+		v = &groupVerdict{}
+		i.verdicts.verdicts[key] = v
+	}
+
+	return v.evaluate(h, raw, checkedAt, time.Now())
+}
+
+func (v *groupVerdict) evaluate(h hysteresis, raw error, checkedAt, now time.Time) error {
+	rawHealthy := raw == nil
+
+	if !v.initialized || rawHealthy == v.healthy {
+		v.initialized = true
+		v.healthy = rawHealthy
+		v.err = raw
+		v.pendingSince = time.Time{}
+
+		return raw
+	}
+
+	if v.pendingSince.IsZero() {
+		v.pendingSince = checkedAt
+	}
+
+	threshold := h.up
+	if v.healthy {
+		threshold = h.down
+	}
+
+	if now.Sub(v.pendingSince) >= threshold {
+		v.healthy = rawHealthy
+		v.err = raw
+		v.pendingSince = time.Time{}
+
+		return raw
+	}
+
+	if v.healthy {
+		return nil
+	}
+
+	return fmt.Errorf("%w: healthy for %s of %s: %w", errRecovering, now.Sub(v.pendingSince).Round(time.Millisecond), h.up, v.err)
+}
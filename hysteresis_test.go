@@ -0,0 +1,77 @@
+package healthz
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupVerdict_evaluate(t *testing.T) {
+	h := hysteresis{down: 5 * time.Second, up: 30 * time.Second}
+	errFail := errors.New("fail")
+	base := time.Now()
+	at := func(sec int) time.Time { return base.Add(time.Duration(sec) * time.Second) }
+
+	v := &groupVerdict{}
+
+	assert.NoError(t, v.evaluate(h, nil, at(0), at(0)), "initial state is taken as is")
+	assert.NoError(t, v.evaluate(h, errFail, at(10), at(10)), "failure just started")
+	assert.NoError(t, v.evaluate(h, errFail, at(12), at(14)), "failing 4s of 5s")
+	assert.ErrorIs(t, v.evaluate(h, errFail, at(15), at(15)), errFail, "failing 5s")
+
+	err := v.evaluate(h, nil, at(20), at(20))
+	assert.ErrorIs(t, err, errRecovering)
+	assert.ErrorIs(t, err, errFail)
+
+	assert.Error(t, v.evaluate(h, nil, at(35), at(49)), "stable 29s of 30s")
+	assert.ErrorIs(t, v.evaluate(h, errFail, at(50), at(50)), errFail, "failure again resets recovery")
+	assert.Error(t, v.evaluate(h, nil, at(55), at(60)))
+	assert.NoError(t, v.evaluate(h, nil, at(80), at(85)), "stable 30s")
+}
+
+func TestWithHysteresis(t *testing.T) {
+	assert.Error(t, WithHysteresis(0, time.Second, time.Second)(New()))
+	assert.Error(t, WithHysteresis(GroupReady, -time.Second, time.Second)(New()))
+
+	svc := &mockService{}
+	inspector := New(HealthCheckTarget{Service: svc, Groups: GroupLive | GroupReady})
+	assert.NoError(t, WithHysteresis(GroupReady, time.Hour, time.Hour)(inspector))
+
+	inspector.check(context.Background())
+	assert.NoError(t, inspector.CheckGroup(GroupReady, true))
+
+	svc.healthErr = errors.New("fail")
+	inspector.check(context.Background())
+
+	assert.NoError(t, inspector.CheckGroup(GroupReady, true), "ready is debounced")
+	assert.Error(t, inspector.CheckGroup(GroupLive, true), "live has no hysteresis")
+}
+
+func TestWithHysteresis_combined(t *testing.T) {
+	db := &mockService{scope: "db", dest: "pg"}
+	app := &mockService{scope: "app", dest: "loop"}
+
+	inspector := New(
+		HealthCheckTarget{Service: db, Groups: GroupReady},
+		HealthCheckTarget{Service: app, Groups: GroupLive},
+	)
+	assert.NoError(t, WithHysteresis(GroupReady, time.Hour, time.Hour)(inspector))
+
+	inspector.check(context.Background())
+	assert.NoError(t, inspector.CheckGroup(GroupLive|GroupReady, true))
+
+	db.healthErr = errors.New("fail")
+	inspector.check(context.Background())
+
+	assert.NoError(t, inspector.CheckGroup(GroupLive|GroupReady, true), "ready of the combined group is debounced")
+
+	app.healthErr = errors.New("stuck")
+	inspector.check(context.Background())
+
+	err := inspector.CheckGroup(GroupLive|GroupReady, true)
+	assert.ErrorContains(t, err, "live: ")
+	assert.NotContains(t, err.Error(), "ready", "live has no hysteresis")
+}
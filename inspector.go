@@ -4,13 +4,19 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 	"unsafe"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/singleflight"
 )
 
 const (
@@ -20,33 +26,52 @@ const (
 
 var (
 	errMissTargets      = errors.New("miss targets")
-	errMissGroup        = errors.New("miss group, allow only combinations from 1, 2, 4")
+	errMissGroup        = errors.New("unknown probe group, see RegisterGroup")
 	errEmptyGroup       = errors.New("empty probe group")
 	errWrongCheckPeriod = errors.New("incorrect check period")
+	errWrongTimeout     = errors.New("incorrect timeout")
+	errCycleTimeout     = errors.New("check didn't finish within the cycle timeout")
+	errMissProvider     = errors.New("miss targets provider")
+	errMissService      = errors.New("miss service of the target")
 )
 
-// ProbeGroup - Bit Mask Verification Groups.
-type ProbeGroup uint8
+// ProbeGroup - Bit Mask Verification Groups, built-in ones and registered by RegisterGroup.
+type ProbeGroup uint64
 
 func (pg ProbeGroup) validate() error {
 	if pg == 0 {
 		return errEmptyGroup
 	}
 
-	if (pg & AllGroups) != pg {
-		return errMissGroup
+	if unknown := pg &^ knownGroups(); unknown != 0 {
+		return fmt.Errorf("%w: bits %#x", errMissGroup, uint64(unknown))
 	}
 
 	return nil
 }
 
+// String - e.g. "ready" or "live|ready".
+func (pg ProbeGroup) String() string {
+	var names []string
+
+	for _, g := range groupBits(pg) {
+		names = append(names, groupName(g))
+	}
+
+	if rest := pg &^ knownGroups(); rest != 0 {
+		names = append(names, strconv.FormatUint(uint64(rest), 10))
+	}
+
+	return strings.Join(names, "|")
+}
+
 const (
 	GroupCommon  ProbeGroup = 1 << iota // 1
 	GroupStartup                        // 2
 	GroupLive                           // 4
 	GroupReady                          // 8
 	//
-	AllGroups ProbeGroup = GroupCommon | GroupStartup | GroupLive | GroupReady // 15
+	AllGroups ProbeGroup = GroupCommon | GroupStartup | GroupLive | GroupReady // 15, built-in groups only
 )
 
 // HealthCheckable - Interface of the verified service.
@@ -56,29 +81,161 @@ type HealthCheckable interface {
 	Dest() string                     // A specific resource or (for example: "Redis-Primary", "Postgres-12", "kafka-1.domain.local:8321")
 }
 
+// MultiGroupChecker - checkable with own check per probe group (e.g. cheap TCP dial for live,
+// full query for ready), GroupHealth is called for every group of the target instead of Health.
+type MultiGroupChecker interface {
+	HealthCheckable
+	GroupHealth(ctx context.Context, group ProbeGroup) error
+}
+
+// DetailedChecker - checkable attaching structured details to its result (replication lag,
+// pool in-use/idle counts, cluster color), HealthDetails is called instead of Health.
+type DetailedChecker interface {
+	HealthCheckable
+	HealthDetails(ctx context.Context) (map[string]any, error)
+}
+
+// MetricsReporter - checkable exporting numeric values the check already has (replication lag,
+// queue depth), HealthMetrics is called after every check, see WithTargetMetric.
+type MetricsReporter interface {
+	HealthCheckable
+	HealthMetrics() map[string]float64
+}
+
 // HealthCheckTarget - container for the service and its groups.
 type HealthCheckTarget struct {
 	Service HealthCheckable
 	Groups  ProbeGroup // Bit mask of groups
+	// targets it depends on as "scope/dest" (e.g. "database/pg-1"), see Inspector.Graph
+	DependsOn []string
+	Timeout   time.Duration // of every check call, overrides WithCheckTimeout if set
+	Period    time.Duration // of the target checks, overrides WithCheckPeriod and WithAdaptiveSchedule if set
+	// consecutive failed (passed) checks to report the target unhealthy (healthy again),
+	// override WithThresholds if set
+	FailureThreshold int
+	SuccessThreshold int
+	// values of the WithMetricLabels labels of the target series, missing ones are empty
+	Labels map[string]string
+
+	config   *TargetConfig // set for declarative targets, see Config
+	provided bool          // resolved from WithTargetsFromProvider
+}
+
+// declared - reports whether the target is declared by Config, see ApplyConfig.
+func (t HealthCheckTarget) declared() bool {
+	return t.config != nil
+}
+
+// programmatic - reports whether the target is given by the code (New, WithTargets, AddTarget),
+// neither declared by Config nor resolved from providers, see Configure.
+func (t HealthCheckTarget) programmatic() bool {
+	return t.config == nil && !t.provided
+}
+
+func (t HealthCheckTarget) declaredOrProvided() bool {
+	return !t.programmatic()
 }
 
 type Option func(i *Inspector) error
 
 // Inspector - the main control structure.
 type Inspector struct {
+	mu            sync.RWMutex // guards settings which could be reloaded: targets, periods, hysteresis
 	targets       []HealthCheckTarget
 	stopCh        chan struct{}
 	confirmStopCh chan struct{}
+	reload        chan struct{} // wakes the check loop on reloaded settings, see reloaded
 	metric        *prometheus.GaugeVec
+	metricErrors  prometheus.Counter
+	outcomeMetric *prometheus.CounterVec
+	latencyMetric *prometheus.SummaryVec
+	durationHist  *prometheus.HistogramVec
+	queueWaitHist *prometheus.HistogramVec
 	checkPeriod   time.Duration
+	cycleTimeout  time.Duration
+	checkTimeout  time.Duration
+	scopeSlots    map[string]chan struct{}
+	adaptive      adaptive // see WithAdaptiveSchedule
+	silences      silences // see Silence
+	paces         targetPaces
+	states        targetStates
+	logger        *slog.Logger // see WithLogger
+	logLevels     LogLevels
+	maxChecks     int // simultaneous checks of the cycle, unlimited if zero
+	backoffMax    time.Duration
+	bootstrap     bootstrap // see WithBootstrapTimeouts
 	data          unsafe.Pointer
+	onDemand      *onDemand // see WithOnDemandChecks
+	nowFlight     singleflight.Group
+	refreshQuery  bool
+	groupHeader   func(r *http.Request) bool
+	cycleMu       sync.Mutex // serializes check cycles, see CheckNow
+	response      ResponseStrategy
+	routes        Routes // see WithRoutes
+	shutdownDelay time.Duration
+	shuttingDown  atomic.Bool
+	shadow        atomic.Bool // see Rollout
+	state         atomic.Int32
+	lastCycle     atomic.Int64
+	nextCycle     atomic.Int64
+	cycles        atomic.Uint64
+	changesMu     sync.Mutex
+	changes       []Change
+	history       HistoryStore
+	retention     *retention   // see WithHistoryRetention
+	textfile      *textfile    // see WithTextfileExporter
+	pushgateway   *pushgateway // see WithPushgateway
+	overridesMu   sync.RWMutex
+	overrides     map[string]override
+	hysteresis    map[ProbeGroup]hysteresis
+	verdicts      groupVerdicts
+	parent        *Inspector // set for children, see Child
+	scope         string     // scope of the child
+	childrenMu    sync.Mutex
+	redactor      func(error) string
+	tracer        Tracer
+	exemplar      ExemplarFunc
+	children      []*Inspector
+	emptyGroup    EmptyGroupPolicy
+	scopeMetric   *prometheus.GaugeVec
+	scopeMetricMu sync.Mutex
+	scopesSeen    map[string]bool // scopes of the last scope metric update
+	auditGrace    time.Duration   // cancellation audit mode if set
+	auditMu       sync.Mutex
+	audits        map[string]CancelAudit
+	cycleDuration prometheus.Histogram // see WithSelfMetrics
+	skippedCycles atomic.Uint64
+	inFlight      atomic.Int64 // checks in flight
+	groupMsgs     map[ProbeGroup]Messages
+	targetMsgs    map[string]Messages // keyed by "scope/dest"
+	providers     []func() []HealthCheckTarget
+	targetMetric  *prometheus.GaugeVec
+	reportedMu    sync.Mutex
+	reported      map[string]map[string]bool // names of MetricsReporter values per "scope/dest"
+	retry         retrySettings
+	stoppingMu    sync.Mutex
+	stopping      []func(ctx context.Context) error // see OnStopping
+	stoppingDone  atomic.Bool
+	namespace     string // prefix of the self metrics, see WithMetricNamespace
+	thresholds    thresholds
+	streaks       targetStreaks
+	metricLabels  []string // extra labels of per target metrics, see WithMetricLabels
+	webhook       *webhook
+	subscribers   subscribers // see Subscribe, WithOnStateChange
+	latchStartup  bool        // see WithStartupLatch
+	startupPassed atomic.Bool
+	forced        atomic.Pointer[forcedReadiness] // see SetReady, SetNotReady
+	execLog       ExecutionLog
 }
 
 func New(targets ...HealthCheckTarget) *Inspector {
 	return &Inspector{
 		targets:     targets,
 		checkPeriod: defCheckPeriod,
+		reload:      make(chan struct{}, 1),
 		data:        unsafe.Pointer(newHealthResult()),
+		response:    DefResponseStrategy,
+		logLevels:   DefLogLevels,
 	}
 }
 
@@ -100,6 +257,50 @@ func WithTargets(targets ...HealthCheckTarget) Option {
 	}
 }
 
+// WithTargetsFromProvider - targets contributed lazily by the provider called once at Start,
+// e.g. by DI containers after all constructors have run.
+func WithTargetsFromProvider(provider func() []HealthCheckTarget) Option {
+	return func(i *Inspector) error {
+		if provider == nil {
+			return errMissProvider
+		}
+
+		i.providers = append(i.providers, provider)
+
+		return nil
+	}
+}
+
+// resolveProviders - adds targets of the providers, once.
+func (i *Inspector) resolveProviders() error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	var provided []HealthCheckTarget
+
+	for _, provider := range i.providers {
+		for _, target := range provider() {
+			if target.Service == nil {
+				return errMissService
+			}
+
+			if err := target.Groups.validate(); err != nil {
+				return fmt.Errorf("provided target %s/%s: %w", target.Service.Scope(), target.Service.Dest(), err)
+			}
+
+			target.provided = true
+			provided = append(provided, target)
+		}
+	}
+
+	i.targets = append(slices.Clip(i.targets), provided...)
+	i.providers = nil
+
+	return nil
+}
+
+// WithMetric - gauge with labels "scope", "dest" set to 1 if the reported state of the target is healthy,
+// otherwise 0; the state is under thresholds (see WithThresholds) like the probes, other metrics count raw checks.
 func WithMetric(metric *prometheus.GaugeVec) Option {
 	return func(i *Inspector) error {
 		if metric == nil {
@@ -108,7 +309,7 @@ func WithMetric(metric *prometheus.GaugeVec) Option {
 			return nil
 		}
 
-		if err := validateMetricLabels(metric); err != nil {
+		if err := i.validateLabels("WithMetric", metric.MetricVec, "scope", "dest"); err != nil {
 			return err
 		}
 
@@ -130,9 +331,38 @@ func WithCheckPeriod(p time.Duration) Option {
 	}
 }
 
+// WithCycleTimeout - bounds duration of one check cycle, targets which didn't finish
+// are recorded as timed out and the cycle result is published on time.
+func WithCycleTimeout(d time.Duration) Option {
+	return func(i *Inspector) error {
+		if d <= 0 {
+			return errWrongTimeout
+		}
+
+		i.cycleTimeout = d
+
+		return nil
+	}
+}
+
+// WithCheckTimeout - bounds every check call of the targets (see also HealthCheckTarget.Timeout),
+// so one hanging dependency doesn't block the whole check cycle.
+func WithCheckTimeout(d time.Duration) Option {
+	return func(i *Inspector) error {
+		if d <= 0 {
+			return errWrongTimeout
+		}
+
+		i.checkTimeout = d
+
+		return nil
+	}
+}
+
+// CheckGroup - verdict of the group, for a combined mask (e.g. GroupLive|GroupReady) every
+// group is evaluated by itself and the mask is healthy only if all of them are.
 func (i *Inspector) CheckGroup(group ProbeGroup, needAllHealthy bool) error {
-	res := i.get()
-	return res.health(group, needAllHealthy)
+	return i.CheckGroupPolicy(group, policyOf(needAllHealthy))
 }
 
 var DefResponseProcessor = func(err error) []byte {
@@ -143,34 +373,89 @@ var DefResponseProcessor = func(err error) []byte {
 	return []byte("OK")
 }
 
+// HealthHandler - probe handler of the group, response is written by the ResponseStrategy
+// (see WithResponseStrategy, WithResponseFormat), toResponse could be nil,
+// otherwise it renders the body instead of the strategy formatter.
 func (i *Inspector) HealthHandler(group ProbeGroup, needAllHealthy bool, toResponse func(error) []byte) http.HandlerFunc {
-	if toResponse == nil {
-		toResponse = DefResponseProcessor
-	}
+	return i.HealthHandlerPolicy(group, policyOf(needAllHealthy), toResponse)
+}
 
-	return func(w http.ResponseWriter, _ *http.Request) {
-		if err := i.CheckGroup(group, needAllHealthy); err != nil {
-			w.WriteHeader(http.StatusServiceUnavailable)
-			w.Write(toResponse(err))
+func (i *Inspector) probeReport(group ProbeGroup, err error) ProbeReport {
+	report := ProbeReport{Group: group, Err: err, Messages: i.groupMsgs[group], TargetMessages: i.targetMsgs}
+	inGroup := make(map[string]bool)
 
-			return
+	res := i.result()
+	if res.checked {
+		report.CheckedAt, report.Seq = res.checkedAt, res.seq
+	}
+
+	for _, tr := range res.targets {
+		if tr.Groups&group != 0 {
+			tr.Err = tr.errFor(group)
+			report.Targets = append(report.Targets, tr)
+			inGroup[targetKey(tr.Scope, tr.Dest)] = true
 		}
+	}
 
-		w.WriteHeader(http.StatusOK)
-		w.Write(toResponse(nil))
+	for _, c := range i.Changes(time.Now().Add(-recentChanges)) {
+		if inGroup[targetKey(c.Scope, c.Dest)] {
+			report.Changed = append(report.Changed, c)
+		}
 	}
+
+	return report
 }
 
 func (i *Inspector) Start(ctx context.Context) error {
+	if i.parent != nil {
+		return errChildLifecycle
+	}
+
+	if err := i.resolveProviders(); err != nil {
+		return err
+	}
+
 	i.stopCh = make(chan struct{})
 	i.confirmStopCh = make(chan struct{})
+	i.shuttingDown.Store(false)
+	i.stoppingDone.Store(false)
+	i.state.Store(int32(StateRunning))
+	i.warnEmptyGroups()
 
-	go i.start(ctx)
+	if i.webhook != nil {
+		go i.webhook.run(ctx, i.stopCh, i.log())
+	}
+
+	if i.retention != nil {
+		go i.runRetention(ctx, i.stopCh)
+	}
+
+	go i.start(ctx, i.stopCh, i.confirmStopCh)
 
 	return nil
 }
 
+// Stop - flips readiness (see BeginShutdown), runs OnStopping hooks and stops periodically health checks.
+// Checks are stopped even if ctx ends during the shutdown delay or hooks fail, errors of the delay,
+// the hooks (and of the final push, see WithPushgateway) are returned.
 func (i *Inspector) Stop(ctx context.Context) error {
+	var delayErr error
+
+	if err := i.BeginShutdown(ctx); err != nil {
+		delayErr = fmt.Errorf("begin shutdown: %w", err)
+	}
+
+	hooksErr := i.runStopping(ctx)
+
+	if err := i.halt(ctx); err != nil {
+		return errors.Join(delayErr, hooksErr, err)
+	}
+
+	return errors.Join(delayErr, hooksErr, i.pushFinal(ctx))
+}
+
+// halt - stops periodically health checks without flipping readiness and running hooks.
+func (i *Inspector) halt(ctx context.Context) error {
 	if i.stopCh == nil {
 		return nil
 	}
@@ -178,6 +463,10 @@ func (i *Inspector) Stop(ctx context.Context) error {
 	close(i.stopCh)
 	i.stopCh = nil
 
+	if ctx.Err() != nil { // the loop is still awaited, so it never outlives Stop
+		ctx = context.WithoutCancel(ctx)
+	}
+
 	ctx, cancel := context.WithTimeout(ctx, shutdownTimeout)
 	defer cancel()
 
@@ -189,12 +478,28 @@ func (i *Inspector) Stop(ctx context.Context) error {
 	}
 }
 
-func (i *Inspector) start(ctx context.Context) {
-	ticker := time.NewTicker(i.checkPeriod)
+func (i *Inspector) start(ctx context.Context, stopCh <-chan struct{}, confirmStopCh chan<- struct{}) {
+	period := i.cyclePeriod()
+
+	ticker := time.NewTicker(period)
 	defer ticker.Stop()
-	defer close(i.confirmStopCh) // waiting all job to be done
+	defer close(confirmStopCh) // waiting all job to be done
+	defer i.state.Store(int32(StateStopped))
+	defer i.logAt(i.logLevels.Lifecycle, "healthz: inspector stopped")
 
-	i.check(ctx)
+	i.logAt(i.logLevels.Lifecycle, "healthz: inspector started", "period", period, "onDemand", i.onDemand != nil)
+
+	if i.onDemand != nil { // checks are triggered by probes
+		select {
+		case <-ctx.Done():
+		case <-stopCh:
+		}
+
+		return
+	}
+
+	i.nextCycle.Store(time.Now().Add(period).UnixNano())
+	i.timedCheck(ctx, period)
 
 	for {
 		select {
@@ -206,65 +511,392 @@ func (i *Inspector) start(ctx context.Context) {
 		select {
 		case <-ctx.Done():
 			return
-		case <-i.stopCh:
+		case <-stopCh:
 			return
-		case <-ticker.C:
-			i.check(ctx)
+		case <-i.reload:
+			if p := i.cyclePeriod(); p != period { // the next cycle is due in the new period from now
+				period = p
+				ticker.Reset(period)
+				i.nextCycle.Store(time.Now().Add(period).UnixNano())
+			}
+		case tick := <-ticker.C:
+			if p := i.cyclePeriod(); p != period { // reloaded
+				period = p
+				ticker.Reset(period)
+			}
+
+			i.nextCycle.Store(tick.Add(period).UnixNano())
+			i.timedCheck(ctx, period)
 		}
 	}
 }
 
+func (i *Inspector) timedCheck(ctx context.Context, period time.Duration) {
+	start := time.Now()
+	i.check(ctx)
+	i.observeCycle(time.Since(start), period)
+}
+
+// reloaded - wakes the check loop to apply the reloaded period at once, not after the current tick.
+func (i *Inspector) reloaded() {
+	select {
+	case i.reload <- struct{}{}:
+	default: // the loop is already woken (or not started)
+	}
+}
+
+func (i *Inspector) period() time.Duration {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	return i.checkPeriod
+}
+
 type serviceCheckResult struct {
-	target HealthCheckTarget
-	err    error
+	idx       int
+	target    HealthCheckTarget
+	err       error
+	degraded  error                // reason of StatusDegraded, err is nil then
+	groupErrs map[ProbeGroup]error // results of MultiGroupChecker per group
+	duration  time.Duration        // zero if the check didn't finish
+	queueWait time.Duration        // from the cycle start till the check started, see WithQueueWaitMetric
+	reported  error                // err under thresholds (see applyThresholds), set for the metrics
+	details   map[string]any       // set by DetailedChecker
+	exemplar  prometheus.Labels    // trace of the check, see WithTracer
+	values    map[string]float64   // set by MetricsReporter
 }
 
 func (i *Inspector) check(ctx context.Context) {
-	result := healthResult{}
+	i.checkCycle(ctx, false)
+}
+
+// checkCycle - checks the targets and publishes the result, all checks the targets not due
+// by the adaptive schedule too.
+func (i *Inspector) checkCycle(ctx context.Context, all bool) {
+	i.cycleMu.Lock()
+	defer i.cycleMu.Unlock()
+
+	i.mu.RLock()
+	targets, cycleTimeout, checkTimeout, maxChecks := i.targets, i.cycleTimeout, i.checkTimeout, i.maxChecks
+	i.mu.RUnlock()
+
+	result := healthResult{
+		checked:   true,
+		checkedAt: time.Now(),
+		targets:   make([]TargetResult, len(targets)),
+	}
+
+	var deadline <-chan struct{}
+
+	if cycleTimeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, cycleTimeout)
+		defer cancel()
+
+		deadline = ctx.Done()
+	}
+
+	cycle := i.cycles.Add(1)
+
+	i.logAt(i.logLevels.Cycle, "healthz: check cycle started", "cycle", cycle, "targets", len(targets))
 
 	g, ctx := errgroup.WithContext(ctx)
+	if maxChecks > 0 {
+		g.SetLimit(maxChecks)
+	}
+
+	// buffered for all targets, late checks mustn't block after the cycle deadline
+	chResult := make(chan serviceCheckResult, len(targets))
+
+	done := make([]bool, len(targets))
+	due := make([]int, 0, len(targets))
+
+	carried := i.carriedResults(targets, result.checkedAt, all)
+
+	for idx := range targets {
+		if tr, ok := carried[idx]; ok {
+			result.targets[idx], done[idx] = tr, true
+
+			continue
+		}
+
+		due = append(due, idx)
+	}
+
+	queued := result.checkedAt
+
+	work := func(idx int, release func()) func() error {
+		return func() error {
+			defer release()
 
-	chResult := make(chan serviceCheckResult, 1)
+			i.inFlight.Add(1)
+			defer i.inFlight.Add(-1)
 
-	for _, target := range i.targets {
-		g.Go(func() error {
-			chResult <- serviceCheckResult{target: target, err: target.Service.Health(ctx)}
+			queueWait := time.Since(queued)
+
+			res := i.checkInSlot(ctx, cycle, targets[idx], checkTimeout)
+			res.idx = idx
+			res.queueWait = queueWait
+
+			chResult <- res
 
 			return nil
-		})
+		}
 	}
 
+	// g.Go blocks at the limit, the cycle deadline is watched meanwhile. Targets of a limited scope
+	// take the scope slot first on the dispatcher of the scope, so a saturated scope doesn't hold
+	// slots of WithMaxConcurrency while targets of other scopes wait.
 	go func() {
-		_ = g.Wait()
+		var scoped sync.WaitGroup
+
+		queues := make(map[string]chan int)
+
+		for _, idx := range fairOrder(targets, due) {
+			scope := targets[idx].Service.Scope()
+			if _, limited := i.scopeSlots[scope]; !limited {
+				g.Go(work(idx, func() {}))
+
+				continue
+			}
+
+			queue, ok := queues[scope]
+			if !ok {
+				queue = make(chan int, len(due))
+				queues[scope] = queue
+
+				scoped.Add(1)
+
+				go func() {
+					defer scoped.Done()
+
+					for idx := range queue {
+						release, err := i.acquireScope(ctx, scope)
+						if err != nil {
+							chResult <- serviceCheckResult{idx: idx, target: targets[idx], err: err}
+
+							continue
+						}
+
+						g.Go(work(idx, release))
+					}
+				}()
+			}
 
-		close(chResult)
+			queue <- idx
+		}
+
+		for _, queue := range queues {
+			close(queue)
+		}
+
+		scoped.Wait()
+
+		_ = g.Wait() // releases the group context when late checks are done
 	}()
 
-	for resTarget := range chResult {
-		result.add(resTarget)
-		i.updateMetric(resTarget.target.Service, resTarget.err)
+	checked := make([]serviceCheckResult, 0, len(targets))
+	shadow := i.shadow.Load() // results of the shadow aren't exported, see Rollout
+
+	for received := len(carried); received < len(targets); {
+		select {
+		case resTarget := <-chResult:
+			received++
+			done[resTarget.idx] = true
+
+			result.add(resTarget)
+			i.logFailure(cycle, resTarget)
+			checked = append(checked, resTarget)
+		case <-deadline:
+			for idx, target := range targets {
+				if done[idx] {
+					continue
+				}
+
+				received++
+
+				timedOut := serviceCheckResult{idx: idx, target: target, err: errCycleTimeout}
+
+				result.add(timedOut)
+				i.logFailure(cycle, timedOut)
+				checked = append(checked, timedOut)
+			}
+		}
 	}
 
-	pointer := unsafe.Pointer(&result)
-	atomic.StorePointer(&i.data, pointer)
+	i.updatePaces(targets, result.targets, result.checkedAt)
+	i.applyThresholds(targets, result.targets)
+	i.trackStates(result.targets, result.checkedAt)
+
+	metricErrs := make([]error, 0, len(checked)+1)
+
+	if !shadow {
+		for _, res := range checked {
+			res.reported = result.targets[res.idx].Err // the gauge agrees with the probes
+			metricErrs = append(metricErrs, i.updateTargetMetrics(res)...)
+		}
+	}
+
+	result.targets = i.registered(result.targets)
+
+	if !shadow {
+		metricErrs = append(metricErrs, i.updateScopeMetric(result.targets))
+	}
+
+	if i.hasMetrics() {
+		result.targets = append(result.targets, metricSinkResult(metricErrs))
+	}
+
+	if i.history != nil && !shadow {
+		result.targets = append(result.targets, i.recordHistory(result.targets, time.Now()))
+	}
+
+	changes := i.publish(&result)
+
+	i.logChanges(changes)
+	i.logAt(i.logLevels.Cycle, "healthz: check cycle finished",
+		"cycle", cycle, "seq", result.seq, "duration", time.Since(result.checkedAt))
+
+	if !shadow {
+		notified := i.unsilenced(changes)
+
+		i.notifyWebhook(notified, &result)
+		i.notifyState(notified, &result)
+		i.exportTextfile()
+		i.pushMetrics(context.WithoutCancel(ctx)) // the cycle context is done, the push is bounded by the client timeout
+	}
+
+	i.pruneOverrides()
+	i.lastCycle.Store(time.Now().UnixNano())
 }
 
-func (i *Inspector) get() *healthResult {
-	pointer := atomic.LoadPointer(&i.data)
-	data := (*healthResult)(pointer)
+// checkTarget - checks the target within the limit of its scope, see WithScopeConcurrency.
+func (i *Inspector) checkTarget(ctx context.Context, cycle uint64, target HealthCheckTarget, timeout time.Duration) serviceCheckResult {
+	waitStart := time.Now()
 
-	return data
+	release, err := i.acquireScope(ctx, target.Service.Scope())
+	if err != nil {
+		return serviceCheckResult{target: target, err: err}
+	}
+	defer release()
+
+	queueWait := time.Since(waitStart)
+
+	res := i.checkInSlot(ctx, cycle, target, timeout)
+	res.queueWait = queueWait
+
+	return res
 }
 
-func (i *Inspector) updateMetric(svc HealthCheckable, err error) {
-	if i.metric == nil {
+// checkInSlot - checks the target, the slot of its scope is already taken.
+func (i *Inspector) checkInSlot(ctx context.Context, cycle uint64, target HealthCheckTarget, timeout time.Duration) (res serviceCheckResult) {
+	res.target = target
+
+	ctx, endSpan, exemplar := i.startSpan(ctx, target)
+	res.exemplar = exemplar
+
+	defer i.auditCancel(ctx, target)()
+
+	start := time.Now()
+	defer func() {
+		res.duration = time.Since(start)
+		endSpan(res.err)
+
+		if mr, ok := target.Service.(MetricsReporter); ok {
+			res.values = mr.HealthMetrics()
+		}
+	}()
+
+	info := CheckInfo{Cycle: cycle, Attempt: 1, Group: target.Groups}
+
+	if target.Timeout > 0 {
+		timeout = target.Timeout
+	}
+
+	for {
+		started := time.Now()
+
+		i.runCheck(ctx, target, info, timeout, &res)
+		i.logExecution(target, info, started, &res)
+
+		if !i.retryAllowed(ctx, target, info.Attempt, res.err) {
+			return res
+		}
+
+		info.Attempt++
+	}
+}
+
+// runCheck - one attempt of the target check.
+func (i *Inspector) runCheck(ctx context.Context, target HealthCheckTarget, info CheckInfo, timeout time.Duration, res *serviceCheckResult) {
+	res.err, res.degraded, res.groupErrs, res.details = nil, nil, nil, nil
+
+	// every call gets own timeout
+	callCtx := func(info CheckInfo) (context.Context, context.CancelFunc) {
+		if timeout <= 0 {
+			return withCheckInfo(ctx, info), func() {}
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+
+		return withCheckInfo(ctx, info), cancel
+	}
+
+	mgc, ok := target.Service.(MultiGroupChecker)
+	if !ok {
+		callCtx, cancel := callCtx(info)
+		defer cancel()
+
+		if dc, ok := target.Service.(DetailedChecker); ok {
+			res.details, res.err = dc.HealthDetails(callCtx)
+		} else {
+			res.err = target.Service.Health(callCtx)
+		}
+
+		res.err, res.degraded = splitDegraded(res.err)
+
 		return
 	}
 
-	healthy := 0.0
-	if err == nil {
-		healthy = 1.0
+	res.groupErrs = make(map[ProbeGroup]error)
+
+	var errs, degraded []error
+
+	for _, group := range groupBits(target.Groups) {
+		info.Group = group
+
+		callCtx, cancel := callCtx(info)
+		err, reason := splitDegraded(mgc.GroupHealth(callCtx, group))
+
+		cancel()
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", group, err))
+		}
+
+		if reason != nil {
+			degraded = append(degraded, fmt.Errorf("%s: %w", group, reason))
+		}
+
+		res.groupErrs[group] = err
+	}
+
+	res.err, res.degraded = errors.Join(errs...), errors.Join(degraded...)
+}
+
+func (i *Inspector) store(result *healthResult) {
+	pointer := unsafe.Pointer(result)
+	atomic.StorePointer(&i.data, pointer)
+}
+
+func (i *Inspector) get() *healthResult {
+	if i.parent != nil {
+		return i.parent.result().scoped(i.scope)
 	}
 
-	i.metric.WithLabelValues(svc.Scope(), svc.Dest()).Set(healthy)
+	pointer := atomic.LoadPointer(&i.data)
+	data := (*healthResult)(pointer)
+
+	return data
 }
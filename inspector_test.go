@@ -440,3 +440,128 @@ func TestStopEdgeCases(t *testing.T) {
 		}
 	})
 }
+
+func TestCycleTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	fast := &mockService{scope: "test", dest: "fast"}
+	slow := &mockService{scope: "test", dest: "slow", callBack: func() { <-release }}
+
+	inspector := New(
+		HealthCheckTarget{Service: fast, Groups: GroupLive},
+		HealthCheckTarget{Service: slow, Groups: GroupReady},
+	)
+	assert.Error(t, WithCycleTimeout(0)(inspector))
+	assert.NoError(t, WithCycleTimeout(20*time.Millisecond)(inspector))
+
+	start := time.Now()
+	inspector.check(context.Background())
+	assert.Less(t, time.Since(start), testTimeout)
+
+	assert.NoError(t, inspector.CheckGroup(GroupLive, true))
+	assert.ErrorIs(t, inspector.CheckGroup(GroupReady, true), errCycleTimeout)
+}
+
+func TestCheckTimeout(t *testing.T) {
+	hanging := &lagService{dest: "hanging"}
+	slowOwn := &lagService{dest: "own-timeout"}
+	fast := &mockService{scope: "audit", dest: "fast"}
+
+	inspector := New(
+		HealthCheckTarget{Service: hanging, Groups: GroupReady},
+		HealthCheckTarget{Service: slowOwn, Groups: GroupLive, Timeout: 5 * time.Millisecond},
+		HealthCheckTarget{Service: fast, Groups: GroupStartup},
+	)
+	assert.ErrorIs(t, WithCheckTimeout(0)(inspector), errWrongTimeout)
+	assert.NoError(t, WithCheckTimeout(20*time.Millisecond)(inspector))
+
+	start := time.Now()
+	inspector.check(context.Background())
+	assert.Less(t, time.Since(start), testTimeout)
+
+	assert.ErrorIs(t, inspector.CheckGroup(GroupReady, true), context.DeadlineExceeded)
+	assert.ErrorIs(t, inspector.CheckGroup(GroupLive, true), context.DeadlineExceeded)
+	assert.NoError(t, inspector.CheckGroup(GroupStartup, true))
+
+	t.Run("Deadline in CheckInfo", func(t *testing.T) {
+		svc := &infoService{}
+
+		inspector := New(HealthCheckTarget{Service: svc, Groups: GroupLive, Timeout: time.Minute})
+		inspector.check(context.Background())
+
+		assert.Len(t, svc.infos, 1)
+		assert.WithinDuration(t, time.Now().Add(time.Minute), svc.infos[0].Deadline, time.Second)
+	})
+}
+
+type multiGroupService struct {
+	mockService
+	groupErrs map[ProbeGroup]error
+	called    []ProbeGroup
+	mu        sync.Mutex
+}
+
+func (m *multiGroupService) GroupHealth(ctx context.Context, group ProbeGroup) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if info, ok := CheckInfoFromContext(ctx); ok && info.Group == group {
+		m.called = append(m.called, group)
+	}
+
+	return m.groupErrs[group]
+}
+
+func TestMultiGroupChecker(t *testing.T) {
+	errReady := errors.New("query failed")
+	svc := &multiGroupService{
+		mockService: mockService{scope: "database", dest: "pg", healthErr: errors.New("must not be called")},
+		groupErrs:   map[ProbeGroup]error{GroupReady: errReady},
+	}
+
+	inspector := New(HealthCheckTarget{Service: svc, Groups: GroupLive | GroupReady})
+	inspector.check(context.Background())
+
+	assert.ElementsMatch(t, []ProbeGroup{GroupLive, GroupReady}, svc.called)
+	assert.NoError(t, inspector.CheckGroup(GroupLive, true))
+	assert.ErrorIs(t, inspector.CheckGroup(GroupReady, true), errReady)
+
+	tr := inspector.Snapshot().Targets[0]
+	assert.ErrorIs(t, tr.Err, errReady)
+	assert.ErrorContains(t, tr.Err, "ready: query failed")
+}
+
+func TestWithTargetsFromProvider(t *testing.T) {
+	static := HealthCheckTarget{Service: &mockService{scope: "db", dest: "pg"}, Groups: GroupReady}
+
+	var constructed []HealthCheckTarget // filled by "constructors" after the inspector is created
+
+	inspector := New(static)
+	assert.NoError(t, WithTargetsFromProvider(func() []HealthCheckTarget { return constructed })(inspector))
+
+	constructed = append(constructed, HealthCheckTarget{Service: &mockService{scope: "cache", dest: "redis"}, Groups: GroupLive})
+
+	assert.NoError(t, inspector.Start(context.Background()))
+	assert.Len(t, inspector.targets, 2)
+
+	assert.NoError(t, inspector.Stop(context.Background()))
+	assert.NoError(t, inspector.Start(context.Background()))
+	assert.Len(t, inspector.targets, 2, "provider is called once")
+	assert.NoError(t, inspector.Stop(context.Background()))
+
+	t.Run("Invalid provided target", func(t *testing.T) {
+		inspector := New()
+		assert.NoError(t, WithTargetsFromProvider(func() []HealthCheckTarget {
+			return []HealthCheckTarget{{Service: &mockService{}, Groups: 251}}
+		})(inspector))
+
+		assert.ErrorIs(t, inspector.Start(context.Background()), errMissGroup)
+
+		inspector = New()
+		assert.NoError(t, WithTargetsFromProvider(func() []HealthCheckTarget { return []HealthCheckTarget{{Groups: GroupLive}} })(inspector))
+		assert.ErrorIs(t, inspector.Start(context.Background()), errMissService)
+	})
+
+	assert.ErrorIs(t, WithTargetsFromProvider(nil)(New()), errMissProvider)
+}
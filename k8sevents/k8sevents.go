@@ -0,0 +1,90 @@
+// Package k8sevents - emits Kubernetes Events on the owning pod when probe groups of the healthz inspector
+// change state, so `kubectl describe pod` shows "Readiness lost: kafka broker unreachable" without digging
+// into logs. Events are recorded by a Recorder, e.g. client-go record.EventRecorder bound to the pod object,
+// so the package doesn't depend on client-go.
+package k8sevents
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/art-frela/healthz"
+)
+
+// Types of the events.
+const (
+	EventTypeNormal  = "Normal"
+	EventTypeWarning = "Warning"
+)
+
+const maxMessage = 1024 // message limit of the events API
+
+var errMissRecorder = errors.New("missing event recorder")
+
+// Recorder - records an event of the pod, must not block (client-go recorders queue events).
+type Recorder interface {
+	Event(eventType, reason, message string)
+}
+
+// RecorderFunc - Recorder of a function, e.g. a closure over record.EventRecorder of client-go:
+//
+//	k8sevents.RecorderFunc(func(eventType, reason, message string) { recorder.Event(pod, eventType, reason, message) })
+type RecorderFunc func(eventType, reason, message string)
+
+func (f RecorderFunc) Event(eventType, reason, message string) { f(eventType, reason, message) }
+
+// transition - reason and message of the group transition.
+type transition struct {
+	reason  string
+	message string
+}
+
+// transitions - of the groups evaluated by healthz.StateChange, to unhealthy and back to healthy.
+var transitions = map[healthz.ProbeGroup][2]transition{
+	healthz.GroupStartup: {{"StartupFailed", "Startup failed"}, {"StartupPassed", "Startup passed"}},
+	healthz.GroupLive:    {{"LivenessLost", "Liveness lost"}, {"LivenessRestored", "Liveness restored"}},
+	healthz.GroupReady:   {{"ReadinessLost", "Readiness lost"}, {"ReadinessRestored", "Readiness restored"}},
+}
+
+// WithEvents - healthz option recording transitions of the startup, live and ready groups
+// (see healthz.WithOnStateChange): Warning events with the error of the group when it fails,
+// Normal ones when it recovers. Events leave the process, so errors are redacted by redactor,
+// healthz.RedactCredentials if nil.
+func WithEvents(recorder Recorder, redactor func(error) string) healthz.Option {
+	return func(i *healthz.Inspector) error {
+		if recorder == nil {
+			return errMissRecorder
+		}
+
+		if redactor == nil {
+			redactor = healthz.RedactCredentials
+		}
+
+		return healthz.WithOnStateChange(func(sc healthz.StateChange) { record(recorder, redactor, sc) })(i)
+	}
+}
+
+// record - event of the group transition, target transitions are skipped.
+func record(recorder Recorder, redactor func(error) string, sc healthz.StateChange) {
+	t, ok := transitions[sc.Group]
+	if !ok {
+		return
+	}
+
+	if sc.Healthy {
+		recorder.Event(EventTypeNormal, t[1].reason, t[1].message)
+
+		return
+	}
+
+	msg := t[0].message
+	if sc.Err != nil {
+		msg += ": " + redactor(sc.Err)
+	}
+
+	if len(msg) > maxMessage {
+		msg = strings.ToValidUTF8(msg[:maxMessage-3], "") + "..."
+	}
+
+	recorder.Event(EventTypeWarning, t[0].reason, msg)
+}
@@ -0,0 +1,132 @@
+package k8sevents
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/art-frela/healthz"
+	"github.com/stretchr/testify/assert"
+)
+
+type service struct{ failing atomic.Bool }
+
+func (s *service) Health(context.Context) error {
+	if s.failing.Load() {
+		return errors.New("dial tcp: broker unreachable, password=secret")
+	}
+
+	return nil
+}
+
+func (s *service) Scope() string { return "kafka" }
+func (s *service) Dest() string  { return "broker" }
+
+type event struct{ eventType, reason, message string }
+
+type recorder struct {
+	mu     sync.Mutex
+	events []event
+}
+
+func (r *recorder) Event(eventType, reason, message string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events = append(r.events, event{eventType, reason, message})
+}
+
+func (r *recorder) find(reason string) (event, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, e := range r.events {
+		if e.reason == reason {
+			return e, true
+		}
+	}
+
+	return event{}, false
+}
+
+func TestWithEvents(t *testing.T) {
+	assert.ErrorIs(t, WithEvents(nil, nil)(healthz.New()), errMissRecorder)
+
+	svc := &service{}
+	rec := &recorder{}
+
+	inspector := healthz.New(healthz.HealthCheckTarget{Service: svc, Groups: healthz.GroupReady})
+	assert.NoError(t, healthz.WithCheckPeriod(5*time.Millisecond)(inspector))
+	assert.NoError(t, WithEvents(rec, nil)(inspector))
+	assert.NoError(t, inspector.Start(context.Background()))
+
+	defer inspector.Stop(context.Background())
+
+	assert.Eventually(t, func() bool { return !inspector.Status().LastCycle.IsZero() }, time.Second, time.Millisecond)
+
+	svc.failing.Store(true)
+
+	assert.Eventually(t, func() bool {
+		_, ok := rec.find("ReadinessLost")
+		return ok
+	}, time.Second, time.Millisecond)
+
+	lost, _ := rec.find("ReadinessLost")
+	assert.Equal(t, EventTypeWarning, lost.eventType)
+	assert.True(t, strings.HasPrefix(lost.message, "Readiness lost: "), lost.message)
+	assert.Contains(t, lost.message, "broker unreachable")
+	assert.NotContains(t, lost.message, "secret", "errors are redacted")
+
+	svc.failing.Store(false)
+
+	assert.Eventually(t, func() bool {
+		_, ok := rec.find("ReadinessRestored")
+		return ok
+	}, time.Second, time.Millisecond)
+
+	restored, _ := rec.find("ReadinessRestored")
+	assert.Equal(t, event{EventTypeNormal, "ReadinessRestored", "Readiness restored"}, restored)
+}
+
+func TestRecord(t *testing.T) {
+	plain := func(err error) string { return err.Error() }
+
+	tests := []struct {
+		name   string
+		change healthz.StateChange
+		want   []event
+	}{
+		{
+			name:   "test.1 target transition",
+			change: healthz.StateChange{Scope: "kafka", Dest: "broker", Err: errors.New("fail")},
+		},
+		{
+			name:   "test.2 liveness lost",
+			change: healthz.StateChange{Group: healthz.GroupLive, Err: errors.New("deadlock")},
+			want:   []event{{EventTypeWarning, "LivenessLost", "Liveness lost: deadlock"}},
+		},
+		{
+			name:   "test.3 startup passed",
+			change: healthz.StateChange{Group: healthz.GroupStartup, Healthy: true},
+			want:   []event{{EventTypeNormal, "StartupPassed", "Startup passed"}},
+		},
+		{
+			name:   "test.4 long message",
+			change: healthz.StateChange{Group: healthz.GroupReady, Err: errors.New(strings.Repeat("x", 2*maxMessage))},
+			want:   []event{{EventTypeWarning, "ReadinessLost", "Readiness lost: " + strings.Repeat("x", maxMessage-19) + "..."}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &recorder{}
+			record(rec, plain, tt.change)
+
+			assert.Equal(t, tt.want, rec.events)
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package healthz
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Endpoints of KubernetesPreset.
+const (
+	PathLivez   = "/livez"
+	PathReadyz  = "/readyz"
+	PathHealthz = "/healthz"
+)
+
+// kubeCheck - named check of the Kubernetes style endpoint.
+type kubeCheck struct {
+	name string
+	err  error
+}
+
+// KubernetesPreset - /livez, /readyz and /healthz endpoints behaving like the ones of kube-apiserver,
+// so probes could be standardized across services: every check (ping, targets of the group named
+// "scope/dest", shutdown for /readyz) must pass, "ok" is returned then or the list of the checks with
+// ?verbose, failures are answered by 500 with the list and reasons withheld (logged by WithLogger).
+// Checks could be excluded by ?exclude=<name> (repeatable), every check is served on its own path
+// too, e.g. /readyz/database/pg. /healthz covers the targets of both live and ready groups.
+func KubernetesPreset(inspector *Inspector) http.Handler {
+	mux := http.NewServeMux()
+
+	for _, ep := range []struct {
+		name  string
+		path  string
+		group ProbeGroup
+	}{
+		{name: "livez", path: PathLivez, group: GroupLive},
+		{name: "readyz", path: PathReadyz, group: GroupReady},
+		{name: "healthz", path: PathHealthz, group: GroupLive | GroupReady},
+	} {
+		mux.Handle(ep.path, inspector.kubeRootHandler(ep.name, ep.group))
+		mux.Handle(ep.path+"/", http.StripPrefix(ep.path+"/", inspector.kubeCheckHandler(ep.group)))
+	}
+
+	return mux
+}
+
+// kubeChecks - checks of the endpoint of the group, in order of the targets.
+func (i *Inspector) kubeChecks(group ProbeGroup) []kubeCheck {
+	root := i
+	if i.parent != nil {
+		root = i.parent
+	}
+
+	root.mu.RLock()
+	targets := root.targets
+	root.mu.RUnlock()
+
+	res := i.result()
+
+	results := make(map[string]TargetResult, len(res.targets))
+	for _, tr := range res.targets {
+		results[targetKey(tr.Scope, tr.Dest)] = tr
+	}
+
+	checks := []kubeCheck{{name: "ping"}}
+
+	for _, target := range targets {
+		scope, dest := target.Service.Scope(), target.Service.Dest()
+		if target.Groups&group == 0 || i.parent != nil && scope != i.scope {
+			continue
+		}
+
+		tr, ok := results[targetKey(scope, dest)]
+
+		switch {
+		case !ok:
+			checks = append(checks, kubeCheck{name: targetKey(scope, dest), err: errNoYetChecked})
+		case tr.manual == "": // maintenance and disabled targets don't count
+			checks = append(checks, kubeCheck{name: targetKey(scope, dest), err: tr.errFor(group)})
+		}
+	}
+
+	if group&GroupReady != 0 {
+		var err error
+		if root.shuttingDown.Load() {
+			err = errShuttingDown
+		}
+
+		checks = append(checks, kubeCheck{name: "shutdown", err: err})
+	}
+
+	return checks
+}
+
+// kubeRootHandler - endpoint of all checks of the group, see KubernetesPreset.
+func (i *Inspector) kubeRootHandler(name string, group ProbeGroup) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		excluded := r.URL.Query()["exclude"]
+
+		var (
+			output bytes.Buffer
+			failed []any
+		)
+
+		for _, check := range i.kubeChecks(group) {
+			if n := slices.Index(excluded, check.name); n >= 0 {
+				excluded = slices.Delete(excluded, n, n+1)
+				fmt.Fprintf(&output, "[+]%s excluded: ok\n", check.name)
+
+				continue
+			}
+
+			if check.err != nil {
+				fmt.Fprintf(&output, "[-]%s failed: reason withheld\n", check.name)
+				failed = append(failed, check.name, i.redact(check.err).Error())
+
+				continue
+			}
+
+			fmt.Fprintf(&output, "[+]%s ok\n", check.name)
+		}
+
+		if len(excluded) > 0 {
+			quoted := make([]string, len(excluded))
+			for n, e := range excluded {
+				quoted[n] = strconv.Quote(e)
+			}
+
+			fmt.Fprintf(&output, "warn: some health checks cannot be excluded: no matches for %s\n", strings.Join(quoted, ", "))
+		}
+
+		if len(failed) > 0 {
+			i.logAt(i.logLevels.Failure, "healthz: "+name+" check failed", failed...)
+			http.Error(w, fmt.Sprintf("%s%s check failed", output.String(), name), http.StatusInternalServerError)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+
+		if _, verbose := r.URL.Query()["verbose"]; !verbose {
+			fmt.Fprint(w, "ok")
+
+			return
+		}
+
+		output.WriteTo(w)
+		fmt.Fprintf(w, "%s check passed\n", name)
+	})
+}
+
+// kubeCheckHandler - endpoint of a single check of the group named by the path, see KubernetesPreset.
+func (i *Inspector) kubeCheckHandler(group ProbeGroup) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, check := range i.kubeChecks(group) {
+			if check.name != r.URL.Path {
+				continue
+			}
+
+			if check.err != nil {
+				http.Error(w, fmt.Sprintf("internal server error: %v", i.redact(check.err)), http.StatusInternalServerError)
+
+				return
+			}
+
+			fmt.Fprint(w, "ok")
+
+			return
+		}
+
+		http.NotFound(w, r)
+	})
+}
@@ -0,0 +1,110 @@
+package healthz
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKubernetesPreset(t *testing.T) {
+	pg := &mockService{scope: "database", dest: "pg"}
+	redis := &mockService{scope: "cache", dest: "redis", healthErr: errors.New("dial tcp: connection refused")}
+
+	inspector := New(
+		HealthCheckTarget{Service: pg, Groups: GroupLive | GroupReady},
+		HealthCheckTarget{Service: redis, Groups: GroupReady},
+	)
+	inspector.check(context.Background())
+
+	handler := KubernetesPreset(inspector)
+
+	tests := []struct {
+		name     string
+		target   string
+		wantCode int
+		wantBody string
+		plain    bool // passed single checks are answered without headers, like by kube-apiserver
+	}{
+		{
+			name:     "test.1 livez",
+			target:   "/livez",
+			wantCode: http.StatusOK,
+			wantBody: "ok",
+		},
+		{
+			name:     "test.2 livez verbose",
+			target:   "/livez?verbose",
+			wantCode: http.StatusOK,
+			wantBody: "[+]ping ok\n[+]database/pg ok\nlivez check passed\n",
+		},
+		{
+			name:     "test.3 readyz failed",
+			target:   "/readyz",
+			wantCode: http.StatusInternalServerError,
+			wantBody: "[+]ping ok\n[+]database/pg ok\n[-]cache/redis failed: reason withheld\n[+]shutdown ok\nreadyz check failed\n",
+		},
+		{
+			name:     "test.4 readyz excluded",
+			target:   "/readyz?verbose&exclude=cache/redis&exclude=etcd",
+			wantCode: http.StatusOK,
+			wantBody: "[+]ping ok\n[+]database/pg ok\n[+]cache/redis excluded: ok\n[+]shutdown ok\n" +
+				"warn: some health checks cannot be excluded: no matches for \"etcd\"\nreadyz check passed\n",
+		},
+		{
+			name:     "test.5 healthz",
+			target:   "/healthz?exclude=cache/redis",
+			wantCode: http.StatusOK,
+			wantBody: "ok",
+		},
+		{
+			name:     "test.6 single check",
+			target:   "/readyz/database/pg",
+			wantCode: http.StatusOK,
+			wantBody: "ok",
+			plain:    true,
+		},
+		{
+			name:     "test.7 single check failed",
+			target:   "/readyz/cache/redis",
+			wantCode: http.StatusInternalServerError,
+			wantBody: "internal server error: dial tcp: connection refused\n",
+		},
+		{
+			name:     "test.8 unknown check",
+			target:   "/livez/cache/redis",
+			wantCode: http.StatusNotFound,
+			wantBody: "404 page not found\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			assert.Equal(t, tt.wantCode, w.Code)
+			assert.Equal(t, tt.wantBody, w.Body.String())
+
+			if !tt.plain {
+				assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+			}
+		})
+	}
+
+	inspector.shuttingDown.Store(true)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz/shutdown", nil))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	child, err := inspector.Child("database")
+	assert.NoError(t, err)
+
+	w = httptest.NewRecorder()
+	KubernetesPreset(child).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/livez?verbose", nil))
+	assert.Equal(t, "[+]ping ok\n[+]database/pg ok\nlivez check passed\n", w.Body.String())
+}
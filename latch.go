@@ -0,0 +1,34 @@
+package healthz
+
+// WithStartupLatch - GroupStartup, once it has passed, stays passed for the lifetime of the inspector
+// (Kubernetes startup probe semantics): dependencies flapping after boot don't fail the startup probe
+// and kubelet doesn't restart healthy pods. Live and ready groups are evaluated as usual.
+func WithStartupLatch() Option {
+	return func(i *Inspector) error {
+		i.latchStartup = true
+
+		return nil
+	}
+}
+
+// checkLatched - verdict of the group with the startup latch applied.
+func (i *Inspector) checkLatched(group ProbeGroup, check func(ProbeGroup) error) error {
+	if !i.latchStartup || group&GroupStartup == 0 {
+		return check(group)
+	}
+
+	if i.startupPassed.Load() {
+		if group &^= GroupStartup; group == 0 {
+			return nil
+		}
+
+		return check(group)
+	}
+
+	err := check(group)
+	if err == nil {
+		i.startupPassed.Store(true)
+	}
+
+	return err
+}
@@ -0,0 +1,45 @@
+package healthz
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithStartupLatch(t *testing.T) {
+	tests := []struct {
+		name        string
+		latch       bool
+		wantStartup bool // startup passes after the flap
+	}{
+		{name: "test.1 latched", latch: true, wantStartup: true},
+		{name: "test.2 not latched", latch: false, wantStartup: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &mockService{scope: "db", dest: "pg", healthErr: errors.New("booting")}
+			inspector := New(HealthCheckTarget{Service: svc, Groups: GroupStartup | GroupLive | GroupReady})
+
+			if tt.latch {
+				assert.NoError(t, WithStartupLatch()(inspector))
+			}
+
+			ctx := context.Background()
+
+			inspector.check(ctx)
+			assert.Error(t, inspector.CheckGroup(GroupStartup, false), "not passed yet")
+
+			svc.healthErr = nil
+			inspector.check(ctx)
+			assert.NoError(t, inspector.CheckGroup(GroupStartup, false))
+
+			svc.healthErr = errors.New("flap")
+			inspector.check(ctx)
+			assert.Equal(t, tt.wantStartup, inspector.CheckGroup(GroupStartup, false) == nil)
+			assert.Error(t, inspector.CheckGroup(GroupLive, false), "live isn't latched")
+			assert.Error(t, inspector.CheckGroup(GroupStartup|GroupReady, true), "other groups of the mask are evaluated")
+		})
+	}
+}
@@ -0,0 +1,150 @@
+// Package lbsync - propagates readiness of the healthz inspector to a load balancer for VM deployments
+// outside Kubernetes: the instance is registered in the target group (AWS ALB/NLB target group,
+// GCP instance group, ...) while ready and deregistered when it isn't. Cloud API calls are made
+// by Registrar implementations, so the package doesn't depend on cloud SDKs.
+package lbsync
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/art-frela/healthz"
+)
+
+const (
+	defResync      = 30 * time.Second
+	defCallTimeout = 10 * time.Second
+	firstCyclePoll = 100 * time.Millisecond
+)
+
+var errMissRegistrar = errors.New("missing inspector or registrar")
+
+// Registrar - membership of the instance in the load balancer, calls must be idempotent.
+type Registrar interface {
+	Register(ctx context.Context) error
+	Deregister(ctx context.Context) error
+}
+
+// Funcs - Registrar of functions, e.g. closures over elbv2 RegisterTargets/DeregisterTargets
+// of the AWS SDK or instanceGroups AddInstances/RemoveInstances of the GCP compute API.
+type Funcs struct {
+	RegisterFunc   func(ctx context.Context) error
+	DeregisterFunc func(ctx context.Context) error
+}
+
+func (f Funcs) Register(ctx context.Context) error   { return f.RegisterFunc(ctx) }
+func (f Funcs) Deregister(ctx context.Context) error { return f.DeregisterFunc(ctx) }
+
+type membership int8
+
+const (
+	unknown membership = iota
+	registered
+	deregistered
+)
+
+// Syncer - keeps membership of the instance in line with the ready group of the inspector
+// (all ready targets healthy): on its transitions and every Resync, which also retries failed calls.
+// The instance is deregistered as soon as the inspector begins shutdown (see healthz.Inspector.BeginShutdown),
+// so the load balancer drains it during the shutdown delay, and again by the OnStopping hook if that failed.
+type Syncer struct {
+	Resync      time.Duration // 30s if not positive
+	CallTimeout time.Duration // timeout of the registrar calls, 10s if not positive
+
+	inspector *healthz.Inspector
+	registrar Registrar
+
+	mu    sync.Mutex
+	state membership
+}
+
+// New - syncer of the inspector readiness, it's applied by Run.
+func New(inspector *healthz.Inspector, registrar Registrar) (*Syncer, error) {
+	if inspector == nil || registrar == nil {
+		return nil, errMissRegistrar
+	}
+
+	s := &Syncer{inspector: inspector, registrar: registrar}
+
+	inspector.OnStopping(func(ctx context.Context) error {
+		return s.apply(ctx, deregistered)
+	})
+
+	return s, nil
+}
+
+// Run - syncs until ctx is done, failed calls are logged (logger of the inspector) and retried. Returns ctx error.
+func (s *Syncer) Run(ctx context.Context) error {
+	events, unsubscribe := s.inspector.Subscribe(1)
+	defer unsubscribe()
+
+	resync := s.Resync
+	if resync <= 0 {
+		resync = defResync
+	}
+
+	ticker := time.NewTicker(resync)
+	defer ticker.Stop()
+
+	for {
+		wait := ticker.C
+		if s.inspector.Status().LastCycle.IsZero() { // the first result isn't a transition, poll for it
+			wait = time.After(firstCyclePoll)
+		}
+
+		if err := s.sync(ctx); err != nil && ctx.Err() == nil {
+			s.inspector.Logger().Warn("healthz: load balancer membership not synced", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-wait:
+		case <-events:
+		}
+	}
+}
+
+// sync - applies the current readiness, the instance is deregistered while the inspector is shutting down.
+func (s *Syncer) sync(ctx context.Context) error {
+	want := deregistered
+	if !s.inspector.ShuttingDown() && s.inspector.CheckGroup(healthz.GroupReady, true) == nil {
+		want = registered
+	}
+
+	return s.apply(ctx, want)
+}
+
+func (s *Syncer) apply(ctx context.Context, want membership) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.state == want {
+		return nil
+	}
+
+	timeout := s.CallTimeout
+	if timeout <= 0 {
+		timeout = defCallTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	call := s.registrar.Deregister
+	if want == registered {
+		call = s.registrar.Register
+	}
+
+	if err := call(ctx); err != nil {
+		s.state = unknown
+
+		return err
+	}
+
+	s.state = want
+
+	return nil
+}
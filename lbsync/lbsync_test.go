@@ -0,0 +1,131 @@
+package lbsync
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/art-frela/healthz"
+	"github.com/stretchr/testify/assert"
+)
+
+type service struct{ failing atomic.Bool }
+
+func (s *service) Health(context.Context) error {
+	if s.failing.Load() {
+		return errors.New("fail")
+	}
+
+	return nil
+}
+
+func (s *service) Scope() string { return "lb" }
+func (s *service) Dest() string  { return "app" }
+
+type registrar struct {
+	mu    sync.Mutex
+	calls []string
+	fail  bool
+}
+
+func (r *registrar) call(name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.fail {
+		r.fail = false
+
+		return errors.New("throttled")
+	}
+
+	r.calls = append(r.calls, name)
+
+	return nil
+}
+
+func (r *registrar) last() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.calls) == 0 {
+		return ""
+	}
+
+	return r.calls[len(r.calls)-1]
+}
+
+func TestSyncer(t *testing.T) {
+	svc := &service{}
+	inspector := healthz.New(healthz.HealthCheckTarget{Service: svc, Groups: healthz.GroupReady})
+	assert.NoError(t, healthz.WithCheckPeriod(10*time.Millisecond)(inspector))
+
+	reg := &registrar{fail: true}
+	syncer, err := New(inspector, Funcs{
+		RegisterFunc:   func(context.Context) error { return reg.call("register") },
+		DeregisterFunc: func(context.Context) error { return reg.call("deregister") },
+	})
+	assert.NoError(t, err)
+
+	syncer.Resync = 20 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	assert.NoError(t, inspector.Start(ctx))
+
+	done := make(chan error)
+	go func() { done <- syncer.Run(ctx) }()
+
+	isLast := func(call string) func() bool { return func() bool { return reg.last() == call } }
+
+	assert.Eventually(t, isLast("register"), time.Second, time.Millisecond, "failed call is retried")
+
+	svc.failing.Store(true)
+	assert.Eventually(t, isLast("deregister"), time.Second, time.Millisecond)
+
+	svc.failing.Store(false)
+	assert.Eventually(t, isLast("register"), time.Second, time.Millisecond)
+
+	assert.NoError(t, inspector.Stop(context.Background()))
+	assert.Equal(t, "deregister", reg.last(), "deregistered when stopping")
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+
+	_, err = New(nil, Funcs{})
+	assert.ErrorIs(t, err, errMissRegistrar)
+	_, err = New(inspector, nil)
+	assert.ErrorIs(t, err, errMissRegistrar)
+}
+
+func TestSyncer_beginShutdown(t *testing.T) {
+	inspector := healthz.New(healthz.HealthCheckTarget{Service: &service{}, Groups: healthz.GroupReady})
+	assert.NoError(t, healthz.WithCheckPeriod(10*time.Millisecond)(inspector))
+	assert.NoError(t, healthz.WithShutdownDelay(time.Second)(inspector))
+
+	reg := &registrar{}
+	syncer, err := New(inspector, Funcs{
+		RegisterFunc:   func(context.Context) error { return reg.call("register") },
+		DeregisterFunc: func(context.Context) error { return reg.call("deregister") },
+	})
+	assert.NoError(t, err)
+
+	syncer.Resync = time.Minute
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	assert.NoError(t, inspector.Start(ctx))
+
+	go syncer.Run(ctx)
+
+	assert.Eventually(t, func() bool { return reg.last() == "register" }, time.Second, time.Millisecond)
+
+	go inspector.BeginShutdown(ctx)
+
+	assert.Eventually(t, func() bool { return reg.last() == "deregister" }, 200*time.Millisecond, time.Millisecond,
+		"deregistered at the start of the shutdown delay")
+}
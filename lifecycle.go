@@ -0,0 +1,83 @@
+package healthz
+
+import (
+	"context"
+	"time"
+)
+
+// LifecycleState - state of the inspector check loop.
+type LifecycleState int32
+
+const (
+	StateNotStarted LifecycleState = iota
+	StateRunning
+	StateStopping
+	StateStopped
+)
+
+func (ls LifecycleState) String() string {
+	switch ls {
+	case StateNotStarted:
+		return "NotStarted"
+	case StateRunning:
+		return "Running"
+	case StateStopping:
+		return "Stopping"
+	case StateStopped:
+		return "Stopped"
+	default:
+		return "Unknown"
+	}
+}
+
+// LifecycleStatus - introspection data of the inspector.
+type LifecycleStatus struct {
+	State     LifecycleState
+	LastCycle time.Time // end of the last completed check cycle, zero if none
+	NextCycle time.Time // next scheduled check cycle, zero if not running
+}
+
+// Status - reports lifecycle state and check cycle timing.
+func (i *Inspector) Status() LifecycleStatus {
+	if i.parent != nil {
+		return i.parent.Status()
+	}
+
+	status := LifecycleStatus{
+		State:     LifecycleState(i.state.Load()),
+		LastCycle: unixNanoTime(i.lastCycle.Load()),
+	}
+
+	if status.State == StateRunning {
+		status.NextCycle = unixNanoTime(i.nextCycle.Load())
+	}
+
+	return status
+}
+
+func unixNanoTime(n int64) time.Time {
+	if n == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(0, n)
+}
+
+// Runner - runs the inspector until ctx is done and then stops it, for errgroup / oklog/run
+// style orchestration:
+//
+//	g.Go(func() error { return inspector.Runner()(ctx) })
+func (i *Inspector) Runner() func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		if err := i.Start(ctx); err != nil {
+			return err
+		}
+
+		<-ctx.Done()
+
+		stopCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), shutdownTimeout)
+		defer cancel()
+
+		return i.Stop(stopCtx)
+	}
+}
@@ -0,0 +1,76 @@
+package healthz
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLifecycleState_String(t *testing.T) {
+	assert.Equal(t, "NotStarted", StateNotStarted.String())
+	assert.Equal(t, "Running", StateRunning.String())
+	assert.Equal(t, "Stopping", StateStopping.String())
+	assert.Equal(t, "Stopped", StateStopped.String())
+	assert.Equal(t, "Unknown", LifecycleState(42).String())
+}
+
+func TestInspectorStatus(t *testing.T) {
+	inspector := New(HealthCheckTarget{Service: &mockService{}, Groups: GroupLive})
+	inspector.checkPeriod = time.Hour
+
+	status := inspector.Status()
+	assert.Equal(t, StateNotStarted, status.State)
+	assert.True(t, status.LastCycle.IsZero())
+	assert.True(t, status.NextCycle.IsZero())
+
+	assert.NoError(t, inspector.Start(context.Background()))
+	assert.Eventually(t, func() bool {
+		return !inspector.Status().LastCycle.IsZero()
+	}, time.Second, time.Millisecond)
+
+	status = inspector.Status()
+	assert.Equal(t, StateRunning, status.State)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), status.NextCycle, time.Second)
+
+	assert.NoError(t, inspector.BeginShutdown(context.Background()))
+	assert.Equal(t, StateStopping, inspector.Status().State)
+
+	assert.NoError(t, inspector.Stop(context.Background()))
+
+	status = inspector.Status()
+	assert.Equal(t, StateStopped, status.State)
+	assert.True(t, status.NextCycle.IsZero())
+}
+
+func TestRunner(t *testing.T) {
+	inspector := New(HealthCheckTarget{Service: &mockService{}, Groups: GroupLive})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+
+	go func() { done <- inspector.Runner()(ctx) }()
+
+	assert.Eventually(t, func() bool {
+		return inspector.CheckGroup(GroupLive, true) == nil
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, StateRunning, inspector.Status().State)
+
+	cancel()
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(testTimeout):
+		t.Fatal("runner didn't return")
+	}
+
+	assert.Equal(t, StateStopped, inspector.Status().State)
+}
+
+func TestRunner_child(t *testing.T) {
+	child, err := New().Child("db")
+	assert.NoError(t, err)
+	assert.ErrorIs(t, child.Runner()(context.Background()), errChildLifecycle)
+}
@@ -0,0 +1,193 @@
+package healthz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"slices"
+	"sync"
+)
+
+var (
+	errMissListeners  = errors.New("missing listeners")
+	errMissListenAddr = errors.New("missing listener address")
+	errWrongNetwork   = errors.New("wrong listener network, expected tcp or unix")
+	errUnknownPath    = errors.New("unknown endpoint path")
+)
+
+// defaultPaths - endpoints of Inspector.Handler.
+var defaultPaths = []string{PathStartup, PathLive, PathReady, PathScopes, PathStatus, PathSchedule, PathGraph}
+
+// ProbeListener - address serving the inspector endpoints.
+type ProbeListener struct {
+	Network string   // "tcp" (default) or "unix"
+	Addr    string   // host:port or socket path
+	Paths   []string // served endpoints (e.g. PathLive, PathReady or their routes, see WithRoutes), all if empty
+}
+
+func (pl ProbeListener) network() string {
+	if pl.Network == "" {
+		return "tcp"
+	}
+
+	return pl.Network
+}
+
+func (pl ProbeListener) validate() error {
+	if pl.Addr == "" {
+		return errMissListenAddr
+	}
+
+	if n := pl.network(); n != "tcp" && n != "unix" {
+		return fmt.Errorf("%w: %q", errWrongNetwork, n)
+	}
+
+	return nil
+}
+
+// MultiServer - serves the inspector endpoints on several listeners at once
+// (main app port, dedicated ops port, unix socket), each with own set of endpoints.
+type MultiServer struct {
+	OnListen func(listener ProbeListener, addr net.Addr) // reports the address actually listened
+
+	listeners []ProbeListener
+	servers   []*http.Server
+}
+
+// NewMultiServer - server of the inspector endpoints on the listeners.
+func NewMultiServer(inspector *Inspector, listeners ...ProbeListener) (*MultiServer, error) {
+	if len(listeners) == 0 {
+		return nil, errMissListeners
+	}
+
+	ms := &MultiServer{listeners: listeners}
+	handler := inspector.Handler()
+
+	for _, pl := range listeners {
+		if err := pl.validate(); err != nil {
+			return nil, fmt.Errorf("listener %s: %w", pl.Addr, err)
+		}
+
+		paths := make([]string, 0, len(pl.Paths))
+
+		for _, path := range pl.Paths {
+			routed, ok := inspector.routePath(path)
+			if !ok {
+				return nil, fmt.Errorf("listener %s: %w: %q", pl.Addr, errUnknownPath, path)
+			}
+
+			paths = append(paths, routed)
+		}
+
+		ms.servers = append(ms.servers, &http.Server{Handler: filterPaths(handler, paths)})
+	}
+
+	return ms, nil
+}
+
+// ListenAndServe - listens all addresses (fails if any of them can't be listened) and serves
+// until Shutdown, returns http.ErrServerClosed after Shutdown or errors of failed servers.
+func (ms *MultiServer) ListenAndServe() error {
+	lns := make([]net.Listener, 0, len(ms.listeners))
+
+	for _, pl := range ms.listeners {
+		ln, err := listen(pl)
+		if err != nil {
+			for _, opened := range lns {
+				opened.Close()
+			}
+
+			return fmt.Errorf("listen %s: %w", pl.Addr, err)
+		}
+
+		lns = append(lns, ln)
+	}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+
+	for idx, ln := range lns {
+		if ms.OnListen != nil {
+			ms.OnListen(ms.listeners[idx], ln.Addr())
+		}
+
+		wg.Add(1)
+
+		go func(srv *http.Server) {
+			defer wg.Done()
+
+			err := srv.Serve(ln)
+			if errors.Is(err, http.ErrServerClosed) {
+				return
+			}
+
+			mu.Lock()
+			errs = append(errs, err)
+			mu.Unlock()
+
+			// one failed listener stops others, like a single server does
+			ms.close()
+		}(ms.servers[idx])
+	}
+
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+
+	return http.ErrServerClosed
+}
+
+// Shutdown - gracefully shutdowns all servers, see http.Server.Shutdown.
+func (ms *MultiServer) Shutdown(ctx context.Context) error {
+	var errs []error
+
+	for _, srv := range ms.servers {
+		if err := srv.Shutdown(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (ms *MultiServer) close() {
+	for _, srv := range ms.servers {
+		srv.Close()
+	}
+}
+
+func listen(pl ProbeListener) (net.Listener, error) {
+	if pl.network() == "unix" {
+		// stale socket of the previous run
+		if fi, err := os.Stat(pl.Addr); err == nil && fi.Mode()&os.ModeSocket != 0 {
+			os.Remove(pl.Addr)
+		}
+	}
+
+	return net.Listen(pl.network(), pl.Addr)
+}
+
+// filterPaths - handler serving only the paths, all if empty.
+func filterPaths(handler http.Handler, paths []string) http.Handler {
+	if len(paths) == 0 {
+		return handler
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !slices.Contains(paths, r.URL.Path) {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		handler.ServeHTTP(w, r)
+	})
+}
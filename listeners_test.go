@@ -0,0 +1,115 @@
+package healthz
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMultiServer(t *testing.T) {
+	inspector := New(HealthCheckTarget{Service: &mockService{scope: "db", dest: "pg"}, Groups: AllGroups})
+	inspector.check(context.Background())
+
+	socket := filepath.Join(t.TempDir(), "healthz.sock")
+
+	app := ProbeListener{Addr: "127.0.0.1:0", Paths: []string{PathLive, PathReady}}
+	ops := ProbeListener{Addr: "127.0.0.1:0"}
+	unix := ProbeListener{Network: "unix", Addr: socket, Paths: []string{PathStatus}}
+
+	srv, err := NewMultiServer(inspector, app, ops, unix)
+	assert.NoError(t, err)
+
+	addrs := make(chan net.Addr, 3)
+	srv.OnListen = func(_ ProbeListener, addr net.Addr) { addrs <- addr }
+
+	done := make(chan error, 1)
+
+	go func() { done <- srv.ListenAndServe() }()
+
+	var listened []net.Addr
+
+	for range 3 {
+		select {
+		case addr := <-addrs:
+			listened = append(listened, addr)
+		case <-time.After(testTimeout):
+			t.Fatal("server didn't listen")
+		}
+	}
+
+	get := func(client *http.Client, host, path string) int {
+		resp, err := client.Get(fmt.Sprintf("http://%s%s", host, path))
+		if !assert.NoError(t, err) {
+			return 0
+		}
+
+		resp.Body.Close()
+
+		return resp.StatusCode
+	}
+
+	tcpClient := &http.Client{Transport: &http.Transport{}}
+
+	assert.Equal(t, http.StatusOK, get(tcpClient, listened[0].String(), PathReady))
+	assert.Equal(t, http.StatusNotFound, get(tcpClient, listened[0].String(), PathStatus), "status only on ops port")
+	assert.Equal(t, http.StatusOK, get(tcpClient, listened[1].String(), PathStatus))
+
+	unixClient := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", socket)
+		},
+	}}
+	assert.Equal(t, http.StatusOK, get(unixClient, "healthz", PathStatus))
+	assert.Equal(t, http.StatusNotFound, get(unixClient, "healthz", PathLive))
+
+	// spare connections of the clients would delay Shutdown
+	tcpClient.CloseIdleConnections()
+	unixClient.CloseIdleConnections()
+
+	assert.NoError(t, srv.Shutdown(context.Background()))
+	assert.ErrorIs(t, <-done, http.ErrServerClosed)
+}
+
+func TestNewMultiServer(t *testing.T) {
+	inspector := New()
+
+	tests := []struct {
+		name      string
+		listeners []ProbeListener
+		wantErr   error
+	}{
+		{name: "test.1 no listeners", wantErr: errMissListeners},
+		{name: "test.2 no address", listeners: []ProbeListener{{}}, wantErr: errMissListenAddr},
+		{name: "test.3 wrong network", listeners: []ProbeListener{{Network: "udp", Addr: ":0"}}, wantErr: errWrongNetwork},
+		{name: "test.4 unknown path", listeners: []ProbeListener{{Addr: ":0", Paths: []string{"/healthz"}}}, wantErr: errUnknownPath},
+		{name: "test.5 ok", listeners: []ProbeListener{{Addr: ":0", Paths: []string{PathLive}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewMultiServer(inspector, tt.listeners...)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMultiServer_ListenError(t *testing.T) {
+	busy, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer busy.Close()
+
+	srv, err := NewMultiServer(New(), ProbeListener{Addr: "127.0.0.1:0"}, ProbeListener{Addr: busy.Addr().String()})
+	assert.NoError(t, err)
+
+	assert.Error(t, srv.ListenAndServe())
+}
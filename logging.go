@@ -0,0 +1,96 @@
+package healthz
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+)
+
+var errMissLogger = errors.New("missing logger")
+
+// LogLevels - levels of the inspector logs, see WithLogger.
+type LogLevels struct {
+	Lifecycle  slog.Level // start and stop of the inspector
+	Cycle      slog.Level // start and finish of check cycles
+	Failure    slog.Level // failed checks of targets
+	Transition slog.Level // state changes of targets and probe groups
+}
+
+// DefLogLevels - levels of the inspector logs by default.
+var DefLogLevels = LogLevels{
+	Lifecycle:  slog.LevelInfo,
+	Cycle:      slog.LevelDebug,
+	Failure:    slog.LevelWarn,
+	Transition: slog.LevelInfo,
+}
+
+// WithLogger - logger of the inspector instead of slog.Default: besides warnings it logs start and stop,
+// check cycles, failed checks of targets (errors are redacted, see WithErrorRedactor) and state changes
+// of targets and probe groups at DefLogLevels (see WithLogLevels). Without it only warnings are logged.
+// Children log by the logger of the parent unless they have own.
+func WithLogger(logger *slog.Logger) Option {
+	return func(i *Inspector) error {
+		if logger == nil {
+			return errMissLogger
+		}
+
+		i.logger = logger
+
+		return nil
+	}
+}
+
+// WithLogLevels - levels of the logs written by the logger of WithLogger.
+func WithLogLevels(levels LogLevels) Option {
+	return func(i *Inspector) error {
+		i.logLevels = levels
+
+		return nil
+	}
+}
+
+// Logger - logger of the inspector warnings (see log), for packages extending the inspector.
+func (i *Inspector) Logger() *slog.Logger {
+	return i.log()
+}
+
+// log - logger of warnings: own, of the parent or slog.Default.
+func (i *Inspector) log() *slog.Logger {
+	if i.logger != nil {
+		return i.logger
+	}
+
+	if i.parent != nil {
+		return i.parent.log()
+	}
+
+	return slog.Default()
+}
+
+// logAt - writes the detailed log if the inspector has a logger, see WithLogger.
+func (i *Inspector) logAt(level slog.Level, msg string, args ...any) {
+	if i.logger == nil {
+		return
+	}
+
+	i.logger.Log(context.Background(), level, msg, args...)
+}
+
+// logFailure - logs the failed check of the target.
+func (i *Inspector) logFailure(cycle uint64, res serviceCheckResult) {
+	if res.err == nil {
+		return
+	}
+
+	i.logAt(i.logLevels.Failure, "healthz: target check failed",
+		"cycle", cycle, "scope", res.target.Service.Scope(), "dest", res.target.Service.Dest(),
+		"duration", res.duration, "error", i.redact(res.err))
+}
+
+// logChanges - logs state changes of the targets.
+func (i *Inspector) logChanges(changes []Change) {
+	for _, c := range changes {
+		i.logAt(i.logLevels.Transition, "healthz: target state changed",
+			"scope", c.Scope, "dest", c.Dest, "healthy", c.Healthy, "error", i.redact(c.Err))
+	}
+}
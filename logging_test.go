@@ -0,0 +1,67 @@
+package healthz
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithLogger(t *testing.T) {
+	assert.ErrorIs(t, WithLogger(nil)(New()), errMissLogger)
+
+	var buf bytes.Buffer
+
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	svc := &mockService{scope: "db", dest: "pg"}
+
+	inspector := New(HealthCheckTarget{Service: svc, Groups: GroupReady})
+	assert.NoError(t, WithLogger(logger)(inspector))
+	assert.NoError(t, WithErrorRedactor(RedactCredentials)(inspector))
+
+	inspector.check(context.Background())
+
+	assert.Contains(t, buf.String(), "level=DEBUG msg=\"healthz: check cycle started\" cycle=1 targets=1")
+	assert.Contains(t, buf.String(), "msg=\"healthz: check cycle finished\" cycle=1 seq=1")
+	assert.NotContains(t, buf.String(), "failed")
+
+	buf.Reset()
+
+	svc.healthErr = errors.New("dial postgres://user:secret@db")
+	inspector.check(context.Background())
+
+	out := buf.String()
+	assert.Contains(t, out, "level=WARN msg=\"healthz: target check failed\" cycle=2 scope=db dest=pg")
+	assert.Contains(t, out, "level=INFO msg=\"healthz: target state changed\" scope=db dest=pg healthy=false")
+	assert.Contains(t, out, "level=INFO msg=\"healthz: probe group state changed\" group=ready healthy=false")
+	assert.NotContains(t, out, "secret", "errors are redacted")
+
+	buf.Reset()
+
+	assert.NoError(t, WithLogLevels(LogLevels{Cycle: slog.LevelDebug - 4, Failure: slog.LevelError})(inspector))
+	inspector.check(context.Background())
+
+	assert.Contains(t, buf.String(), "level=ERROR msg=\"healthz: target check failed\"")
+	assert.NotContains(t, buf.String(), "check cycle", "below the handler level")
+}
+
+func TestInspector_log(t *testing.T) {
+	var buf bytes.Buffer
+
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+
+	inspector := New()
+	assert.Equal(t, slog.Default(), inspector.log())
+
+	assert.NoError(t, WithLogger(logger)(inspector))
+
+	child, err := inspector.Child("db")
+	assert.NoError(t, err)
+	assert.Equal(t, logger, child.log(), "children log by the parent logger")
+
+	child.warnEmptyGroups()
+	assert.Contains(t, buf.String(), "healthz: probe groups have no targets")
+}
@@ -0,0 +1,68 @@
+package healthz
+
+import "errors"
+
+var errEmptyMessages = errors.New("empty messages")
+
+// Messages - custom texts of probe responses matching conventions of legacy tooling.
+type Messages struct {
+	Healthy   string // e.g. "READY"
+	Unhealthy string // e.g. "STARTING", "DEGRADED"
+}
+
+// text - message of the state, fallback if not set.
+func (m Messages) text(healthy bool, fallback string) string {
+	msg := m.Unhealthy
+	if healthy {
+		msg = m.Healthy
+	}
+
+	if msg == "" {
+		return fallback
+	}
+
+	return msg
+}
+
+// WithGroupMessages - texts of the group probe responses (body of FormatPlain, "message" of FormatJSON,
+// summary line of FormatKubeVerbose), applied to every group of the mask.
+func WithGroupMessages(group ProbeGroup, msgs Messages) Option {
+	return func(i *Inspector) error {
+		if err := group.validate(); err != nil {
+			return err
+		}
+
+		if msgs == (Messages{}) {
+			return errEmptyMessages
+		}
+
+		if i.groupMsgs == nil {
+			i.groupMsgs = make(map[ProbeGroup]Messages)
+		}
+
+		i.groupMsgs[group] = msgs
+
+		for _, g := range groupBits(group) {
+			i.groupMsgs[g] = msgs
+		}
+
+		return nil
+	}
+}
+
+// WithTargetMessages - texts of the target line of FormatKubeVerbose instead of "ok" and "failed".
+func WithTargetMessages(scope, dest string, msgs Messages) Option {
+	return func(i *Inspector) error {
+		if msgs == (Messages{}) {
+			return errEmptyMessages
+		}
+
+		if i.targetMsgs == nil {
+			i.targetMsgs = make(map[string]Messages)
+		}
+
+		i.targetMsgs[targetKey(scope, dest)] = msgs
+
+		return nil
+	}
+}
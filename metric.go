@@ -0,0 +1,295 @@
+package healthz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Internal target reporting health of the metric pipeline, it belongs to GroupCommon only
+// and doesn't influence probes.
+const (
+	InternalScope = "healthz"
+	MetricsDest   = "metrics"
+)
+
+// WithMetricErrorsCounter - counter incremented on every failed metric update.
+func WithMetricErrorsCounter(counter prometheus.Counter) Option {
+	return func(i *Inspector) error {
+		i.metricErrors = counter
+
+		return nil
+	}
+}
+
+// Outcomes of a check for the outcome metric.
+const (
+	OutcomeOK       = "ok"
+	OutcomeDegraded = "degraded"
+	OutcomeError    = "error"
+	OutcomeTimeout  = "timeout"
+)
+
+// WithOutcomeMetric - counter with labels "scope", "dest", "outcome" (ok, degraded, error, timeout),
+// shows how often each target hits its timeout vs completes.
+func WithOutcomeMetric(counter *prometheus.CounterVec) Option {
+	return func(i *Inspector) error {
+		if counter != nil {
+			if err := i.validateLabels("WithOutcomeMetric", counter.MetricVec, "scope", "dest", "outcome"); err != nil {
+				return err
+			}
+		}
+
+		i.outcomeMetric = counter
+
+		return nil
+	}
+}
+
+// WithLatencyMetric - summary with labels "scope", "dest" observing duration of finished checks,
+// specify objectives (e.g. 0.5, 0.99) to get latency quantiles per target.
+func WithLatencyMetric(summary *prometheus.SummaryVec) Option {
+	return func(i *Inspector) error {
+		if summary != nil {
+			if err := i.validateLabels("WithLatencyMetric", summary.MetricVec, "scope", "dest"); err != nil {
+				return err
+			}
+		}
+
+		i.latencyMetric = summary
+
+		return nil
+	}
+}
+
+// WithDurationMetric - histogram with labels "scope", "dest" (e.g. healthz_check_duration_seconds)
+// observing duration of every finished check, passed or failed, shows slow but passing targets
+// against SLO buckets. Observations of failed checks carry the trace exemplar (see WithTracer).
+func WithDurationMetric(histogram *prometheus.HistogramVec) Option {
+	return func(i *Inspector) error {
+		if histogram != nil {
+			if err := i.validateLabels("WithDurationMetric", histogram.MetricVec, "scope", "dest"); err != nil {
+				return err
+			}
+		}
+
+		i.durationHist = histogram
+
+		return nil
+	}
+}
+
+// WithQueueWaitMetric - histogram with labels "scope", "dest" (e.g. healthz_check_queue_wait_seconds)
+// observing time from the cycle start till the check started: waiting for a slot of WithMaxConcurrency
+// and WithScopeConcurrency, shows targets starved by the limits.
+func WithQueueWaitMetric(histogram *prometheus.HistogramVec) Option {
+	return func(i *Inspector) error {
+		if histogram != nil {
+			if err := i.validateLabels("WithQueueWaitMetric", histogram.MetricVec, "scope", "dest"); err != nil {
+				return err
+			}
+		}
+
+		i.queueWaitHist = histogram
+
+		return nil
+	}
+}
+
+// WithScopeMetric - gauge with label "scope" (e.g. healthz_scope_up) set every cycle to the worst
+// state among the scope targets: 1 if all of them are healthy, otherwise 0.
+func WithScopeMetric(gauge *prometheus.GaugeVec) Option {
+	return func(i *Inspector) error {
+		if gauge != nil {
+			if err := validateLabels("WithScopeMetric", gauge.MetricVec, "scope"); err != nil {
+				return err
+			}
+		}
+
+		i.scopeMetric = gauge
+
+		return nil
+	}
+}
+
+// WithTargetMetric - gauge with labels "scope", "dest", "name" (e.g. healthz_target_metric)
+// publishing values of MetricsReporter targets, values not reported anymore are deleted.
+func WithTargetMetric(gauge *prometheus.GaugeVec) Option {
+	return func(i *Inspector) error {
+		if gauge != nil {
+			if err := i.validateLabels("WithTargetMetric", gauge.MetricVec, "scope", "dest", "name"); err != nil {
+				return err
+			}
+		}
+
+		i.targetMetric = gauge
+
+		return nil
+	}
+}
+
+func outcome(err, degraded error) string {
+	switch {
+	case err == nil && degraded != nil:
+		return OutcomeDegraded
+	case err == nil:
+		return OutcomeOK
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, errCycleTimeout):
+		return OutcomeTimeout
+	default:
+		return OutcomeError
+	}
+}
+
+// updateMetric - updates metrics of the check result, a panic of the metric
+// (e.g. inconsistent label cardinality) is returned as error.
+func (i *Inspector) updateMetric(res serviceCheckResult) (metricErr error) {
+	scope, dest := res.target.Service.Scope(), res.target.Service.Dest()
+
+	defer func() {
+		if r := recover(); r != nil {
+			metricErr = fmt.Errorf("update metric of %s/%s: %v", scope, dest, r)
+		}
+
+		if metricErr != nil && i.metricErrors != nil {
+			i.metricErrors.Inc()
+		}
+	}()
+
+	if i.metric != nil {
+		healthy := 0.0
+		if res.reported == nil {
+			healthy = 1.0
+		}
+
+		i.metric.With(i.seriesLabels(res.target)).Set(healthy)
+	}
+
+	if i.outcomeMetric != nil {
+		labels := i.seriesLabels(res.target)
+		labels["outcome"] = outcome(res.err, res.degraded)
+
+		counter := i.outcomeMetric.With(labels)
+
+		if res.err != nil {
+			addWithExemplar(counter, res.exemplar)
+		} else {
+			counter.Inc()
+		}
+	}
+
+	if i.latencyMetric != nil && res.duration > 0 {
+		i.latencyMetric.With(i.seriesLabels(res.target)).Observe(res.duration.Seconds())
+	}
+
+	if i.durationHist != nil && res.duration > 0 {
+		observer := i.durationHist.With(i.seriesLabels(res.target))
+
+		if res.err != nil {
+			observeWithExemplar(observer, res.duration.Seconds(), res.exemplar)
+		} else {
+			observer.Observe(res.duration.Seconds())
+		}
+	}
+
+	if i.queueWaitHist != nil && res.duration > 0 {
+		i.queueWaitHist.With(i.seriesLabels(res.target)).Observe(res.queueWait.Seconds())
+	}
+
+	if i.targetMetric != nil && res.duration > 0 {
+		i.updateTargetMetric(res.target, res.values)
+	}
+
+	return nil
+}
+
+// updateScopeMetric - sets the worst state per scope, series of gone scopes are deleted.
+func (i *Inspector) updateScopeMetric(targets []TargetResult) (metricErr error) {
+	if i.scopeMetric == nil {
+		return nil
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			metricErr = fmt.Errorf("update scope metric: %v", r)
+		}
+
+		if metricErr != nil && i.metricErrors != nil {
+			i.metricErrors.Inc()
+		}
+	}()
+
+	up := make(map[string]bool)
+
+	for _, tr := range targets {
+		healthy, seen := up[tr.Scope]
+		up[tr.Scope] = tr.Healthy() && (healthy || !seen)
+	}
+
+	i.scopeMetricMu.Lock()
+	defer i.scopeMetricMu.Unlock()
+
+	for scope := range i.scopesSeen {
+		if _, ok := up[scope]; !ok {
+			i.scopeMetric.DeleteLabelValues(scope)
+		}
+	}
+
+	for scope, healthy := range up {
+		value := 0.0
+		if healthy {
+			value = 1.0
+		}
+
+		i.scopeMetric.WithLabelValues(scope).Set(value)
+	}
+
+	i.scopesSeen = up
+
+	return nil
+}
+
+func (i *Inspector) updateTargetMetric(target HealthCheckTarget, values map[string]float64) {
+	i.reportedMu.Lock()
+	defer i.reportedMu.Unlock()
+
+	key := targetKey(target.Service.Scope(), target.Service.Dest())
+	labels := i.seriesLabels(target)
+
+	for name := range i.reported[key] {
+		if _, ok := values[name]; !ok {
+			labels["name"] = name
+			i.targetMetric.Delete(labels)
+		}
+	}
+
+	names := make(map[string]bool, len(values))
+
+	for name, value := range values {
+		labels["name"] = name
+		i.targetMetric.With(labels).Set(value)
+		names[name] = true
+	}
+
+	if i.reported == nil {
+		i.reported = make(map[string]map[string]bool)
+	}
+
+	i.reported[key] = names
+}
+
+func (i *Inspector) hasMetrics() bool {
+	return i.metric != nil || i.outcomeMetric != nil || i.latencyMetric != nil || i.scopeMetric != nil ||
+		i.targetMetric != nil || i.durationHist != nil || i.queueWaitHist != nil
+}
+
+func metricSinkResult(errs []error) TargetResult {
+	return TargetResult{
+		Scope:  InternalScope,
+		Dest:   MetricsDest,
+		Groups: GroupCommon,
+		Err:    errors.Join(errs...),
+	}
+}
@@ -0,0 +1,88 @@
+package healthz
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var errWrongMetricLabel = errors.New("incorrect metric label")
+
+// reserved labels set by the inspector itself.
+var reservedLabels = []string{"scope", "dest", "outcome", "name"}
+
+// WithMetricLabels - extra variable labels of the per target metrics (WithMetric, WithOutcomeMetric,
+// WithLatencyMetric, WithDurationMetric, WithQueueWaitMetric, WithTargetMetric), e.g. "tenant", "region",
+// valued by HealthCheckTarget.Labels.
+// The metrics given before and after the option are validated against the extended label set.
+func WithMetricLabels(names ...string) Option {
+	return func(i *Inspector) error {
+		for n, name := range names {
+			if !validMetricName(name) || slices.Contains(reservedLabels, name) || slices.Contains(names[:n], name) {
+				return fmt.Errorf("%w: %q", errWrongMetricLabel, name)
+			}
+		}
+
+		prev := i.metricLabels
+		i.metricLabels = names
+
+		if err := i.validateTargetMetrics(); err != nil {
+			i.metricLabels = prev
+
+			return err
+		}
+
+		return nil
+	}
+}
+
+// validateTargetMetrics - validates the set per target metrics against the extended label set.
+func (i *Inspector) validateTargetMetrics() error {
+	var errs []error
+
+	if i.metric != nil {
+		errs = append(errs, i.validateLabels("WithMetric", i.metric.MetricVec, "scope", "dest"))
+	}
+
+	if i.outcomeMetric != nil {
+		errs = append(errs, i.validateLabels("WithOutcomeMetric", i.outcomeMetric.MetricVec, "scope", "dest", "outcome"))
+	}
+
+	if i.latencyMetric != nil {
+		errs = append(errs, i.validateLabels("WithLatencyMetric", i.latencyMetric.MetricVec, "scope", "dest"))
+	}
+
+	if i.durationHist != nil {
+		errs = append(errs, i.validateLabels("WithDurationMetric", i.durationHist.MetricVec, "scope", "dest"))
+	}
+
+	if i.queueWaitHist != nil {
+		errs = append(errs, i.validateLabels("WithQueueWaitMetric", i.queueWaitHist.MetricVec, "scope", "dest"))
+	}
+
+	if i.targetMetric != nil {
+		errs = append(errs, i.validateLabels("WithTargetMetric", i.targetMetric.MetricVec, "scope", "dest", "name"))
+	}
+
+	return errors.Join(errs...)
+}
+
+// validateLabels - validates the per target metric: the labels plus ones of WithMetricLabels.
+func (i *Inspector) validateLabels(option string, vec *prometheus.MetricVec, labels ...string) error {
+	return validateLabels(option, vec, append(labels, i.metricLabels...)...)
+}
+
+// seriesLabels - labels of the target series: scope, dest and extra labels (values of the target or empty).
+func (i *Inspector) seriesLabels(target HealthCheckTarget) prometheus.Labels {
+	labels := make(prometheus.Labels, 3+len(i.metricLabels))
+	labels["scope"] = target.Service.Scope()
+	labels["dest"] = target.Service.Dest()
+
+	for _, name := range i.metricLabels {
+		labels[name] = target.Labels[name]
+	}
+
+	return labels
+}
@@ -0,0 +1,60 @@
+package healthz
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithMetricLabels(t *testing.T) {
+	health := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test_labels_up"}, []string{"scope", "dest", "tenant"})
+	outcomes := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_labels_outcomes_total"}, []string{"scope", "dest", "outcome", "tenant"})
+	values := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test_labels_target_metric"}, []string{"scope", "dest", "name", "tenant"})
+
+	svc := &reporterService{mockService: mockService{scope: "db", dest: "pg"}, values: map[string]float64{"lag": 1}}
+
+	inspector := New(
+		HealthCheckTarget{Service: svc, Groups: GroupReady, Labels: map[string]string{"tenant": "acme"}},
+		HealthCheckTarget{Service: &mockService{scope: "cache", dest: "redis"}, Groups: GroupReady},
+	)
+
+	// the metric given before the labels is validated by the option of labels
+	assert.ErrorIs(t, WithMetric(health)(inspector), ErrMetricLabels)
+	assert.NoError(t, WithMetricLabels("tenant")(inspector))
+	assert.NoError(t, WithMetric(health)(inspector))
+	assert.NoError(t, WithOutcomeMetric(outcomes)(inspector))
+	assert.NoError(t, WithTargetMetric(values)(inspector))
+
+	inspector.check(context.Background())
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(health.WithLabelValues("db", "pg", "acme")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(health.WithLabelValues("cache", "redis", "")), "missing value is empty")
+	assert.Equal(t, 1.0, testutil.ToFloat64(outcomes.WithLabelValues("db", "pg", OutcomeOK, "acme")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(values.WithLabelValues("db", "pg", "lag", "acme")))
+
+	svc.values = nil
+	inspector.check(context.Background())
+	assert.Equal(t, 0, testutil.CollectAndCount(values), "value not reported anymore is deleted")
+
+	latency := prometheus.NewSummaryVec(prometheus.SummaryOpts{Name: "test_labels_latency_seconds"}, []string{"scope", "dest"})
+	assert.ErrorIs(t, WithLatencyMetric(latency)(inspector), ErrMetricLabels, "labels of the option are needed")
+
+	tests := []struct {
+		name   string
+		labels []string
+	}{
+		{name: "test.1 reserved", labels: []string{"scope"}},
+		{name: "test.2 duplicated", labels: []string{"tenant", "tenant"}},
+		{name: "test.3 invalid name", labels: []string{"tenant-id"}},
+		{name: "test.4 set metric has no label", labels: []string{"tenant", "region"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Error(t, WithMetricLabels(tt.labels...)(inspector))
+			assert.Equal(t, []string{"tenant"}, inspector.metricLabels, "labels are kept")
+		})
+	}
+}
@@ -0,0 +1,230 @@
+package healthz
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateMetric_recovery(t *testing.T) {
+	// inconsistent cardinality: WithLabelValues(scope, dest) panics
+	metric := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test_broken"}, []string{"scope", "dest", "foo"})
+	counter := prometheus.NewCounter(prometheus.CounterOpts{Name: "test_metric_errors_total"})
+
+	inspector := New(HealthCheckTarget{Service: &mockService{scope: "db", dest: "pg"}, Groups: GroupReady})
+	inspector.metric = metric
+	assert.NoError(t, WithMetricErrorsCounter(counter)(inspector))
+
+	inspector.check(context.Background())
+
+	assert.NoError(t, inspector.CheckGroup(GroupReady, true))
+	assert.Equal(t, 1.0, testutil.ToFloat64(counter))
+
+	targets := inspector.Snapshot().Targets
+	assert.Len(t, targets, 2)
+
+	sink := targets[1]
+	assert.Equal(t, InternalScope, sink.Scope)
+	assert.Equal(t, MetricsDest, sink.Dest)
+	assert.Equal(t, GroupCommon, sink.Groups)
+	assert.ErrorContains(t, sink.Err, "update metric of db/pg")
+}
+
+func TestUpdateMetric_healthySink(t *testing.T) {
+	metric := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test_ok"}, []string{"scope", "dest"})
+
+	inspector := New(HealthCheckTarget{Service: &mockService{scope: "db", dest: "pg"}, Groups: GroupReady})
+	assert.NoError(t, WithMetric(metric)(inspector))
+
+	inspector.check(context.Background())
+
+	targets := inspector.Snapshot().Targets
+	assert.Len(t, targets, 2)
+	assert.True(t, targets[1].Healthy())
+	assert.Equal(t, 1.0, testutil.ToFloat64(metric.WithLabelValues("db", "pg")))
+}
+
+func TestOutcomeAndLatencyMetrics(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	outcomes := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_outcomes_total"}, []string{"scope", "dest", "outcome"})
+	latency := prometheus.NewSummaryVec(prometheus.SummaryOpts{
+		Name:       "test_latency_seconds",
+		Objectives: map[float64]float64{0.5: 0.05, 0.99: 0.001},
+	}, []string{"scope", "dest"})
+
+	inspector := New(
+		HealthCheckTarget{Service: &mockService{scope: "db", dest: "ok"}, Groups: GroupReady},
+		HealthCheckTarget{Service: &mockService{scope: "db", dest: "fail", healthErr: errors.New("fail")}, Groups: GroupReady},
+		HealthCheckTarget{Service: &mockService{scope: "db", dest: "slow", callBack: func() { <-release }}, Groups: GroupReady},
+	)
+	assert.NoError(t, WithOutcomeMetric(outcomes)(inspector))
+	assert.NoError(t, WithLatencyMetric(latency)(inspector))
+	assert.NoError(t, WithCycleTimeout(20*time.Millisecond)(inspector))
+
+	inspector.check(context.Background())
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(outcomes.WithLabelValues("db", "ok", OutcomeOK)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(outcomes.WithLabelValues("db", "fail", OutcomeError)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(outcomes.WithLabelValues("db", "slow", OutcomeTimeout)))
+
+	// finished checks only
+	assert.Equal(t, 2, testutil.CollectAndCount(latency))
+}
+
+func TestOutcomeAndLatencyMetrics_wrongLabels(t *testing.T) {
+	outcomes := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_outcomes_total"}, []string{"scope", "dest"})
+	latency := prometheus.NewSummaryVec(prometheus.SummaryOpts{Name: "test_latency_seconds"}, []string{"dest"})
+
+	assert.ErrorIs(t, WithOutcomeMetric(outcomes)(New()), ErrMetricLabels)
+	assert.ErrorIs(t, WithLatencyMetric(latency)(New()), ErrMetricLabels)
+}
+
+func TestDurationMetric(t *testing.T) {
+	durations := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "test_check_duration_seconds",
+		Buckets: []float64{0.01, 0.1, 1},
+	}, []string{"scope", "dest"})
+
+	release := make(chan struct{})
+	defer close(release)
+
+	inspector := New(
+		HealthCheckTarget{Service: &mockService{scope: "db", dest: "ok"}, Groups: GroupReady},
+		HealthCheckTarget{Service: &mockService{scope: "db", dest: "fail", healthErr: errors.New("fail")}, Groups: GroupReady},
+		HealthCheckTarget{Service: &mockService{scope: "db", dest: "slow", callBack: func() { <-release }}, Groups: GroupReady},
+	)
+	assert.NoError(t, WithDurationMetric(durations)(inspector))
+	assert.NoError(t, WithCycleTimeout(20*time.Millisecond)(inspector))
+
+	inspector.check(context.Background())
+	inspector.check(context.Background())
+
+	// finished checks only, passed or failed
+	assert.Equal(t, 2, testutil.CollectAndCount(durations))
+
+	for _, dest := range []string{"ok", "fail"} {
+		var m dto.Metric
+
+		assert.NoError(t, durations.WithLabelValues("db", dest).(prometheus.Histogram).Write(&m))
+		assert.Equal(t, uint64(2), m.GetHistogram().GetSampleCount(), dest)
+	}
+
+	assert.True(t, inspector.RemoveTarget("db", "ok"))
+	assert.Equal(t, 1, testutil.CollectAndCount(durations), "series of removed target are deleted")
+
+	wrong := prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: "test_wrong_seconds"}, []string{"dest"})
+	assert.ErrorIs(t, WithDurationMetric(wrong)(New()), ErrMetricLabels)
+}
+
+func TestQueueWaitMetric(t *testing.T) {
+	waits := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "test_check_queue_wait_seconds",
+		Buckets: []float64{0.01, 0.1, 1},
+	}, []string{"scope", "dest"})
+
+	slow := func() { time.Sleep(30 * time.Millisecond) }
+
+	inspector := New(
+		HealthCheckTarget{Service: &mockService{scope: "db", dest: "first", callBack: slow}, Groups: GroupReady},
+		HealthCheckTarget{Service: &mockService{scope: "db", dest: "second", callBack: slow}, Groups: GroupReady},
+	)
+	assert.NoError(t, WithQueueWaitMetric(waits)(inspector))
+	assert.NoError(t, WithMaxConcurrency(1)(inspector))
+
+	inspector.check(context.Background())
+
+	assert.Equal(t, 2, testutil.CollectAndCount(waits))
+
+	sum := func(dest string) float64 {
+		var m dto.Metric
+
+		assert.NoError(t, waits.WithLabelValues("db", dest).(prometheus.Histogram).Write(&m))
+		assert.Equal(t, uint64(1), m.GetHistogram().GetSampleCount(), dest)
+
+		return m.GetHistogram().GetSampleSum()
+	}
+
+	assert.Less(t, sum("first"), 0.01)
+	assert.GreaterOrEqual(t, sum("second"), 0.03, "waited for the first check")
+
+	assert.True(t, inspector.RemoveTarget("db", "first"))
+	assert.Equal(t, 1, testutil.CollectAndCount(waits), "series of removed target are deleted")
+
+	wrong := prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: "test_wrong_wait_seconds"}, []string{"dest"})
+	assert.ErrorIs(t, WithQueueWaitMetric(wrong)(New()), ErrMetricLabels)
+}
+
+func TestScopeMetric(t *testing.T) {
+	scopeUp := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test_scope_up"}, []string{"scope"})
+
+	pg := &mockService{scope: "db", dest: "pg"}
+	replica := &mockService{scope: "db", dest: "replica", healthErr: errors.New("fail")}
+	redis := &mockService{scope: "cache", dest: "redis"}
+
+	inspector := New(
+		HealthCheckTarget{Service: pg, Groups: GroupReady},
+		HealthCheckTarget{Service: replica, Groups: GroupReady},
+		HealthCheckTarget{Service: redis, Groups: GroupLive},
+	)
+	assert.NoError(t, WithScopeMetric(scopeUp)(inspector))
+
+	inspector.check(context.Background())
+
+	assert.Equal(t, 0.0, testutil.ToFloat64(scopeUp.WithLabelValues("db")), "worst state of the scope")
+	assert.Equal(t, 1.0, testutil.ToFloat64(scopeUp.WithLabelValues("cache")))
+
+	inspector.targets = inspector.targets[:2]
+	replica.healthErr = nil
+
+	inspector.check(context.Background())
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(scopeUp.WithLabelValues("db")))
+	assert.Equal(t, 1, testutil.CollectAndCount(scopeUp), "series of the gone scope is deleted")
+
+	assert.ErrorIs(t, WithScopeMetric(prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test_scope_up"}, []string{"scope", "dest"}))(New()), ErrMetricLabels)
+}
+
+// reporterService - checker exporting own values.
+type reporterService struct {
+	mockService
+	values map[string]float64
+}
+
+func (s *reporterService) HealthMetrics() map[string]float64 { return s.values }
+
+func TestTargetMetric(t *testing.T) {
+	values := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test_target_metric"}, []string{"scope", "dest", "name"})
+
+	svc := &reporterService{
+		mockService: mockService{scope: "db", dest: "replica"},
+		values:      map[string]float64{"replication_lag_seconds": 1.5, "pool_in_use": 3},
+	}
+
+	inspector := New(
+		HealthCheckTarget{Service: svc, Groups: GroupReady},
+		HealthCheckTarget{Service: &mockService{scope: "cache", dest: "redis"}, Groups: GroupReady},
+	)
+	assert.NoError(t, WithTargetMetric(values)(inspector))
+
+	inspector.check(context.Background())
+
+	assert.Equal(t, 2, testutil.CollectAndCount(values))
+	assert.Equal(t, 1.5, testutil.ToFloat64(values.WithLabelValues("db", "replica", "replication_lag_seconds")))
+
+	svc.values = map[string]float64{"replication_lag_seconds": 0.5}
+
+	inspector.check(context.Background())
+
+	assert.Equal(t, 1, testutil.CollectAndCount(values), "value not reported anymore is deleted")
+	assert.Equal(t, 0.5, testutil.ToFloat64(values.WithLabelValues("db", "replica", "replication_lag_seconds")))
+
+	assert.ErrorIs(t, WithTargetMetric(prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test_target_metric"}, []string{"scope", "dest"}))(New()), ErrMetricLabels)
+}
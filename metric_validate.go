@@ -2,25 +2,99 @@ package healthz
 
 import (
 	"errors"
-	"regexp"
+	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/prometheus/client_golang/prometheus"
 )
 
+// Causes of MetricError.
 var (
-	reLabel       = regexp.MustCompile(`(?m)variableLabels: {scope,dest}`)
-	errMissLabels = errors.New("unexpected labels, need scope,dest")
+	ErrMetricLabels   = errors.New("unexpected labels")
+	ErrMetricConflict = errors.New("metric registration conflict")
 )
 
-// validateMetricLabels checks that the metric has label "scope" and "dest".
-func validateMetricLabels(metric *prometheus.GaugeVec) error {
+// MetricError - metric given to an option or registered by the inspector is unusable,
+// Err is ErrMetricLabels or ErrMetricConflict (wrapping the prometheus error,
+// e.g. prometheus.AlreadyRegisteredError).
+type MetricError struct {
+	Metric string   // option of the given metric or name of the registered one
+	Labels []string // labels the inspector needs
+	Err    error
+}
+
+func (e *MetricError) Error() string {
+	if len(e.Labels) == 0 {
+		return fmt.Sprintf("%s: %v", e.Metric, e.Err)
+	}
+
+	return fmt.Sprintf("%s: %v, need %s", e.Metric, e.Err, strings.Join(e.Labels, ","))
+}
+
+func (e *MetricError) Unwrap() error {
+	return e.Err
+}
+
+// validateLabels - checks that the variable labels of the vector are exactly the labels (in any order),
+// by its description: no series is created on the vector, it could be already served.
+func validateLabels(option string, vec *prometheus.MetricVec, labels ...string) error {
+	have := variableLabels(vec)
+
+	if len(have) != len(labels) || slices.ContainsFunc(labels, func(label string) bool {
+		return !slices.Contains(have, label)
+	}) {
+		return &MetricError{Metric: option, Labels: labels, Err: fmt.Errorf("%w: has %s", ErrMetricLabels, strings.Join(have, ","))}
+	}
+
+	return nil
+}
+
+// variableLabels - names of the variable labels of the vector, parsed from its description
+// ("Desc{..., variableLabels: {scope,c(dest)}}", constrained labels are wrapped by "c()").
+func variableLabels(vec *prometheus.MetricVec) []string {
 	ch := make(chan *prometheus.Desc, 1)
-	metric.Describe(ch)
-	desc := <-ch
+	vec.Describe(ch)
+
+	desc := (<-ch).String()
 
-	if reLabel.MatchString(desc.String()) {
+	const prefix = "variableLabels: {"
+
+	start := strings.LastIndex(desc, prefix)
+	if start < 0 {
+		return nil
+	}
+
+	list := strings.TrimSuffix(desc[start+len(prefix):], "}}")
+	if list == "" {
 		return nil
 	}
 
-	return errMissLabels
+	names := strings.Split(list, ",")
+	for n, name := range names {
+		names[n] = strings.TrimSuffix(strings.TrimPrefix(name, "c("), ")")
+	}
+
+	return names
+}
+
+// namedCollector - collector created by the inspector, name is fully-qualified.
+type namedCollector struct {
+	name string
+	prometheus.Collector
+}
+
+// register - registers the collectors, on failure the registered ones are unregistered.
+func register(reg prometheus.Registerer, collectors ...namedCollector) error {
+	for n, c := range collectors {
+		if err := reg.Register(c.Collector); err != nil {
+			for _, registered := range collectors[:n] {
+				reg.Unregister(registered.Collector)
+			}
+
+			return &MetricError{Metric: c.name, Err: fmt.Errorf("%w: %w", ErrMetricConflict, err)}
+		}
+	}
+
+	return nil
 }
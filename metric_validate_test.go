@@ -1,14 +1,46 @@
 package healthz
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 )
 
-func Test_validateMetricLabels(t *testing.T) {
+func Test_validateLabels_live(t *testing.T) {
+	up := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test_live_up"}, []string{"scope", "dest"})
+	up.WithLabelValues("db", "pg").Set(1)
+
+	ch := make(chan prometheus.Metric, 10)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		for range 1000 {
+			assert.NoError(t, validateLabels("WithMetric", up.MetricVec, "scope", "dest"))
+		}
+	}()
+
+	for {
+		select {
+		case <-done:
+			assert.Equal(t, 1, testutil.CollectAndCount(up))
+
+			return
+		default:
+			up.Collect(ch)
+			assert.Len(t, ch, 1, "scrapes see no probe series")
+
+			<-ch
+		}
+	}
+}
+
+func Test_validateLabels(t *testing.T) {
 	m1 := promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: "test_m1",
@@ -39,15 +71,42 @@ func Test_validateMetricLabels(t *testing.T) {
 			metric:  m2,
 			wantErr: true,
 		},
+		{
+			name:    "test.3 ok other order",
+			metric:  prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test_m3", Help: "x variableLabels: {a}"}, []string{"dest", "scope"}),
+			wantErr: false,
+		},
+		{
+			name:    "test.4 err fewer labels",
+			metric:  prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test_m4"}, []string{"scope"}),
+			wantErr: true,
+		},
+		{
+			name: "test.5 ok constrained labels",
+			metric: prometheus.V2.NewGaugeVec(prometheus.GaugeVecOpts{
+				GaugeOpts: prometheus.GaugeOpts{Name: "test_m5"},
+				VariableLabels: prometheus.ConstrainedLabels{
+					{Name: "scope"},
+					{Name: "dest", Constraint: strings.ToLower},
+				},
+			}),
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := validateMetricLabels(tt.metric)
+			err := validateLabels("WithMetric", tt.metric.MetricVec, "scope", "dest")
 			if tt.wantErr {
-				assert.Error(t, err)
+				var metricErr *MetricError
+
+				assert.ErrorIs(t, err, ErrMetricLabels)
+				assert.ErrorAs(t, err, &metricErr)
+				assert.Equal(t, []string{"scope", "dest"}, metricErr.Labels)
 			} else {
 				assert.NoError(t, err)
 			}
+
+			assert.Equal(t, 0, testutil.CollectAndCount(tt.metric), "no series is created")
 		})
 	}
 }
@@ -0,0 +1,83 @@
+package healthz
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// HeaderRefreshIn - seconds until a probe may trigger a fresh check in on-demand mode.
+const HeaderRefreshIn = "X-Healthz-Refresh-In-Seconds"
+
+var errWrongOnDemand = errors.New("incorrect on-demand check interval")
+
+// onDemand - settings of the on-demand mode, see WithOnDemandChecks.
+type onDemand struct {
+	interval time.Duration
+	flight   singleflight.Group // probes arriving during a check wait for it
+}
+
+// WithOnDemandChecks - checks are triggered by probes (HealthHandler) instead of the periodic cycles, but
+// not more often than once per interval regardless of probe frequency, cached results are served in between.
+// Freshness of the result is exposed by HeaderCheckedAt, HeaderAge and HeaderRefreshIn.
+func WithOnDemandChecks(interval time.Duration) Option {
+	return func(i *Inspector) error {
+		if interval <= 0 {
+			return errWrongOnDemand
+		}
+
+		i.onDemand = &onDemand{interval: interval}
+
+		return nil
+	}
+}
+
+// refreshIn - time until the next on-demand check is allowed, zero if it is allowed now.
+func (i *Inspector) refreshIn() time.Duration {
+	last := i.lastCycle.Load()
+	if last == 0 {
+		return 0
+	}
+
+	return max(i.onDemand.interval-time.Since(unixNanoTime(last)), 0)
+}
+
+// refreshOnDemand - checks the targets if the cached result is older than the interval,
+// concurrent probes share one check.
+func (i *Inspector) refreshOnDemand(ctx context.Context) {
+	if i.parent != nil {
+		i.parent.refreshOnDemand(ctx)
+
+		return
+	}
+
+	if i.onDemand == nil || i.shuttingDown.Load() || i.refreshIn() > 0 {
+		return
+	}
+
+	_, _, _ = i.onDemand.flight.Do("check", func() (any, error) {
+		if i.refreshIn() == 0 { // another probe could just finish the check
+			i.check(context.WithoutCancel(ctx)) // shared by the probes, the caller mustn't cancel it
+		}
+
+		return nil, nil
+	})
+}
+
+// writeRefreshIn - sets HeaderRefreshIn in on-demand mode.
+func (i *Inspector) writeRefreshIn(w http.ResponseWriter) {
+	root := i
+	if i.parent != nil {
+		root = i.parent
+	}
+
+	if root.onDemand == nil {
+		return
+	}
+
+	w.Header().Set(HeaderRefreshIn, strconv.FormatFloat(root.refreshIn().Seconds(), 'f', 3, 64))
+}
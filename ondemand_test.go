@@ -0,0 +1,80 @@
+package healthz
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithOnDemandChecks(t *testing.T) {
+	assert.ErrorIs(t, WithOnDemandChecks(0)(New()), errWrongOnDemand)
+
+	var calls atomic.Int32
+
+	inspector := New(HealthCheckTarget{
+		Service: &mockService{scope: "db", dest: "pg", callBack: func() {
+			calls.Add(1)
+			time.Sleep(10 * time.Millisecond)
+		}},
+		Groups: GroupReady,
+	})
+	assert.NoError(t, WithOnDemandChecks(time.Hour)(inspector))
+	assert.NoError(t, inspector.Start(context.Background()))
+
+	defer inspector.Stop(context.Background())
+
+	assert.Zero(t, calls.Load(), "no periodic checks")
+
+	handler := inspector.HealthHandler(GroupReady, true, nil)
+
+	var wg sync.WaitGroup
+
+	for range 10 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			w := httptest.NewRecorder()
+			handler(w, httptest.NewRequest(http.MethodGet, "/healthz/ready", nil))
+
+			assert.Equal(t, http.StatusOK, w.Code)
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls.Load(), "concurrent probes share one check")
+
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest(http.MethodGet, "/healthz/ready", nil))
+
+	assert.Equal(t, int32(1), calls.Load(), "cached result is served within the interval")
+	assert.NotEmpty(t, w.Header().Get(HeaderCheckedAt))
+	assert.NotEmpty(t, w.Header().Get(HeaderAge))
+	assert.NotEqual(t, "0.000", w.Header().Get(HeaderRefreshIn))
+
+	schedule := inspector.Schedule()
+	if assert.Len(t, schedule, 1) {
+		assert.Equal(t, ScheduleOnDemand, schedule[0].Reason)
+		assert.Equal(t, time.Hour, schedule[0].Period)
+	}
+
+	inspector.lastCycle.Store(time.Now().Add(-2 * time.Hour).UnixNano()) // the interval passed
+
+	child, err := inspector.Child("db")
+	assert.NoError(t, err)
+
+	w = httptest.NewRecorder()
+	child.HealthHandler(GroupReady, true, nil)(w, httptest.NewRequest(http.MethodGet, "/healthz/db/ready", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, int32(2), calls.Load(), "probes of a child trigger checks of the parent")
+	assert.NotEmpty(t, w.Header().Get(HeaderRefreshIn))
+}
@@ -0,0 +1,137 @@
+package healthz
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	errInvalidated     = errors.New("result invalidated, waiting for the next check")
+	errMarkedUnhealthy = errors.New("marked unhealthy by application")
+)
+
+// override - result of the target forced by application till the time.
+type override struct {
+	err   error
+	until time.Time
+}
+
+// Invalidate - marks the cached result of the target stale, so until the next check cycle
+// the target is unhealthy. Reports whether the target was found.
+func (i *Inspector) Invalidate(scope, dest string) bool {
+	if i.parent != nil {
+		return scope == i.scope && i.parent.Invalidate(scope, dest)
+	}
+
+	i.changesMu.Lock()
+	defer i.changesMu.Unlock()
+
+	cur := i.get()
+	if !cur.checked {
+		return false
+	}
+
+	next := cur.clone()
+	found := false
+
+	for n, tr := range next.targets {
+		if tr.Scope == scope && tr.Dest == dest {
+			next.targets[n].Err = errInvalidated
+			next.targets[n].groupErrs = nil
+			found = true
+		}
+	}
+
+	if found {
+		i.storeLocked(cur, next)
+	}
+
+	return found
+}
+
+// MarkUnhealthy - forces the target unhealthy for ttl regardless of check results,
+// for hot-path code that just observed a hard dependency failure. err could be nil, the target must be registered.
+// Child marks only targets of its scope, on the parent.
+func (i *Inspector) MarkUnhealthy(scope, dest string, err error, ttl time.Duration) error {
+	if i.parent != nil {
+		if scope != i.scope {
+			return fmt.Errorf("%w: %s", errForeignScope, scope)
+		}
+
+		return i.parent.MarkUnhealthy(scope, dest, err, ttl)
+	}
+
+	if ttl <= 0 {
+		return errWrongTTL
+	}
+
+	if !i.registeredTarget(scope, dest) {
+		return fmt.Errorf("%w: %s", errUnregisteredTarget, targetKey(scope, dest))
+	}
+
+	if err == nil {
+		err = errMarkedUnhealthy
+	}
+
+	i.overridesMu.Lock()
+	defer i.overridesMu.Unlock()
+
+	if i.overrides == nil {
+		i.overrides = make(map[string]override)
+	}
+
+	i.overrides[targetKey(scope, dest)] = override{err: err, until: time.Now().Add(ttl)}
+
+	return nil
+}
+
+// result - the last check results with applied overrides.
+func (i *Inspector) result() *healthResult {
+	res := i.get()
+
+	i.overridesMu.RLock()
+	defer i.overridesMu.RUnlock()
+
+	if len(i.overrides) == 0 {
+		return res
+	}
+
+	now := time.Now()
+
+	var next *healthResult
+
+	for n, tr := range res.targets {
+		o, ok := i.overrides[targetKey(tr.Scope, tr.Dest)]
+		if !ok || now.After(o.until) {
+			continue
+		}
+
+		if next == nil {
+			next = res.clone()
+		}
+
+		next.targets[n].Err = o.err
+		next.targets[n].groupErrs = nil
+	}
+
+	if next == nil {
+		return res
+	}
+
+	return next
+}
+
+// pruneOverrides - drops expired overrides.
+func (i *Inspector) pruneOverrides() {
+	i.overridesMu.Lock()
+	defer i.overridesMu.Unlock()
+
+	now := time.Now()
+
+	for key, o := range i.overrides {
+		if now.After(o.until) {
+			delete(i.overrides, key)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package healthz
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInvalidate(t *testing.T) {
+	inspector := New(
+		HealthCheckTarget{Service: &mockService{scope: "db", dest: "pg"}, Groups: GroupReady},
+		HealthCheckTarget{Service: &mockService{scope: "cache", dest: "redis"}, Groups: GroupLive},
+	)
+
+	assert.False(t, inspector.Invalidate("db", "pg"), "nothing to invalidate before the first check")
+
+	inspector.check(context.Background())
+	assert.NoError(t, inspector.CheckGroup(GroupReady, true))
+
+	assert.False(t, inspector.Invalidate("db", "unknown"))
+	assert.True(t, inspector.Invalidate("db", "pg"))
+
+	assert.ErrorIs(t, inspector.CheckGroup(GroupReady, true), errInvalidated)
+	assert.NoError(t, inspector.CheckGroup(GroupLive, true))
+	assert.Len(t, inspector.Changes(time.Time{}), 1)
+
+	inspector.check(context.Background())
+	assert.NoError(t, inspector.CheckGroup(GroupReady, true))
+}
+
+func TestMarkUnhealthy(t *testing.T) {
+	inspector := New(
+		HealthCheckTarget{Service: &mockService{scope: "db", dest: "pg"}, Groups: GroupReady},
+		HealthCheckTarget{Service: &mockService{scope: "cache", dest: "redis"}, Groups: GroupReady},
+	)
+	inspector.check(context.Background())
+
+	assert.ErrorIs(t, inspector.MarkUnhealthy("db", "pg", nil, 0), errWrongTTL)
+	assert.ErrorIs(t, inspector.MarkUnhealthy("db", "mysql", nil, time.Minute), errUnregisteredTarget)
+	assert.Empty(t, inspector.overrides, "typo doesn't leak an override")
+
+	errReset := errors.New("connection reset")
+	assert.NoError(t, inspector.MarkUnhealthy("db", "pg", errReset, 30*time.Millisecond))
+
+	assert.ErrorIs(t, inspector.CheckGroup(GroupReady, true), errReset)
+	assert.NoError(t, inspector.CheckGroup(GroupReady, false), "redis is healthy")
+	assert.ErrorIs(t, inspector.Snapshot().Targets[0].Err, errReset)
+
+	// holds over check cycles till ttl
+	inspector.check(context.Background())
+	assert.ErrorIs(t, inspector.CheckGroup(GroupReady, true), errReset)
+
+	time.Sleep(40 * time.Millisecond)
+	assert.NoError(t, inspector.CheckGroup(GroupReady, true))
+
+	inspector.check(context.Background())
+	assert.Empty(t, inspector.overrides)
+
+	assert.NoError(t, inspector.MarkUnhealthy("db", "pg", nil, time.Minute))
+	assert.ErrorIs(t, inspector.CheckGroup(GroupReady, true), errMarkedUnhealthy)
+}
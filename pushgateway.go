@@ -0,0 +1,86 @@
+package healthz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/push"
+)
+
+const defPushTimeout = 5 * time.Second
+
+var errMissPushgateway = errors.New("pushgateway must have url and job")
+
+// pushgateway - settings of the Pushgateway export, see WithPushgateway.
+type pushgateway struct {
+	url      string
+	job      string
+	grouping map[string]string
+	gatherer prometheus.Gatherer
+	client   *http.Client
+}
+
+// WithPushgateway - pushes metrics of the gatherer (prometheus.DefaultGatherer if nil) to the Prometheus
+// Pushgateway at url under the job and grouping key after every check cycle and on Stop, so cron jobs
+// and short-lived processes leave the final health record behind. Every push replaces metrics of the group.
+// Failed pushes of the cycles are logged, the one of Stop is returned.
+func WithPushgateway(url, job string, grouping map[string]string, gatherer prometheus.Gatherer) Option {
+	return func(i *Inspector) error {
+		if url == "" || job == "" {
+			return errMissPushgateway
+		}
+
+		if gatherer == nil {
+			gatherer = prometheus.DefaultGatherer
+		}
+
+		i.pushgateway = &pushgateway{
+			url:      url,
+			job:      job,
+			grouping: grouping,
+			gatherer: gatherer,
+			client:   &http.Client{Timeout: defPushTimeout},
+		}
+
+		return nil
+	}
+}
+
+// push - pushes the metrics to the Pushgateway.
+func (p *pushgateway) push(ctx context.Context) error {
+	pusher := push.New(p.url, p.job).Gatherer(p.gatherer).Client(p.client)
+
+	for name, value := range p.grouping {
+		pusher = pusher.Grouping(name, value)
+	}
+
+	if err := pusher.PushContext(ctx); err != nil {
+		return fmt.Errorf("push to %s: %w", p.url, err)
+	}
+
+	return nil
+}
+
+// pushMetrics - pushes the metrics to the Pushgateway if set.
+func (i *Inspector) pushMetrics(ctx context.Context) {
+	if i.pushgateway == nil {
+		return
+	}
+
+	if err := i.pushgateway.push(ctx); err != nil {
+		i.log().Warn("healthz: metrics not pushed", "job", i.pushgateway.job, "error", err)
+	}
+}
+
+// pushFinal - pushes the metrics on Stop if the Pushgateway is set.
+func (i *Inspector) pushFinal(ctx context.Context) error {
+	if i.pushgateway == nil {
+		return nil
+	}
+
+	return i.pushgateway.push(ctx)
+}
@@ -0,0 +1,94 @@
+package healthz
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithPushgateway(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		job     string
+		wantErr bool
+	}{
+		{name: "test.1 ok", url: "http://pushgateway:9091", job: "backup"},
+		{name: "test.2 no url", job: "backup", wantErr: true},
+		{name: "test.3 no job", url: "http://pushgateway:9091", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inspector := New()
+			err := WithPushgateway(tt.url, tt.job, nil, nil)(inspector)
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, errMissPushgateway)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, prometheus.DefaultGatherer, inspector.pushgateway.gatherer)
+		})
+	}
+}
+
+func TestPushgateway_push(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		paths  []string
+		bodies []string
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		mu.Lock()
+		paths = append(paths, r.Method+" "+r.URL.Path)
+		bodies = append(bodies, string(body))
+		mu.Unlock()
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	reg := prometheus.NewRegistry()
+	up := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "service_up", Help: "Health of the dependency."}, []string{"scope", "dest"})
+	reg.MustRegister(up)
+
+	svc := &mockService{scope: "db", dest: "pg"}
+
+	inspector := New(HealthCheckTarget{Service: svc, Groups: GroupReady})
+	assert.NoError(t, WithMetric(up)(inspector))
+	assert.NoError(t, WithPushgateway(srv.URL, "backup", map[string]string{"instance": "node-1"}, reg)(inspector))
+
+	inspector.check(context.Background())
+
+	svc.healthErr = errors.New("down")
+	inspector.check(context.Background())
+
+	assert.NoError(t, inspector.Stop(context.Background()))
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	assert.Equal(t, []string{
+		"PUT /metrics/job/backup/instance/node-1",
+		"PUT /metrics/job/backup/instance/node-1",
+		"PUT /metrics/job/backup/instance/node-1",
+	}, paths, "pushed by every cycle and on Stop")
+	assert.NotEmpty(t, bodies[0])
+	assert.NotEqual(t, bodies[0], bodies[1], "the latest state is pushed")
+
+	srv.Close()
+	assert.Error(t, inspector.Stop(context.Background()), "failed final push is returned")
+}
@@ -0,0 +1,109 @@
+package healthz
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+var errNoQuorum = errors.New("not enough healthy targets")
+
+// GroupPolicy - how many targets of the probe group must be healthy for the group to pass.
+type GroupPolicy struct {
+	quorum int // healthy targets required, all if zero
+}
+
+var (
+	PolicyAll = GroupPolicy{}          // all targets healthy, CheckGroup with needAllHealthy
+	PolicyAny = GroupPolicy{quorum: 1} // any target healthy, CheckGroup without needAllHealthy
+)
+
+// Quorum - at least n targets healthy, e.g. Quorum(2) for a group of three cache replicas.
+// Values below 1 are treated as 1.
+func Quorum(n int) GroupPolicy {
+	return GroupPolicy{quorum: max(n, 1)}
+}
+
+// policyOf - policy of the needAllHealthy flag.
+func policyOf(needAllHealthy bool) GroupPolicy {
+	if needAllHealthy {
+		return PolicyAll
+	}
+
+	return PolicyAny
+}
+
+func (p GroupPolicy) String() string {
+	switch p {
+	case PolicyAll:
+		return "all"
+	case PolicyAny:
+		return "any"
+	}
+
+	return fmt.Sprintf("quorum(%d)", p.quorum)
+}
+
+// verdict - health of the group with the check results of its targets.
+func (p GroupPolicy) verdict(list []error) error {
+	switch p {
+	case PolicyAll:
+		return accureError(list)
+	case PolicyAny:
+		return accureNoError(list)
+	}
+
+	var healthy int
+
+	for _, err := range list {
+		if err == nil {
+			healthy++
+		}
+	}
+
+	if healthy >= p.quorum {
+		return nil
+	}
+
+	return errors.Join(append([]error{fmt.Errorf("%w: %d of %d, need %d", errNoQuorum, healthy, len(list), p.quorum)}, list...)...)
+}
+
+// CheckGroupPolicy - CheckGroup with the policy beyond all or any targets healthy, e.g. Quorum(2).
+func (i *Inspector) CheckGroupPolicy(group ProbeGroup, policy GroupPolicy) error {
+	if group&GroupReady != 0 && i.shuttingDown.Load() {
+		return errShuttingDown
+	}
+
+	res := i.result()
+
+	return i.checkForced(group, func(group ProbeGroup) error {
+		return i.checkLatched(group, func(group ProbeGroup) error {
+			return i.debouncedHealth(res, group, policy)
+		})
+	})
+}
+
+// HealthHandlerPolicy - HealthHandler with the policy beyond all or any targets healthy, e.g. Quorum(2).
+func (i *Inspector) HealthHandlerPolicy(group ProbeGroup, policy GroupPolicy, toResponse func(error) []byte) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		group, err := i.headerGroup(r, group)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+
+			return
+		}
+
+		i.refreshRequested(r)
+		i.refreshOnDemand(r.Context())
+
+		err = i.CheckGroupPolicy(group, policy)
+
+		strategy := i.response
+		if toResponse != nil {
+			strategy.Formatter = Formatter{Format: func(pr ProbeReport) []byte { return toResponse(pr.Err) }}
+		}
+
+		i.writeRefreshIn(w)
+		strategy.Write(w, r, i.redactReport(i.probeReport(group, err)))
+	}
+}
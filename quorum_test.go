@@ -0,0 +1,91 @@
+package healthz
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupPolicy_verdict(t *testing.T) {
+	errDown := errors.New("down")
+
+	tests := []struct {
+		name    string
+		policy  GroupPolicy
+		list    []error
+		wantErr bool
+	}{
+		{name: "test.1 all passed", policy: PolicyAll, list: []error{nil, nil, nil}},
+		{name: "test.2 all failed", policy: PolicyAll, list: []error{nil, errDown, nil}, wantErr: true},
+		{name: "test.3 any passed", policy: PolicyAny, list: []error{errDown, nil, errDown}},
+		{name: "test.4 any failed", policy: PolicyAny, list: []error{errDown, errDown}, wantErr: true},
+		{name: "test.5 quorum passed", policy: Quorum(2), list: []error{nil, errDown, nil}},
+		{name: "test.6 quorum failed", policy: Quorum(2), list: []error{errDown, errDown, nil}, wantErr: true},
+		{name: "test.7 quorum above targets", policy: Quorum(4), list: []error{nil, nil, nil}, wantErr: true},
+		{name: "test.8 quorum below 1", policy: Quorum(0), list: []error{errDown, nil}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.verdict(tt.list)
+
+			if !tt.wantErr {
+				assert.NoError(t, err)
+
+				return
+			}
+
+			assert.Error(t, err)
+
+			if tt.policy != PolicyAll && tt.policy != PolicyAny {
+				assert.ErrorIs(t, err, errNoQuorum)
+			}
+		})
+	}
+
+	assert.Equal(t, "all", PolicyAll.String())
+	assert.Equal(t, "any", Quorum(1).String())
+	assert.Equal(t, "quorum(2)", Quorum(2).String())
+}
+
+func TestInspector_CheckGroupPolicy(t *testing.T) {
+	replicas := []*mockService{
+		{scope: "cache", dest: "redis-1"},
+		{scope: "cache", dest: "redis-2"},
+		{scope: "cache", dest: "redis-3"},
+	}
+
+	targets := make([]HealthCheckTarget, 0, len(replicas))
+	for _, svc := range replicas {
+		targets = append(targets, HealthCheckTarget{Service: svc, Groups: GroupReady})
+	}
+
+	inspector := New(targets...)
+
+	replicas[0].healthErr = errors.New("down")
+	inspector.check(context.Background())
+
+	assert.NoError(t, inspector.CheckGroupPolicy(GroupReady, Quorum(2)))
+	assert.Error(t, inspector.CheckGroupPolicy(GroupReady, PolicyAll))
+	assert.Error(t, inspector.CheckGroup(GroupReady, true), "needAllHealthy is PolicyAll")
+
+	handler := inspector.HealthHandlerPolicy(GroupReady, Quorum(2), nil)
+
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest(http.MethodGet, "/healthz/ready", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	replicas[1].healthErr = errors.New("down")
+	inspector.check(context.Background())
+
+	assert.ErrorIs(t, inspector.CheckGroupPolicy(GroupReady, Quorum(2)), errNoQuorum)
+	assert.NoError(t, inspector.CheckGroup(GroupReady, false), "any target healthy")
+
+	w = httptest.NewRecorder()
+	handler(w, httptest.NewRequest(http.MethodGet, "/healthz/ready", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
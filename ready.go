@@ -0,0 +1,59 @@
+package healthz
+
+import (
+	"errors"
+	"fmt"
+)
+
+var errForcedNotReady = errors.New("forced not ready")
+
+// forcedReadiness - readiness set by SetReady or SetNotReady.
+type forcedReadiness struct {
+	ready  bool
+	reason string
+}
+
+// SetNotReady - forces the ready group unhealthy regardless of check results (e.g. deploy hooks,
+// incident response) until SetReady or ResetReady, the reason is reported by the probe.
+// Readiness forced on the parent applies to children which don't force their own.
+func (i *Inspector) SetNotReady(reason string) {
+	i.forced.Store(&forcedReadiness{reason: reason})
+}
+
+// SetReady - forces the ready group healthy regardless of check results until SetNotReady or ResetReady,
+// shutting down (see BeginShutdown) fails it anyway.
+func (i *Inspector) SetReady() {
+	i.forced.Store(&forcedReadiness{ready: true})
+}
+
+// ResetReady - the ready group is evaluated by check results again.
+func (i *Inspector) ResetReady() {
+	i.forced.Store(nil)
+}
+
+// forcedReady - readiness forced on the inspector or its parent, nil if not forced.
+func (i *Inspector) forcedReady() *forcedReadiness {
+	if f := i.forced.Load(); f != nil || i.parent == nil {
+		return f
+	}
+
+	return i.parent.forced.Load()
+}
+
+// checkForced - verdict of the group with the forced readiness applied.
+func (i *Inspector) checkForced(group ProbeGroup, check func(ProbeGroup) error) error {
+	f := i.forcedReady()
+	if f == nil || group&GroupReady == 0 {
+		return check(group)
+	}
+
+	if !f.ready {
+		return fmt.Errorf("%w: %s", errForcedNotReady, f.reason)
+	}
+
+	if group &^= GroupReady; group == 0 {
+		return nil
+	}
+
+	return check(group)
+}
@@ -0,0 +1,42 @@
+package healthz
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetReady(t *testing.T) {
+	db := &mockService{scope: "db", dest: "pg"}
+	inspector := New(HealthCheckTarget{Service: db, Groups: GroupLive | GroupReady})
+	child, err := inspector.Child("db")
+	assert.NoError(t, err)
+
+	inspector.check(context.Background())
+	assert.NoError(t, inspector.CheckGroup(GroupReady, true))
+
+	inspector.SetNotReady("deploy")
+	assert.ErrorIs(t, inspector.CheckGroup(GroupReady, true), errForcedNotReady)
+	assert.ErrorContains(t, inspector.CheckGroup(GroupReady, true), "deploy")
+	assert.ErrorIs(t, child.CheckGroup(GroupReady, true), errForcedNotReady, "forced on the parent")
+	assert.NoError(t, inspector.CheckGroup(GroupLive, true), "live isn't forced")
+
+	child.SetReady()
+	assert.NoError(t, child.CheckGroup(GroupReady, true), "child forces its own")
+
+	inspector.ResetReady()
+	child.ResetReady()
+	assert.NoError(t, inspector.CheckGroup(GroupReady, true))
+
+	db.healthErr = errors.New("fail")
+	inspector.check(context.Background())
+
+	inspector.SetReady()
+	assert.NoError(t, inspector.CheckGroup(GroupReady, true))
+	assert.Error(t, inspector.CheckGroup(GroupLive|GroupReady, true), "other groups of the mask are evaluated")
+
+	assert.NoError(t, inspector.BeginShutdown(context.Background()))
+	assert.ErrorIs(t, inspector.CheckGroup(GroupReady, true), errShuttingDown, "shutting down wins")
+}
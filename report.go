@@ -0,0 +1,153 @@
+package healthz
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// TargetAvailability - availability of the target within a window, computed from history.
+type TargetAvailability struct {
+	Scope         string
+	Dest          string
+	Availability  float64       // percent of time healthy
+	Outages       int           // count of unhealthy periods
+	LongestOutage time.Duration // including the ongoing one
+	MTTR          time.Duration // mean time to recovery of finished outages
+}
+
+func (ta TargetAvailability) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Scope                string  `json:"scope"`
+		Dest                 string  `json:"dest"`
+		Availability         float64 `json:"availability"`
+		Outages              int     `json:"outages"`
+		LongestOutageSeconds float64 `json:"longestOutageSeconds"`
+		MTTRSeconds          float64 `json:"mttrSeconds"`
+	}{
+		Scope:                ta.Scope,
+		Dest:                 ta.Dest,
+		Availability:         ta.Availability,
+		Outages:              ta.Outages,
+		LongestOutageSeconds: ta.LongestOutage.Seconds(),
+		MTTRSeconds:          ta.MTTR.Seconds(),
+	})
+}
+
+// Availability - per target availability within the window till now, sorted by scope and dest.
+func (i *Inspector) Availability(window time.Duration) ([]TargetAvailability, error) {
+	to := time.Now()
+
+	entries, err := i.History(to.Add(-window), to)
+	if err != nil {
+		return nil, err
+	}
+
+	return availability(entries, to), nil
+}
+
+// ReportHandler - serves availability report as JSON (or by a negotiated codec), window is set by query param "window", default 24h.
+func (i *Inspector) ReportHandler() http.Handler {
+	return Gzip(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		from, to, err := parseTimeRange(r, 24*time.Hour)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+
+			return
+		}
+
+		entries, err := i.History(from, to)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+
+			return
+		}
+
+		writeEncoded(w, r, http.StatusOK, availability(entries, to))
+	}))
+}
+
+// availability - every entry describes state of the target until its next entry (or till to).
+func availability(entries []HistoryEntry, to time.Time) []TargetAvailability {
+	byTarget := make(map[string][]HistoryEntry)
+
+	for _, e := range entries {
+		key := targetKey(e.Scope, e.Dest)
+		byTarget[key] = append(byTarget[key], e)
+	}
+
+	list := make([]TargetAvailability, 0, len(byTarget))
+
+	for _, samples := range byTarget {
+		sort.Slice(samples, func(a, b int) bool { return samples[a].Time.Before(samples[b].Time) })
+
+		list = append(list, targetAvailability(samples, to))
+	}
+
+	sort.Slice(list, func(a, b int) bool {
+		if list[a].Scope != list[b].Scope {
+			return list[a].Scope < list[b].Scope
+		}
+
+		return list[a].Dest < list[b].Dest
+	})
+
+	return list
+}
+
+func targetAvailability(samples []HistoryEntry, to time.Time) TargetAvailability {
+	ta := TargetAvailability{Scope: samples[0].Scope, Dest: samples[0].Dest}
+
+	var (
+		healthy, total, recovered time.Duration
+		outageStart               time.Time
+		inOutage                  bool
+		recoveries                int
+	)
+
+	for n, e := range samples {
+		end := to
+		if n+1 < len(samples) {
+			end = samples[n+1].Time
+		}
+
+		total += end.Sub(e.Time)
+
+		if e.Healthy {
+			healthy += end.Sub(e.Time)
+
+			if inOutage {
+				inOutage = false
+				outage := e.Time.Sub(outageStart)
+				recovered += outage
+				recoveries++
+				ta.LongestOutage = max(ta.LongestOutage, outage)
+			}
+
+			continue
+		}
+
+		if !inOutage {
+			inOutage = true
+			outageStart = e.Time
+			ta.Outages++
+		}
+	}
+
+	if inOutage {
+		ta.LongestOutage = max(ta.LongestOutage, to.Sub(outageStart))
+	}
+
+	if total > 0 {
+		ta.Availability = float64(healthy) / float64(total) * 100
+	} else if samples[len(samples)-1].Healthy {
+		ta.Availability = 100
+	}
+
+	if recoveries > 0 {
+		ta.MTTR = recovered / time.Duration(recoveries)
+	}
+
+	return ta
+}
@@ -0,0 +1,71 @@
+package healthz
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_availability(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(m int) time.Time { return base.Add(time.Duration(m) * time.Minute) }
+
+	entries := []HistoryEntry{
+		// pg: 10m up, 10m down, 20m up, 5m down, 15m up
+		{Time: at(0), Scope: "db", Dest: "pg", Healthy: true},
+		{Time: at(10), Scope: "db", Dest: "pg", Healthy: false},
+		{Time: at(15), Scope: "db", Dest: "pg", Healthy: false},
+		{Time: at(20), Scope: "db", Dest: "pg", Healthy: true},
+		{Time: at(40), Scope: "db", Dest: "pg", Healthy: false},
+		{Time: at(45), Scope: "db", Dest: "pg", Healthy: true},
+		// cache: up till 30m, then down to the end
+		{Time: at(0), Scope: "cache", Dest: "redis", Healthy: true},
+		{Time: at(30), Scope: "cache", Dest: "redis", Healthy: false},
+	}
+
+	got := availability(entries, at(60))
+	assert.Len(t, got, 2)
+
+	assert.Equal(t, TargetAvailability{
+		Scope:         "cache",
+		Dest:          "redis",
+		Availability:  50,
+		Outages:       1,
+		LongestOutage: 30 * time.Minute,
+	}, got[0])
+
+	assert.Equal(t, "pg", got[1].Dest)
+	assert.InDelta(t, 75, got[1].Availability, 0.001)
+	assert.Equal(t, 2, got[1].Outages)
+	assert.Equal(t, 10*time.Minute, got[1].LongestOutage)
+	assert.Equal(t, 7*time.Minute+30*time.Second, got[1].MTTR)
+}
+
+func TestReportHandler(t *testing.T) {
+	inspector := New(HealthCheckTarget{Service: &mockService{scope: "db", dest: "pg"}, Groups: GroupReady})
+
+	store, err := NewMemoryHistory(100)
+	assert.NoError(t, err)
+	assert.NoError(t, WithHistory(store)(inspector))
+
+	inspector.check(context.Background())
+
+	list, err := inspector.Availability(time.Hour)
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+
+	w := httptest.NewRecorder()
+	inspector.ReportHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz/report?window=24h", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var got []map[string]any
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+	assert.Len(t, got, 1)
+	assert.Equal(t, "pg", got[0]["dest"])
+	assert.Equal(t, 100.0, got[0]["availability"])
+}
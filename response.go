@@ -0,0 +1,102 @@
+package healthz
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+var errWrongStatusCode = errors.New("incorrect http status code")
+
+// Freshness headers of probe responses: start of the evaluated check cycle (RFC 3339)
+// and its age in seconds, to tell a fresh evaluation from a stale one.
+const (
+	HeaderCheckedAt = "X-Healthz-Checked-At"
+	HeaderAge       = "X-Healthz-Age-Seconds"
+	HeaderSeq       = "X-Healthz-Seq" // sequence number of the evaluated result, see Snapshot.Seq
+)
+
+// ResponseStrategy - maps evaluation of a probe group to the HTTP response.
+type ResponseStrategy struct {
+	HealthyStatus   int       // default http.StatusOK
+	UnhealthyStatus int       // default http.StatusServiceUnavailable
+	Formatter       Formatter // renders body, default FormatPlain
+}
+
+// DefResponseStrategy - 200 for healthy, 503 for unhealthy, plain body.
+var DefResponseStrategy = ResponseStrategy{
+	HealthyStatus:   http.StatusOK,
+	UnhealthyStatus: http.StatusServiceUnavailable,
+	Formatter:       formatters[FormatPlain],
+}
+
+func (rs ResponseStrategy) validate() error {
+	for _, code := range []int{rs.HealthyStatus, rs.UnhealthyStatus} {
+		if code != 0 && (code < 100 || code > 599) {
+			return errWrongStatusCode
+		}
+	}
+
+	return nil
+}
+
+// Status - http status code of the report.
+func (rs ResponseStrategy) Status(report ProbeReport) int {
+	if report.Err != nil {
+		if rs.UnhealthyStatus == 0 {
+			return DefResponseStrategy.UnhealthyStatus
+		}
+
+		return rs.UnhealthyStatus
+	}
+
+	if rs.HealthyStatus == 0 {
+		return DefResponseStrategy.HealthyStatus
+	}
+
+	return rs.HealthyStatus
+}
+
+// Write - sends the report: headers first, then status code, then body (omitted for HEAD requests).
+func (rs ResponseStrategy) Write(w http.ResponseWriter, r *http.Request, report ProbeReport) {
+	formatter := rs.Formatter
+	if formatter.Format == nil {
+		formatter = DefResponseStrategy.Formatter
+	}
+
+	body := formatter.Format(report)
+
+	if formatter.ContentType != "" {
+		w.Header().Set("Content-Type", formatter.ContentType)
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+
+	if !report.CheckedAt.IsZero() {
+		w.Header().Set(HeaderCheckedAt, report.CheckedAt.UTC().Format(time.RFC3339Nano))
+		w.Header().Set(HeaderAge, strconv.FormatFloat(report.Age().Seconds(), 'f', 3, 64))
+		w.Header().Set(HeaderSeq, strconv.FormatUint(report.Seq, 10))
+	}
+
+	w.WriteHeader(rs.Status(report))
+
+	if r.Method == http.MethodHead {
+		return
+	}
+
+	w.Write(body)
+}
+
+// WithResponseStrategy - sets status codes and formatter of HealthHandler responses.
+func WithResponseStrategy(rs ResponseStrategy) Option {
+	return func(i *Inspector) error {
+		if err := rs.validate(); err != nil {
+			return err
+		}
+
+		i.response = rs
+
+		return nil
+	}
+}
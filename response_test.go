@@ -0,0 +1,117 @@
+package healthz
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResponseStrategy_Write(t *testing.T) {
+	custom := ResponseStrategy{
+		HealthyStatus:   http.StatusNoContent,
+		UnhealthyStatus: http.StatusInternalServerError,
+		Formatter:       formatters[FormatJSON],
+	}
+
+	tests := []struct {
+		name        string
+		strategy    ResponseStrategy
+		method      string
+		report      ProbeReport
+		wantStatus  int
+		wantBody    string
+		contentType string
+	}{
+		{
+			name:        "test.1 default healthy",
+			strategy:    DefResponseStrategy,
+			method:      http.MethodGet,
+			report:      ProbeReport{Group: GroupLive},
+			wantStatus:  http.StatusOK,
+			wantBody:    "OK",
+			contentType: "text/plain; charset=utf-8",
+		},
+		{
+			name:        "test.2 default unhealthy",
+			strategy:    DefResponseStrategy,
+			method:      http.MethodGet,
+			report:      ProbeReport{Group: GroupLive, Err: errors.New("fail")},
+			wantStatus:  http.StatusServiceUnavailable,
+			wantBody:    "Unhealthy",
+			contentType: "text/plain; charset=utf-8",
+		},
+		{
+			name:        "test.3 zero value falls back to defaults",
+			strategy:    ResponseStrategy{},
+			method:      http.MethodGet,
+			report:      ProbeReport{Group: GroupLive, Err: errors.New("fail")},
+			wantStatus:  http.StatusServiceUnavailable,
+			wantBody:    "Unhealthy",
+			contentType: "text/plain; charset=utf-8",
+		},
+		{
+			name:        "test.4 custom codes",
+			strategy:    custom,
+			method:      http.MethodGet,
+			report:      ProbeReport{Group: GroupReady, Err: errors.New("fail")},
+			wantStatus:  http.StatusInternalServerError,
+			wantBody:    `{"status":"unhealthy","group":"ready","error":"fail"}`,
+			contentType: "application/json",
+		},
+		{
+			name:        "test.5 HEAD without body",
+			strategy:    DefResponseStrategy,
+			method:      http.MethodHead,
+			report:      ProbeReport{Group: GroupLive},
+			wantStatus:  http.StatusOK,
+			wantBody:    "",
+			contentType: "text/plain; charset=utf-8",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			tt.strategy.Write(w, httptest.NewRequest(tt.method, "/healthz", nil), tt.report)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, tt.wantBody, w.Body.String())
+			assert.Equal(t, tt.contentType, w.Header().Get("Content-Type"))
+			assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+		})
+	}
+}
+
+func TestWithResponseStrategy(t *testing.T) {
+	assert.Error(t, WithResponseStrategy(ResponseStrategy{HealthyStatus: 42})(New()))
+	assert.Error(t, WithResponseStrategy(ResponseStrategy{UnhealthyStatus: 600})(New()))
+
+	inspector := New()
+	assert.NoError(t, WithResponseStrategy(ResponseStrategy{UnhealthyStatus: http.StatusTooEarly})(inspector))
+
+	// not yet checked
+	w := httptest.NewRecorder()
+	inspector.HealthHandler(GroupStartup, true, nil)(w, httptest.NewRequest(http.MethodGet, "/healthz/startup", nil))
+	assert.Equal(t, http.StatusTooEarly, w.Code)
+}
+
+func TestResponseStrategy_Freshness(t *testing.T) {
+	w := httptest.NewRecorder()
+	DefResponseStrategy.Write(w, httptest.NewRequest(http.MethodGet, "/healthz", nil), ProbeReport{Group: GroupReady})
+	assert.Empty(t, w.Header().Get(HeaderCheckedAt), "not yet checked")
+	assert.Empty(t, w.Header().Get(HeaderAge))
+
+	checkedAt := time.Now().Add(-90 * time.Second)
+
+	w = httptest.NewRecorder()
+	DefResponseStrategy.Write(w, httptest.NewRequest(http.MethodGet, "/healthz", nil), ProbeReport{Group: GroupReady, CheckedAt: checkedAt})
+	assert.Equal(t, checkedAt.UTC().Format(time.RFC3339Nano), w.Header().Get(HeaderCheckedAt))
+
+	age, err := strconv.ParseFloat(w.Header().Get(HeaderAge), 64)
+	assert.NoError(t, err)
+	assert.InDelta(t, 90, age, 1)
+}
@@ -0,0 +1,143 @@
+package healthz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+const defRetentionInterval = time.Minute
+
+var (
+	errWrongRetention   = errors.New("incorrect history retention")
+	errHistoryNoPruning = errors.New("history store doesn't support pruning")
+)
+
+// HistoryRetention - retention policy of the history store, zero limits are off.
+type HistoryRetention struct {
+	MaxAge     time.Duration // entries older than MaxAge are removed
+	MaxEntries int           // newest entries kept per target
+	Interval   time.Duration // of the background pruning, 1m by default
+}
+
+// HistoryPruner - history store supporting retention, built-in stores implement it.
+type HistoryPruner interface {
+	Prune(before time.Time, maxPerTarget int) (int, error) // removes entries, returns how many
+	Len() int                                              // entries in the store
+}
+
+type retention struct {
+	policy HistoryRetention
+	pruned atomic.Uint64
+}
+
+// WithHistoryRetention - prunes the history store in background while the inspector runs,
+// so long-running services don't grow memory or disk unbounded. The store (see WithHistory,
+// must precede) must implement HistoryPruner. Store size and pruned entries are exported
+// by WithSelfMetrics.
+func WithHistoryRetention(policy HistoryRetention) Option {
+	return func(i *Inspector) error {
+		if policy.MaxAge < 0 || policy.MaxEntries < 0 || policy.Interval < 0 ||
+			policy.MaxAge == 0 && policy.MaxEntries == 0 {
+			return errWrongRetention
+		}
+
+		if i.history == nil {
+			return errMissHistory
+		}
+
+		if _, ok := i.history.(HistoryPruner); !ok {
+			return fmt.Errorf("%w: %T", errHistoryNoPruning, i.history)
+		}
+
+		if policy.Interval == 0 {
+			policy.Interval = defRetentionInterval
+		}
+
+		i.retention = &retention{policy: policy}
+
+		return nil
+	}
+}
+
+// runRetention - prunes the history every interval until ctx is done or stop is closed.
+func (i *Inspector) runRetention(ctx context.Context, stop <-chan struct{}) {
+	ticker := time.NewTicker(i.retention.policy.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			i.pruneHistory(now)
+		}
+	}
+}
+
+// pruneHistory - applies the retention policy at now.
+func (i *Inspector) pruneHistory(now time.Time) {
+	pruner, ok := i.history.(HistoryPruner)
+	if !ok {
+		return
+	}
+
+	var before time.Time
+	if i.retention.policy.MaxAge > 0 {
+		before = now.Add(-i.retention.policy.MaxAge)
+	}
+
+	removed, err := pruner.Prune(before, i.retention.policy.MaxEntries)
+	i.retention.pruned.Add(uint64(removed))
+
+	if err != nil {
+		i.log().Warn("healthz: history not pruned", "error", err)
+	}
+}
+
+// historyLen - entries in the history store, zero if it isn't a HistoryPruner.
+func (i *Inspector) historyLen() int {
+	if pruner, ok := i.history.(HistoryPruner); ok {
+		return pruner.Len()
+	}
+
+	return 0
+}
+
+// retainEntries - entries (oldest first) not before the time (if set), newest maxPerTarget
+// per target (if set), in the same order.
+func retainEntries(entries []HistoryEntry, before time.Time, maxPerTarget int) []HistoryEntry {
+	perTarget := make(map[string]int)
+	keep := make([]bool, len(entries))
+	kept := 0
+
+	for n := len(entries) - 1; n >= 0; n-- {
+		e := entries[n]
+		if e.Time.Before(before) {
+			continue
+		}
+
+		key := targetKey(e.Scope, e.Dest)
+		if maxPerTarget > 0 && perTarget[key] >= maxPerTarget {
+			continue
+		}
+
+		perTarget[key]++
+		keep[n] = true
+		kept++
+	}
+
+	list := make([]HistoryEntry, 0, kept)
+
+	for n, e := range entries {
+		if keep[n] {
+			list = append(list, e)
+		}
+	}
+
+	return list
+}
@@ -0,0 +1,169 @@
+package healthz
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithHistoryRetention(t *testing.T) {
+	memory, err := NewMemoryHistory(10)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		store   HistoryStore
+		policy  HistoryRetention
+		wantErr error
+	}{
+		{name: "test.1 ok", store: memory, policy: HistoryRetention{MaxAge: time.Hour}},
+		{name: "test.2 no limits", store: memory, wantErr: errWrongRetention},
+		{name: "test.3 negative", store: memory, policy: HistoryRetention{MaxEntries: -1}, wantErr: errWrongRetention},
+		{name: "test.4 no history", policy: HistoryRetention{MaxEntries: 1}, wantErr: errMissHistory},
+		{name: "test.5 no pruning", store: &stubHistory{}, policy: HistoryRetention{MaxEntries: 1}, wantErr: errHistoryNoPruning},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inspector := New()
+			if tt.store != nil {
+				assert.NoError(t, WithHistory(tt.store)(inspector))
+			}
+
+			err := WithHistoryRetention(tt.policy)(inspector)
+			assert.ErrorIs(t, err, tt.wantErr)
+
+			if tt.wantErr == nil {
+				assert.Equal(t, defRetentionInterval, inspector.retention.policy.Interval)
+			}
+		})
+	}
+}
+
+func testHistoryPruner(t *testing.T, store interface {
+	HistoryStore
+	HistoryPruner
+},
+) {
+	t.Helper()
+
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for n := range 4 {
+		at := base.Add(time.Duration(n) * time.Minute)
+		assert.NoError(t, store.Append(
+			HistoryEntry{Time: at, Scope: "db", Dest: "pg", Healthy: true},
+			HistoryEntry{Time: at, Scope: "cache", Dest: "redis", Healthy: true},
+		))
+	}
+
+	assert.Equal(t, 8, store.Len())
+
+	removed, err := store.Prune(base.Add(time.Minute), 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, removed, "older than max age")
+	assert.Equal(t, 6, store.Len())
+
+	removed, err = store.Prune(time.Time{}, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, removed, "beyond max entries per target")
+	assert.Equal(t, 4, store.Len())
+
+	entries, err := store.Query(base, base.Add(time.Hour))
+	assert.NoError(t, err)
+
+	if assert.Len(t, entries, 4) {
+		assert.Equal(t, base.Add(2*time.Minute), entries[0].Time.UTC(), "newest are kept, oldest first")
+		assert.Equal(t, base.Add(3*time.Minute), entries[3].Time.UTC())
+	}
+
+	assert.NoError(t, store.Append(HistoryEntry{Time: base.Add(4 * time.Minute), Scope: "db", Dest: "pg"}))
+	assert.Equal(t, 5, store.Len(), "appends after pruning")
+
+	removed, err = store.Prune(time.Time{}, 10)
+	assert.NoError(t, err)
+	assert.Zero(t, removed)
+}
+
+func TestMemoryHistory_Prune(t *testing.T) {
+	store, err := NewMemoryHistory(10)
+	assert.NoError(t, err)
+
+	testHistoryPruner(t, store)
+
+	ring, err := NewMemoryHistory(3)
+	assert.NoError(t, err)
+
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for n := range 5 { // wraps around
+		assert.NoError(t, ring.Append(HistoryEntry{Time: base.Add(time.Duration(n) * time.Minute), Scope: "db", Dest: "pg"}))
+	}
+
+	removed, err := ring.Prune(time.Time{}, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, removed)
+
+	entries, err := ring.Query(base, base.Add(time.Hour))
+	assert.NoError(t, err)
+
+	if assert.Len(t, entries, 2) {
+		assert.Equal(t, base.Add(3*time.Minute), entries[0].Time)
+		assert.Equal(t, base.Add(4*time.Minute), entries[1].Time)
+	}
+}
+
+func TestFileHistory_Prune(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+
+	store, err := NewFileHistory(path)
+	assert.NoError(t, err)
+
+	defer store.Close()
+
+	testHistoryPruner(t, store)
+
+	reopened, err := NewFileHistory(path)
+	assert.NoError(t, err)
+
+	defer reopened.Close()
+
+	assert.Equal(t, 5, reopened.Len(), "pruned file survives restarts")
+}
+
+func TestInspector_historyRetention(t *testing.T) {
+	store, err := NewMemoryHistory(100)
+	assert.NoError(t, err)
+
+	reg := prometheus.NewRegistry()
+
+	inspector := New(HealthCheckTarget{Service: &mockService{scope: "db", dest: "pg"}, Groups: GroupReady})
+	assert.NoError(t, WithHistory(store)(inspector))
+	assert.NoError(t, WithHistoryRetention(HistoryRetention{MaxEntries: 2, Interval: time.Millisecond})(inspector))
+	assert.NoError(t, WithSelfMetrics(reg)(inspector))
+
+	for range 5 {
+		inspector.check(context.Background())
+	}
+
+	assert.NoError(t, inspector.Start(context.Background()))
+
+	defer inspector.Stop(context.Background())
+
+	assert.Eventually(t, func() bool {
+		values := gatherValues(t, reg)
+
+		return values["healthz_history_pruned_total"] > 0 && values["healthz_history_entries"] <= 4
+	}, time.Second, 5*time.Millisecond)
+}
+
+// stubHistory - history store without pruning.
+type stubHistory struct{}
+
+func (stubHistory) Append(...HistoryEntry) error { return nil }
+
+func (stubHistory) Query(time.Time, time.Time) ([]HistoryEntry, error) { return nil, nil }
@@ -0,0 +1,108 @@
+package healthz
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	errWrongRetries     = errors.New("retry attempts must be positive")
+	errWrongRetryBudget = errors.New("retry budget needs positive max tokens and ratio")
+)
+
+type retrySettings struct {
+	attempts  int
+	backoff   time.Duration
+	maxTokens float64 // budget per scope, disabled if zero
+	ratio     float64
+
+	mu      sync.Mutex
+	budgets map[string]*retryBudget // per scope
+}
+
+// retryBudget - token bucket of gRPC retry throttling.
+type retryBudget struct {
+	tokens float64
+}
+
+// WithRetries - failed checks are repeated within the cycle up to attempts in total with the backoff
+// between them, the attempt is passed by CheckInfo.Attempt.
+func WithRetries(attempts int, backoff time.Duration) Option {
+	return func(i *Inspector) error {
+		if attempts < 1 {
+			return errWrongRetries
+		}
+
+		if backoff < 0 {
+			return errWrongTimeout
+		}
+
+		i.retry.attempts = attempts
+		i.retry.backoff = backoff
+
+		return nil
+	}
+}
+
+// WithRetryBudget - per scope retry budget like gRPC retry throttling: every failed attempt of the
+// scope targets takes a token, every success returns ratio of a token (up to maxTokens), retries are
+// allowed while more than half of maxTokens remain. When the whole scope (all DB nodes) is failing
+// retries are suppressed to not multiply load during the outage.
+func WithRetryBudget(maxTokens, ratio float64) Option {
+	return func(i *Inspector) error {
+		if maxTokens <= 0 || ratio <= 0 {
+			return errWrongRetryBudget
+		}
+
+		i.retry.mu.Lock()
+		defer i.retry.mu.Unlock()
+
+		i.retry.maxTokens = maxTokens
+		i.retry.ratio = ratio
+		i.retry.budgets = nil
+
+		return nil
+	}
+}
+
+// retryAllowed - accounts the attempt result in the scope budget, reports whether to retry
+// (waiting the backoff).
+func (i *Inspector) retryAllowed(ctx context.Context, target HealthCheckTarget, attempt int, err error) bool {
+	allowed := i.retry.record(target.Service.Scope(), err == nil)
+
+	if err == nil || attempt >= i.retry.attempts || !allowed || ctx.Err() != nil {
+		return false
+	}
+
+	return sleepCtx(ctx, i.retry.backoff) == nil
+}
+
+// record - accounts the attempt in the scope budget, reports whether retries are allowed.
+func (rs *retrySettings) record(scope string, ok bool) bool {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	if rs.maxTokens == 0 {
+		return true
+	}
+
+	if rs.budgets == nil {
+		rs.budgets = make(map[string]*retryBudget)
+	}
+
+	budget, found := rs.budgets[scope]
+	if !found {
+		budget = &retryBudget{tokens: rs.maxTokens}
+		rs.budgets[scope] = budget
+	}
+
+	if ok {
+		budget.tokens = min(budget.tokens+rs.ratio, rs.maxTokens)
+	} else {
+		budget.tokens = max(budget.tokens-1, 0)
+	}
+
+	return budget.tokens > rs.maxTokens/2
+}
@@ -0,0 +1,92 @@
+package healthz
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// flakyService - fails first failures calls.
+type flakyService struct {
+	scope    string
+	dest     string
+	failures int32
+	calls    atomic.Int32
+	attempts []int
+}
+
+func (s *flakyService) Health(ctx context.Context) error {
+	info, _ := CheckInfoFromContext(ctx)
+	s.attempts = append(s.attempts, info.Attempt)
+
+	if s.calls.Add(1) <= s.failures {
+		return errors.New("flaky")
+	}
+
+	return nil
+}
+func (s *flakyService) Scope() string { return s.scope }
+func (s *flakyService) Dest() string  { return s.dest }
+
+func TestWithRetries(t *testing.T) {
+	svc := &flakyService{scope: "db", dest: "pg", failures: 2}
+
+	inspector := New(HealthCheckTarget{Service: svc, Groups: GroupReady})
+	assert.NoError(t, WithRetries(3, 0)(inspector))
+
+	inspector.check(context.Background())
+
+	assert.NoError(t, inspector.CheckGroup(GroupReady, true))
+	assert.Equal(t, []int{1, 2, 3}, svc.attempts)
+
+	svc = &flakyService{scope: "db", dest: "pg", failures: 5}
+	inspector = New(HealthCheckTarget{Service: svc, Groups: GroupReady})
+	assert.NoError(t, WithRetries(2, 0)(inspector))
+
+	inspector.check(context.Background())
+
+	assert.Error(t, inspector.CheckGroup(GroupReady, true))
+	assert.Equal(t, int32(2), svc.calls.Load())
+
+	assert.ErrorIs(t, WithRetries(0, 0)(New()), errWrongRetries)
+	assert.ErrorIs(t, WithRetries(2, -1)(New()), errWrongTimeout)
+}
+
+func TestWithRetryBudget(t *testing.T) {
+	var (
+		targets []HealthCheckTarget
+		nodes   []*flakyService
+	)
+
+	for _, dest := range []string{"pg-1", "pg-2", "pg-3", "pg-4"} {
+		svc := &flakyService{scope: "db", dest: dest, failures: 100}
+		nodes = append(nodes, svc)
+		targets = append(targets, HealthCheckTarget{Service: svc, Groups: GroupReady})
+	}
+
+	cache := &flakyService{scope: "cache", dest: "redis", failures: 1}
+	targets = append(targets, HealthCheckTarget{Service: cache, Groups: GroupLive})
+
+	inspector := New(targets...)
+	assert.NoError(t, WithRetries(3, 0)(inspector))
+	assert.NoError(t, WithRetryBudget(4, 0.1)(inspector))
+	assert.NoError(t, WithScopeConcurrency("db", 1)(inspector))
+
+	inspector.check(context.Background())
+
+	var calls int32
+	for _, svc := range nodes {
+		calls += svc.calls.Load()
+	}
+
+	// without the budget 4 nodes * 3 attempts, budget allows retries while more than 2 tokens remain
+	assert.Equal(t, int32(4+1), calls)
+
+	assert.NoError(t, inspector.CheckGroup(GroupLive, true), "budget of other scope isn't spent")
+	assert.Equal(t, int32(2), cache.calls.Load())
+
+	assert.ErrorIs(t, WithRetryBudget(0, 1)(New()), errWrongRetryBudget)
+}
@@ -0,0 +1,175 @@
+package healthz
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	errNoShadow      = errors.New("no shadow inspector")
+	errShadowActive  = errors.New("shadow inspector is the active one")
+	errMissInspector = errors.New("inspector is required")
+)
+
+// generation - inspector backing the handlers of Rollout with its handler.
+type generation struct {
+	inspector *Inspector
+	handler   http.Handler
+}
+
+// Rollout - blue/green switch of the inspector backing the handlers, for safe changes of critical
+// probe logic: a candidate (e.g. built by WithConfig or WithOverrides) runs in shadow mode alongside
+// the active inspector, its results are compared (see Diff) but not served or exported
+// (metrics, history, execution log, webhook, subscribers), then Promote swaps the inspectors atomically.
+// Children of the active inspector stay with it.
+type Rollout struct {
+	active atomic.Pointer[generation]
+	mu     sync.Mutex // guards shadow and the swap
+	shadow *Inspector
+}
+
+// NewRollout - rollout with the inspector active, it's started and stopped by its owner till retired by Promote.
+func NewRollout(active *Inspector) (*Rollout, error) {
+	if active == nil {
+		return nil, errMissInspector
+	}
+
+	r := &Rollout{}
+	r.active.Store(&generation{inspector: active, handler: active.Handler()})
+
+	return r, nil
+}
+
+// Active - inspector backing the handlers.
+func (r *Rollout) Active() *Inspector {
+	return r.active.Load().inspector
+}
+
+// Shadow - candidate running in shadow mode, nil if none.
+func (r *Rollout) Shadow() *Inspector {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.shadow
+}
+
+// StartShadow - starts the candidate in shadow mode, the previous shadow is stopped.
+// The candidate must not be started yet. Its checks run till it's aborted, retired or the rollout
+// is stopped, cancellation of ctx doesn't stop them (it bounds stopping the previous shadow).
+func (r *Rollout) StartShadow(ctx context.Context, candidate *Inspector) error {
+	if candidate == nil {
+		return errMissInspector
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if candidate == r.Active() {
+		return errShadowActive
+	}
+
+	candidate.shadow.Store(true)
+
+	if err := candidate.Start(context.WithoutCancel(ctx)); err != nil {
+		candidate.shadow.Store(false)
+
+		return err
+	}
+
+	prev := r.shadow
+	r.shadow = candidate
+
+	if prev != nil {
+		return prev.halt(ctx)
+	}
+
+	return nil
+}
+
+// Diff - difference of the active inspector and the shadow results (see Compare),
+// e.g. Diff.Regressions to decide on Promote.
+func (r *Rollout) Diff() (Diff, error) {
+	r.mu.Lock()
+	active, shadow := r.Active(), r.shadow
+	r.mu.Unlock()
+
+	if shadow == nil {
+		return Diff{}, errNoShadow
+	}
+
+	return Compare(active.Snapshot(), shadow.Snapshot()), nil
+}
+
+// Promote - swaps atomically the shadow into the active inspector backing the handlers, it starts
+// exporting results from the next cycle and takes over readiness forced on the retired one (see SetNotReady).
+// The retired inspector stops checks without flipping readiness and running OnStopping hooks,
+// it's returned to the caller.
+func (r *Rollout) Promote(ctx context.Context) (*Inspector, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.shadow == nil {
+		return nil, errNoShadow
+	}
+
+	promoted := r.shadow
+	r.shadow = nil
+
+	promoted.forced.Store(r.Active().forced.Load())
+	promoted.shadow.Store(false)
+
+	retired := r.active.Swap(&generation{inspector: promoted, handler: promoted.Handler()}).inspector
+
+	return retired, retired.halt(ctx)
+}
+
+// AbortShadow - stops the shadow, the active inspector stays as is.
+func (r *Rollout) AbortShadow(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.abortShadow(ctx)
+}
+
+// abortShadow - see AbortShadow, r.mu must be held.
+func (r *Rollout) abortShadow(ctx context.Context) error {
+	if r.shadow == nil {
+		return errNoShadow
+	}
+
+	shadow := r.shadow
+	r.shadow = nil
+
+	return shadow.halt(ctx)
+}
+
+// Stop - stops the shadow if any and the active inspector (see Inspector.Stop).
+func (r *Rollout) Stop(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var shadowErr error
+
+	if err := r.abortShadow(ctx); !errors.Is(err, errNoShadow) {
+		shadowErr = err
+	}
+
+	return errors.Join(shadowErr, r.Active().Stop(ctx))
+}
+
+// Handler - handler of the active inspector (see Inspector.Handler), switched by Promote.
+func (r *Rollout) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		r.active.Load().handler.ServeHTTP(w, req)
+	})
+}
+
+// HealthHandler - Inspector.HealthHandler of the active inspector, switched by Promote.
+func (r *Rollout) HealthHandler(group ProbeGroup, needAllHealthy bool, toResponse func(error) []byte) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		r.Active().HealthHandler(group, needAllHealthy, toResponse)(w, req)
+	}
+}
@@ -0,0 +1,152 @@
+package healthz
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRollout(t *testing.T) {
+	_, err := NewRollout(nil)
+	assert.ErrorIs(t, err, errMissInspector)
+
+	up := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test_rollout_up"}, []string{"scope", "dest"})
+
+	active := New(HealthCheckTarget{Service: &mockService{scope: "db", dest: "pg"}, Groups: GroupReady})
+	assert.NoError(t, WithMetric(up)(active))
+
+	active.check(context.Background())
+
+	rollout, err := NewRollout(active)
+	assert.NoError(t, err)
+
+	ready := func() int {
+		rec := httptest.NewRecorder()
+		rollout.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, PathReady, nil))
+
+		return rec.Code
+	}
+
+	_, err = rollout.Diff()
+	assert.ErrorIs(t, err, errNoShadow)
+	assert.ErrorIs(t, rollout.StartShadow(context.Background(), active), errShadowActive)
+
+	// stricter probe logic of the candidate fails the target
+	candidate := New(HealthCheckTarget{
+		Service: &mockService{scope: "db", dest: "pg", healthErr: errors.New("replica lag")},
+		Groups:  GroupReady,
+	})
+	assert.NoError(t, WithMetric(up)(candidate))
+	assert.NoError(t, WithCheckPeriod(5*time.Millisecond)(candidate))
+
+	assert.NoError(t, rollout.StartShadow(context.Background(), candidate))
+	assert.Same(t, candidate, rollout.Shadow())
+
+	assert.Eventually(t, func() bool {
+		diff, err := rollout.Diff()
+
+		return err == nil && len(diff.Regressions()) == 1
+	}, time.Second, time.Millisecond)
+
+	assert.Equal(t, http.StatusOK, ready(), "the shadow isn't served")
+	assert.Equal(t, 1.0, testutil.ToFloat64(up.WithLabelValues("db", "pg")), "the shadow isn't exported")
+
+	retired, err := rollout.Promote(context.Background())
+	assert.NoError(t, err)
+	assert.Same(t, active, retired)
+	assert.Same(t, candidate, rollout.Active())
+	assert.Nil(t, rollout.Shadow())
+	assert.False(t, retired.shuttingDown.Load(), "retired inspector doesn't flip readiness")
+
+	assert.Equal(t, http.StatusServiceUnavailable, ready(), "the promoted inspector is served")
+	assert.Eventually(t, func() bool {
+		return testutil.ToFloat64(up.WithLabelValues("db", "pg")) == 0
+	}, time.Second, time.Millisecond, "the promoted inspector is exported")
+
+	_, err = rollout.Promote(context.Background())
+	assert.ErrorIs(t, err, errNoShadow)
+	assert.ErrorIs(t, rollout.AbortShadow(context.Background()), errNoShadow)
+
+	assert.NoError(t, rollout.Stop(context.Background()))
+	assert.Equal(t, StateStopped, candidate.Status().State)
+}
+
+func TestRollout_AbortShadow(t *testing.T) {
+	active := New(HealthCheckTarget{Service: &mockService{scope: "db", dest: "pg"}, Groups: GroupReady})
+
+	rollout, err := NewRollout(active)
+	assert.NoError(t, err)
+
+	first := active.WithOverrides()
+	second := active.WithOverrides()
+
+	assert.NoError(t, rollout.StartShadow(context.Background(), first))
+	assert.NoError(t, rollout.StartShadow(context.Background(), second))
+	assert.Eventually(t, func() bool { return first.Status().State == StateStopped }, time.Second, time.Millisecond,
+		"the previous shadow is stopped")
+
+	assert.NoError(t, rollout.AbortShadow(context.Background()))
+	assert.Eventually(t, func() bool { return second.Status().State == StateStopped }, time.Second, time.Millisecond)
+	assert.Same(t, active, rollout.Active())
+	assert.Nil(t, rollout.Shadow())
+}
+
+func TestRollout_Promote(t *testing.T) {
+	active := New(HealthCheckTarget{Service: &mockService{scope: "db", dest: "pg"}, Groups: GroupReady})
+	active.check(context.Background())
+	active.SetNotReady("maintenance")
+
+	rollout, err := NewRollout(active)
+	assert.NoError(t, err)
+
+	candidate := active.WithOverrides(WithCheckPeriod(5 * time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	assert.NoError(t, rollout.StartShadow(ctx, candidate))
+	cancel()
+
+	seq := candidate.Snapshot().Seq
+	assert.Eventually(t, func() bool { return candidate.Snapshot().Seq > seq+2 }, time.Second, time.Millisecond,
+		"the shadow outlives the context of StartShadow")
+
+	_, err = rollout.Promote(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, StateRunning, candidate.Status().State)
+	assert.ErrorContains(t, candidate.CheckGroup(GroupReady, true), "maintenance", "forced readiness is taken over")
+
+	assert.NoError(t, rollout.Stop(context.Background()))
+}
+
+func TestRollout_concurrentSwap(t *testing.T) {
+	active := New(HealthCheckTarget{Service: &mockService{scope: "db", dest: "pg"}, Groups: GroupReady})
+
+	rollout, err := NewRollout(active)
+	assert.NoError(t, err)
+
+	for range 20 {
+		assert.NoError(t, rollout.StartShadow(context.Background(), rollout.Active().WithOverrides()))
+
+		promoted := make(chan error, 1)
+		go func() {
+			_, err := rollout.Promote(context.Background())
+			promoted <- err
+		}()
+
+		aborted := rollout.AbortShadow(context.Background())
+
+		errs := []error{aborted, <-promoted}
+		assert.Len(t, slices.DeleteFunc(errs, func(err error) bool { return errors.Is(err, errNoShadow) }), 1,
+			"either the shadow is promoted or aborted")
+		assert.Nil(t, rollout.Shadow())
+	}
+
+	assert.NoError(t, rollout.Stop(context.Background()))
+}
@@ -0,0 +1,124 @@
+package healthz
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+var errWrongRoute = errors.New("incorrect route")
+
+// Routes - paths of the endpoints keyed by their default paths (PathLive, PathReady, ...),
+// endpoints not listed keep the default path, an empty path disables the endpoint.
+type Routes map[string]string
+
+// KubernetesRoutes - probe endpoints named like the ones of the Kubernetes components.
+var KubernetesRoutes = Routes{
+	PathStartup: "/startupz",
+	PathLive:    "/livez",
+	PathReady:   "/readyz",
+}
+
+// Route - endpoint of Inspector.Handler.
+type Route struct {
+	Endpoint string     // default path of the endpoint, e.g. PathLive
+	Path     string     // path the endpoint is served on
+	Group    ProbeGroup // probe group of the probe endpoints, zero for others
+}
+
+// WithRoutes - renames and re-paths the endpoints of Inspector.Handler (and RegisterRoutes, NewMultiServer),
+// e.g. KubernetesRoutes or company-specific paths, so which group maps to which path is set in one place.
+func WithRoutes(routes Routes) Option {
+	return func(i *Inspector) error {
+		seen := make(map[string]bool, len(defaultPaths))
+
+		for _, endpoint := range defaultPaths {
+			path, ok := routes[endpoint]
+			if !ok {
+				path = endpoint
+			}
+
+			if path == "" {
+				continue
+			}
+
+			if !strings.HasPrefix(path, "/") || seen[path] {
+				return fmt.Errorf("%w: %q", errWrongRoute, path)
+			}
+
+			seen[path] = true
+		}
+
+		for endpoint := range routes {
+			if !slices.Contains(defaultPaths, endpoint) {
+				return fmt.Errorf("%w: %q", errUnknownPath, endpoint)
+			}
+		}
+
+		i.routes = Routes{}
+
+		for endpoint, path := range routes {
+			i.routes[endpoint] = path
+		}
+
+		return nil
+	}
+}
+
+// Routes - served endpoints in the order of the default paths.
+func (i *Inspector) Routes() []Route {
+	list := make([]Route, 0, len(defaultPaths))
+
+	for _, endpoint := range defaultPaths {
+		path, ok := i.routes[endpoint]
+		if !ok {
+			path = endpoint
+		}
+
+		if path == "" {
+			continue
+		}
+
+		list = append(list, Route{Endpoint: endpoint, Path: path, Group: endpointGroups[endpoint]})
+	}
+
+	return list
+}
+
+// endpointGroups - probe groups of the probe endpoints.
+var endpointGroups = map[string]ProbeGroup{
+	PathStartup: GroupStartup,
+	PathLive:    GroupLive,
+	PathReady:   GroupReady,
+}
+
+// RegisterRoutes - registers the endpoints on the mux by the routes (see WithRoutes),
+// for applications serving health endpoints on own mux.
+func (i *Inspector) RegisterRoutes(mux *http.ServeMux) {
+	handlers := map[string]http.Handler{
+		PathStartup:  i.HealthHandler(GroupStartup, false, nil),
+		PathLive:     i.HealthHandler(GroupLive, false, nil),
+		PathReady:    i.HealthHandler(GroupReady, true, nil),
+		PathScopes:   i.ScopesHandler(),
+		PathStatus:   i.StatusHandler(),
+		PathSchedule: i.ScheduleHandler(),
+		PathGraph:    i.GraphHandler(),
+	}
+
+	for _, route := range i.Routes() {
+		mux.Handle(route.Path, handlers[route.Endpoint])
+	}
+}
+
+// routePath - path the endpoint is served on, given by its default or routed path.
+func (i *Inspector) routePath(path string) (string, bool) {
+	for _, route := range i.Routes() {
+		if route.Endpoint == path || route.Path == path {
+			return route.Path, true
+		}
+	}
+
+	return "", false
+}
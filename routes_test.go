@@ -0,0 +1,73 @@
+package healthz
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithRoutes(t *testing.T) {
+	tests := []struct {
+		name    string
+		routes  Routes
+		wantErr error
+	}{
+		{name: "test.1 kubernetes", routes: KubernetesRoutes},
+		{name: "test.2 disabled", routes: Routes{PathGraph: ""}},
+		{name: "test.3 unknown endpoint", routes: Routes{"/healthz": "/health"}, wantErr: errUnknownPath},
+		{name: "test.4 relative path", routes: Routes{PathLive: "livez"}, wantErr: errWrongRoute},
+		{name: "test.5 duplicate path", routes: Routes{PathLive: "/probe", PathReady: "/probe"}, wantErr: errWrongRoute},
+		{name: "test.6 taken default path", routes: Routes{PathLive: PathReady}, wantErr: errWrongRoute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := WithRoutes(tt.routes)(New())
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestInspector_Routes(t *testing.T) {
+	inspector := New(HealthCheckTarget{Service: &mockService{scope: "db", dest: "pg"}, Groups: AllGroups})
+	assert.NoError(t, WithRoutes(Routes{PathStartup: "/startupz", PathLive: "/livez", PathReady: "/readyz", PathGraph: ""})(inspector))
+
+	inspector.check(context.Background())
+
+	routes := inspector.Routes()
+	if assert.Len(t, routes, len(defaultPaths)-1) {
+		assert.Equal(t, Route{Endpoint: PathReady, Path: "/readyz", Group: GroupReady}, routes[2])
+		assert.Equal(t, Route{Endpoint: PathScopes, Path: PathScopes}, routes[3])
+	}
+
+	handler := inspector.Handler()
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{path: "/startupz", want: http.StatusOK},
+		{path: "/livez", want: http.StatusOK},
+		{path: "/readyz", want: http.StatusOK},
+		{path: PathStatus, want: http.StatusOK},
+		{path: PathLive, want: http.StatusNotFound},
+		{path: PathGraph, want: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			assert.Equal(t, tt.want, w.Code)
+		})
+	}
+
+	_, err := NewMultiServer(inspector, ProbeListener{Addr: ":0", Paths: []string{PathLive, "/readyz"}})
+	assert.NoError(t, err, "listeners accept default and routed paths")
+
+	_, err = NewMultiServer(inspector, ProbeListener{Addr: ":0", Paths: []string{PathGraph}})
+	assert.ErrorIs(t, err, errUnknownPath, "disabled endpoint")
+}
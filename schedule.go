@@ -0,0 +1,150 @@
+package healthz
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// Reasons of the target schedule.
+const (
+	ScheduleNotStarted = "not started" // inspector isn't started, checks aren't run
+	SchedulePeriodic   = "periodic"    // checked every check cycle
+	ScheduleAdaptive   = "adaptive"    // period adapts to stability of the target, see WithAdaptiveSchedule
+	ScheduleOwnPeriod  = "own period"  // checked by own period of the target, see HealthCheckTarget.Period
+	ScheduleBackoff    = "backoff"     // failing target is checked less frequently, see WithBackoff
+	ScheduleOnDemand   = "on demand"   // checked by probes not more often than the period, see WithOnDemandChecks
+	ScheduleStopped    = "stopped"     // inspector is stopping or stopped
+)
+
+// TargetSchedule - effective schedule of the target checks.
+type TargetSchedule struct {
+	Scope   string
+	Dest    string
+	LastRun time.Time     // start of the cycle of the last check, zero if never checked
+	NextRun time.Time     // next planned check, zero if none
+	Period  time.Duration // effective check period
+	Reason  string        // explains NextRun, e.g. SchedulePeriodic
+}
+
+func (ts TargetSchedule) MarshalJSON() ([]byte, error) {
+	view := struct {
+		Scope   string     `json:"scope"`
+		Dest    string     `json:"dest"`
+		LastRun *time.Time `json:"lastRun,omitempty"`
+		NextRun *time.Time `json:"nextRun,omitempty"`
+		Period  float64    `json:"periodSeconds"`
+		Reason  string     `json:"reason"`
+	}{
+		Scope:  ts.Scope,
+		Dest:   ts.Dest,
+		Period: ts.Period.Seconds(),
+		Reason: ts.Reason,
+	}
+
+	if !ts.LastRun.IsZero() {
+		view.LastRun = &ts.LastRun
+	}
+
+	if !ts.NextRun.IsZero() {
+		view.NextRun = &ts.NextRun
+	}
+
+	return json.Marshal(view)
+}
+
+// Schedule - next run of every target, to verify why a target hasn't been checked recently.
+func (i *Inspector) Schedule() []TargetSchedule {
+	if i.parent != nil {
+		var list []TargetSchedule
+
+		for _, ts := range i.parent.Schedule() {
+			if ts.Scope == i.scope {
+				list = append(list, ts)
+			}
+		}
+
+		return list
+	}
+
+	i.mu.RLock()
+	targets := i.targets
+	period := i.checkPeriod
+	i.mu.RUnlock()
+
+	status := i.Status()
+	res := i.get()
+
+	lastRun := make(map[string]time.Time, len(res.targets))
+
+	for _, tr := range res.targets {
+		lastRun[targetKey(tr.Scope, tr.Dest)] = tr.CheckedAt
+	}
+
+	reason := ScheduleNotStarted
+
+	switch {
+	case status.State == StateStopping, status.State == StateStopped:
+		reason = ScheduleStopped
+	case status.State == StateRunning && i.onDemand != nil:
+		reason = ScheduleOnDemand
+	case status.State == StateRunning && i.adaptive.min > 0:
+		reason = ScheduleAdaptive
+	case status.State == StateRunning:
+		reason = SchedulePeriodic
+	}
+
+	nextRun := status.NextCycle
+
+	switch {
+	case i.onDemand != nil:
+		period = i.onDemand.interval
+		nextRun = time.Now().Add(i.refreshIn())
+	case i.adaptive.min > 0:
+		period = i.adaptive.min
+	}
+
+	list := make([]TargetSchedule, 0, len(targets))
+
+	for _, target := range targets {
+		scope, dest := target.Service.Scope(), target.Service.Dest()
+
+		ts := TargetSchedule{
+			Scope:   scope,
+			Dest:    dest,
+			LastRun: lastRun[targetKey(scope, dest)],
+			NextRun: nextRun,
+			Period:  period,
+			Reason:  reason,
+		}
+
+		if reason != ScheduleAdaptive && reason != SchedulePeriodic {
+			list = append(list, ts)
+
+			continue
+		}
+
+		if target.Period > 0 {
+			ts.Period, ts.Reason = target.Period, ScheduleOwnPeriod
+		}
+
+		if p, ok := i.paceOf(scope, dest); ok {
+			ts.NextRun, ts.Period = p.next, p.period
+
+			if p.failures > 1 {
+				ts.Reason = ScheduleBackoff
+			}
+		}
+
+		list = append(list, ts)
+	}
+
+	return list
+}
+
+// ScheduleHandler - serves the schedule as JSON (or by a negotiated codec), e.g. on /healthz/schedule.
+func (i *Inspector) ScheduleHandler() http.Handler {
+	return Gzip(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeEncoded(w, r, http.StatusOK, i.Schedule())
+	}))
+}
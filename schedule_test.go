@@ -0,0 +1,54 @@
+package healthz
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchedule(t *testing.T) {
+	inspector := New(
+		HealthCheckTarget{Service: &mockService{scope: "db", dest: "pg"}, Groups: GroupReady},
+		HealthCheckTarget{Service: &mockService{scope: "cache", dest: "redis"}, Groups: GroupLive},
+	)
+	assert.NoError(t, WithCheckPeriod(time.Hour)(inspector))
+
+	schedule := inspector.Schedule()
+	assert.Len(t, schedule, 2)
+	assert.Equal(t, ScheduleNotStarted, schedule[0].Reason)
+	assert.True(t, schedule[0].LastRun.IsZero())
+	assert.True(t, schedule[0].NextRun.IsZero())
+
+	assert.NoError(t, inspector.Start(context.Background()))
+
+	assert.Eventually(t, func() bool {
+		return !inspector.Schedule()[1].LastRun.IsZero()
+	}, testTimeout, time.Millisecond)
+
+	schedule = inspector.Snapshot().Schedule
+	assert.Equal(t, SchedulePeriodic, schedule[1].Reason)
+	assert.Equal(t, time.Hour, schedule[1].Period)
+	assert.True(t, schedule[1].NextRun.After(schedule[1].LastRun))
+
+	child, err := inspector.Child("cache")
+	assert.NoError(t, err)
+	assert.Len(t, child.Schedule(), 1)
+
+	w := httptest.NewRecorder()
+	child.ScheduleHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, PathSchedule, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var body []map[string]any
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "redis", body[0]["dest"])
+	assert.Equal(t, float64(3600), body[0]["periodSeconds"])
+	assert.Contains(t, body[0], "nextRun")
+
+	assert.NoError(t, inspector.Stop(context.Background()))
+	assert.Equal(t, ScheduleStopped, inspector.Schedule()[0].Reason)
+}
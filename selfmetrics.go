@@ -0,0 +1,146 @@
+package healthz
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	errMissRegisterer = errors.New("missing prometheus registerer")
+	errWrongNamespace = errors.New("incorrect metric namespace")
+)
+
+// WithMetricNamespace - prefixes names of the metrics registered by the inspector itself
+// (see WithSelfMetrics) with the namespace, e.g. "api" gives api_healthz_cycles_total,
+// so several inspectors could share a registry. Must precede WithSelfMetrics.
+func WithMetricNamespace(ns string) Option {
+	return func(i *Inspector) error {
+		if !validMetricName(ns) {
+			return fmt.Errorf("%w: %q", errWrongNamespace, ns)
+		}
+
+		i.namespace = ns
+
+		return nil
+	}
+}
+
+// validMetricName - reports whether s matches [a-zA-Z_][a-zA-Z0-9_]*.
+func validMetricName(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for n, r := range s {
+		switch {
+		case r == '_', r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		case r >= '0' && r <= '9' && n > 0:
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// WithSelfMetrics - registers metrics of the inspector itself (monitoring of the monitor),
+// names are prefixed by WithMetricNamespace if set, a conflict is reported as *MetricError:
+//
+//	healthz_cycle_duration_seconds - histogram of check cycle durations
+//	healthz_cycles_total           - check cycles run
+//	healthz_cycles_skipped_total   - cycles skipped because the previous one overlapped the period
+//	healthz_check_workers          - checks in flight (including late ones after the cycle timeout)
+//	healthz_targets                - configured targets
+//	healthz_history_entries        - entries in the history store (see HistoryPruner)
+//	healthz_history_pruned_total   - history entries removed by the retention (see WithHistoryRetention)
+func WithSelfMetrics(reg prometheus.Registerer) Option {
+	return func(i *Inspector) error {
+		if reg == nil {
+			return errMissRegisterer
+		}
+
+		ns := i.namespace
+		name := func(n string) string { return prometheus.BuildFQName(ns, InternalScope, n) }
+
+		cycleDuration := prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: ns,
+			Subsystem: InternalScope,
+			Name:      "cycle_duration_seconds",
+			Help:      "Duration of health check cycles.",
+			Buckets:   prometheus.DefBuckets,
+		})
+
+		err := register(reg,
+			namedCollector{name("cycle_duration_seconds"), cycleDuration},
+			namedCollector{name("cycles_total"), prometheus.NewCounterFunc(prometheus.CounterOpts{
+				Namespace: ns,
+				Subsystem: InternalScope,
+				Name:      "cycles_total",
+				Help:      "Health check cycles run.",
+			}, func() float64 { return float64(i.cycles.Load()) })},
+			namedCollector{name("cycles_skipped_total"), prometheus.NewCounterFunc(prometheus.CounterOpts{
+				Namespace: ns,
+				Subsystem: InternalScope,
+				Name:      "cycles_skipped_total",
+				Help:      "Health check cycles skipped because the previous cycle overlapped the check period.",
+			}, func() float64 { return float64(i.skippedCycles.Load()) })},
+			namedCollector{name("check_workers"), prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+				Namespace: ns,
+				Subsystem: InternalScope,
+				Name:      "check_workers",
+				Help:      "Health checks in flight.",
+			}, func() float64 { return float64(i.inFlight.Load()) })},
+			namedCollector{name("targets"), prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+				Namespace: ns,
+				Subsystem: InternalScope,
+				Name:      "targets",
+				Help:      "Configured health check targets.",
+			}, func() float64 {
+				i.mu.RLock()
+				defer i.mu.RUnlock()
+
+				return float64(len(i.targets))
+			})},
+			namedCollector{name("history_entries"), prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+				Namespace: ns,
+				Subsystem: InternalScope,
+				Name:      "history_entries",
+				Help:      "Entries in the history store.",
+			}, func() float64 { return float64(i.historyLen()) })},
+			namedCollector{name("history_pruned_total"), prometheus.NewCounterFunc(prometheus.CounterOpts{
+				Namespace: ns,
+				Subsystem: InternalScope,
+				Name:      "history_pruned_total",
+				Help:      "History entries removed by the retention policy.",
+			}, func() float64 {
+				if i.retention == nil {
+					return 0
+				}
+
+				return float64(i.retention.pruned.Load())
+			})},
+		)
+		if err != nil {
+			return err
+		}
+
+		i.cycleDuration = cycleDuration
+
+		return nil
+	}
+}
+
+// observeCycle - accounts the finished cycle, ticks dropped by the ticker while
+// the cycle was running are the skipped cycles.
+func (i *Inspector) observeCycle(duration, period time.Duration) {
+	if i.cycleDuration != nil {
+		i.cycleDuration.Observe(duration.Seconds())
+	}
+
+	if period > 0 && duration > period {
+		i.skippedCycles.Add(uint64(duration / period))
+	}
+}
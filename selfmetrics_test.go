@@ -0,0 +1,104 @@
+package healthz
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelfMetrics(t *testing.T) {
+	release := make(chan struct{})
+
+	inspector := New(
+		HealthCheckTarget{Service: &mockService{dest: "fast"}, Groups: GroupLive},
+		HealthCheckTarget{Service: &mockService{dest: "slow", callBack: func() { <-release }}, Groups: GroupLive},
+	)
+
+	reg := prometheus.NewRegistry()
+	assert.NoError(t, WithSelfMetrics(reg)(inspector))
+	assert.NoError(t, WithCycleTimeout(30*time.Millisecond)(inspector))
+
+	inspector.timedCheck(context.Background(), 10*time.Millisecond)
+
+	values := gatherValues(t, reg)
+	assert.Equal(t, 1.0, values["healthz_cycles_total"])
+	assert.GreaterOrEqual(t, values["healthz_cycles_skipped_total"], 2.0, "30ms cycle of 10ms period")
+	assert.Equal(t, 1.0, values["healthz_check_workers"], "late check is still running")
+	assert.Equal(t, 2.0, values["healthz_targets"])
+	assert.Equal(t, 1.0, values["healthz_cycle_duration_seconds"], "observations count")
+
+	close(release)
+
+	assert.Eventually(t, func() bool {
+		return gatherValues(t, reg)["healthz_check_workers"] == 0
+	}, testTimeout, time.Millisecond)
+
+	var (
+		metricErr *MetricError
+		already   prometheus.AlreadyRegisteredError
+	)
+
+	err := WithSelfMetrics(reg)(New())
+	assert.ErrorIs(t, err, ErrMetricConflict)
+	assert.ErrorAs(t, err, &already)
+
+	if assert.ErrorAs(t, err, &metricErr) {
+		assert.Equal(t, "healthz_cycle_duration_seconds", metricErr.Metric)
+	}
+
+	assert.ErrorIs(t, WithSelfMetrics(nil)(New()), errMissRegisterer)
+}
+
+func TestWithMetricNamespace(t *testing.T) {
+	reg := prometheus.NewRegistry()
+
+	assert.NoError(t, WithSelfMetrics(reg)(New()))
+
+	second := New()
+	assert.NoError(t, WithMetricNamespace("api")(second))
+	assert.NoError(t, WithSelfMetrics(reg)(second))
+
+	values := gatherValues(t, reg)
+	assert.Contains(t, values, "healthz_cycles_total")
+	assert.Contains(t, values, "api_healthz_cycles_total")
+
+	// partially registered metrics are rolled back
+	assert.NoError(t, reg.Register(prometheus.NewGauge(prometheus.GaugeOpts{Name: "web_healthz_targets", Help: "Taken."})))
+
+	third := New()
+	assert.NoError(t, WithMetricNamespace("web")(third))
+
+	err := WithSelfMetrics(reg)(third)
+	assert.ErrorIs(t, err, ErrMetricConflict)
+	assert.NotContains(t, gatherValues(t, reg), "web_healthz_cycles_total")
+
+	for _, ns := range []string{"", "1api", "api-v2"} {
+		assert.ErrorIs(t, WithMetricNamespace(ns)(New()), errWrongNamespace, ns)
+	}
+}
+
+// gatherValues - value of every metric, sample count for histograms.
+func gatherValues(t *testing.T, reg *prometheus.Registry) map[string]float64 {
+	families, err := reg.Gather()
+	assert.NoError(t, err)
+
+	values := make(map[string]float64)
+
+	for _, mf := range families {
+		m := mf.GetMetric()[0]
+
+		switch {
+		case m.GetCounter() != nil:
+			values[mf.GetName()] = m.GetCounter().GetValue()
+		case m.GetGauge() != nil:
+			values[mf.GetName()] = m.GetGauge().GetValue()
+		case m.GetHistogram() != nil:
+			values[mf.GetName()] = float64(m.GetHistogram().GetSampleCount())
+		}
+	}
+
+	return values
+}
@@ -0,0 +1,85 @@
+package healthz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+)
+
+// Default paths of the health endpoints.
+const (
+	PathStartup  = "/healthz/startup"
+	PathLive     = "/healthz/live"
+	PathReady    = "/healthz/ready"
+	PathScopes   = "/healthz/scopes"
+	PathStatus   = "/healthz/status"
+	PathSchedule = "/healthz/schedule"
+	PathGraph    = "/healthz/graph"
+)
+
+// Handler - default health endpoints: startup and live pass if any target is healthy,
+// ready needs all targets healthy. Paths could be changed by WithRoutes.
+func (i *Inspector) Handler() http.Handler {
+	mux := http.NewServeMux()
+	i.RegisterRoutes(mux)
+
+	return mux
+}
+
+// Server - standalone http server of the health endpoints.
+type Server struct {
+	Addr         string
+	PortFallback bool                // listen on a free port when Addr is busy, for local development
+	OnListen     func(addr net.Addr) // reports the address actually listened
+
+	srv *http.Server
+}
+
+// NewServer - server of the default inspector endpoints (see Inspector.Handler).
+func NewServer(addr string, inspector *Inspector) *Server {
+	return &Server{
+		Addr: addr,
+		srv:  &http.Server{Handler: inspector.Handler()},
+	}
+}
+
+// ListenAndServe - listens Addr (or a free port, see PortFallback) and serves until Shutdown.
+func (s *Server) ListenAndServe() error {
+	ln, err := s.listen()
+	if err != nil {
+		return err
+	}
+
+	if s.OnListen != nil {
+		s.OnListen(ln.Addr())
+	}
+
+	return s.srv.Serve(ln)
+}
+
+// Shutdown - gracefully shutdowns the server, see http.Server.Shutdown.
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.srv.Shutdown(ctx)
+}
+
+func (s *Server) listen() (net.Listener, error) {
+	ln, err := net.Listen("tcp", s.Addr)
+	if err == nil || !s.PortFallback || !errors.Is(err, syscall.EADDRINUSE) {
+		return ln, err
+	}
+
+	host, _, splitErr := net.SplitHostPort(s.Addr)
+	if splitErr != nil {
+		return nil, err
+	}
+
+	ln, fallbackErr := net.Listen("tcp", net.JoinHostPort(host, "0"))
+	if fallbackErr != nil {
+		return nil, fmt.Errorf("%w, fallback: %w", err, fallbackErr)
+	}
+
+	return ln, nil
+}
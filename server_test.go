@@ -0,0 +1,75 @@
+package healthz
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInspectorHandler(t *testing.T) {
+	inspector := New(HealthCheckTarget{Service: &mockService{scope: "db", dest: "pg"}, Groups: AllGroups})
+	inspector.check(context.Background())
+
+	handler := inspector.Handler()
+
+	for _, path := range []string{PathStartup, PathLive, PathReady, PathScopes} {
+		t.Run(path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+			assert.Equal(t, http.StatusOK, w.Code)
+		})
+	}
+}
+
+func TestServer_PortFallback(t *testing.T) {
+	busy, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer busy.Close()
+
+	inspector := New(HealthCheckTarget{Service: &mockService{}, Groups: GroupLive})
+	inspector.check(context.Background())
+
+	t.Run("Busy port without fallback", func(t *testing.T) {
+		srv := NewServer(busy.Addr().String(), inspector)
+
+		err := srv.ListenAndServe()
+		assert.ErrorIs(t, err, syscall.EADDRINUSE)
+	})
+
+	t.Run("Busy port with fallback", func(t *testing.T) {
+		srv := NewServer(busy.Addr().String(), inspector)
+		srv.PortFallback = true
+
+		chosen := make(chan net.Addr, 1)
+		srv.OnListen = func(addr net.Addr) { chosen <- addr }
+
+		go srv.ListenAndServe()
+
+		var addr net.Addr
+
+		select {
+		case addr = <-chosen:
+		case <-time.After(testTimeout):
+			t.Fatal("server didn't listen")
+		}
+
+		assert.NotEqual(t, busy.Addr().String(), addr.String())
+
+		resp, err := http.Get(fmt.Sprintf("http://%s%s", addr, PathLive))
+		assert.NoError(t, err)
+
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		assert.Equal(t, "OK", string(body))
+
+		assert.NoError(t, srv.Shutdown(context.Background()))
+	})
+}
@@ -0,0 +1,178 @@
+package healthz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	errShuttingDown       = errors.New("shutting down")
+	errWrongShutdownDelay = errors.New("incorrect shutdown delay")
+)
+
+// WithShutdownDelay - sets how long BeginShutdown (and Stop) waits after readiness
+// was flipped to unhealthy, giving load balancers time to notice it.
+func WithShutdownDelay(d time.Duration) Option {
+	return func(i *Inspector) error {
+		if d < 0 {
+			return errWrongShutdownDelay
+		}
+
+		i.shutdownDelay = d
+
+		return nil
+	}
+}
+
+// BeginShutdown - marks the ready group unhealthy (live stays as is) and waits
+// for the configured shutdown delay or until ctx is done.
+// Repeated calls don't wait again.
+func (i *Inspector) BeginShutdown(ctx context.Context) error {
+	if !i.markShuttingDown() {
+		return nil
+	}
+
+	i.mu.RLock()
+	delay := i.shutdownDelay
+	i.mu.RUnlock()
+
+	return sleepCtx(ctx, delay)
+}
+
+// markShuttingDown - flips readiness and notifies subscribers, returns false if it has been already flipped.
+func (i *Inspector) markShuttingDown() bool {
+	i.state.CompareAndSwap(int32(StateRunning), int32(StateStopping))
+
+	if !i.shuttingDown.CompareAndSwap(false, true) {
+		return false
+	}
+
+	i.notifyShutdown()
+
+	return true
+}
+
+// OnStopping - adds a hook run by Stop after readiness was flipped (and the shutdown delay passed)
+// but before checks stop, e.g. to drain queues and connections while dependencies are still watched.
+// Hooks run once per Stop in the order of adding, all of them run even if some fail.
+// Hooks of a child are added to its parent.
+func (i *Inspector) OnStopping(hook func(ctx context.Context) error) {
+	if i.parent != nil {
+		i.parent.OnStopping(hook)
+
+		return
+	}
+
+	i.stoppingMu.Lock()
+	defer i.stoppingMu.Unlock()
+
+	i.stopping = append(i.stopping, hook)
+}
+
+// runStopping - runs OnStopping hooks if they haven't been run since Start.
+func (i *Inspector) runStopping(ctx context.Context) error {
+	if !i.stoppingDone.CompareAndSwap(false, true) {
+		return nil
+	}
+
+	i.stoppingMu.Lock()
+	hooks := i.stopping
+	i.stoppingMu.Unlock()
+
+	var errs []error
+
+	for n, hook := range hooks {
+		if err := hook(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("stopping hook #%d: %w", n, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// ShuttingDown - reports whether BeginShutdown or Stop has been called.
+func (i *Inspector) ShuttingDown() bool {
+	return i.shuttingDown.Load()
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Shutdowner - server which could be gracefully stopped, e.g. *http.Server.
+type Shutdowner interface {
+	Shutdown(ctx context.Context) error
+}
+
+// GracefulStopper - subset of *grpc.Server methods used for shutdown.
+type GracefulStopper interface {
+	GracefulStop()
+	Stop()
+}
+
+// GRPCShutdowner - adapts a grpc server to Shutdowner: GracefulStop until ctx is done, then Stop.
+func GRPCShutdowner(srv GracefulStopper) Shutdowner {
+	return grpcShutdowner{srv: srv}
+}
+
+type grpcShutdowner struct {
+	srv GracefulStopper
+}
+
+func (gs grpcShutdowner) Shutdown(ctx context.Context) error {
+	done := make(chan struct{})
+
+	go func() {
+		gs.srv.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		gs.srv.Stop()
+
+		return ctx.Err()
+	}
+}
+
+// GracefulShutdown - the standard graceful termination sequence:
+// flips readiness, waits delay for endpoint propagation, stops the inspector (running its OnStopping hooks)
+// and then shuts down servers in the given order.
+// All servers are shut down even if some of them fail.
+func GracefulShutdown(ctx context.Context, inspector *Inspector, delay time.Duration, servers ...Shutdowner) error {
+	inspector.markShuttingDown()
+
+	var errs []error
+
+	if err := sleepCtx(ctx, delay); err != nil {
+		errs = append(errs, fmt.Errorf("propagation delay: %w", err))
+	}
+
+	if err := inspector.Stop(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("stop inspector: %w", err))
+	}
+
+	for n, srv := range servers {
+		if err := srv.Shutdown(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("shutdown server #%d: %w", n, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,182 @@
+package healthz
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBeginShutdown(t *testing.T) {
+	t.Run("Ready flips, live stays", func(t *testing.T) {
+		svc := &mockService{}
+		inspector := New(HealthCheckTarget{Service: svc, Groups: GroupLive | GroupReady})
+		inspector.check(context.Background())
+
+		assert.NoError(t, inspector.CheckGroup(GroupReady, true))
+
+		assert.NoError(t, inspector.BeginShutdown(context.Background()))
+		assert.True(t, inspector.ShuttingDown())
+		assert.ErrorIs(t, inspector.CheckGroup(GroupReady, true), errShuttingDown)
+		assert.NoError(t, inspector.CheckGroup(GroupLive, true))
+	})
+
+	t.Run("Waits for delay once", func(t *testing.T) {
+		inspector := New()
+		assert.NoError(t, WithShutdownDelay(20*time.Millisecond)(inspector))
+
+		start := time.Now()
+		assert.NoError(t, inspector.BeginShutdown(context.Background()))
+		assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+
+		start = time.Now()
+		assert.NoError(t, inspector.BeginShutdown(context.Background()))
+		assert.Less(t, time.Since(start), 20*time.Millisecond)
+	})
+
+	t.Run("Delay interrupted by context", func(t *testing.T) {
+		inspector := New()
+		assert.NoError(t, WithShutdownDelay(testTimeout)(inspector))
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+		defer cancel()
+
+		err := inspector.BeginShutdown(ctx)
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	})
+
+	t.Run("Stop flips readiness", func(t *testing.T) {
+		inspector := New()
+		assert.NoError(t, inspector.Start(context.Background()))
+		assert.NoError(t, inspector.Stop(context.Background()))
+		assert.ErrorIs(t, inspector.CheckGroup(GroupReady, false), errShuttingDown)
+	})
+
+	t.Run("Wrong delay", func(t *testing.T) {
+		assert.Error(t, WithShutdownDelay(-time.Second)(New()))
+	})
+}
+
+type mockShutdowner struct {
+	err    error
+	called func()
+}
+
+func (m *mockShutdowner) Shutdown(context.Context) error {
+	if m.called != nil {
+		m.called()
+	}
+
+	return m.err
+}
+
+type mockGRPCServer struct {
+	block   chan struct{}
+	stopped bool
+}
+
+func (m *mockGRPCServer) GracefulStop() {
+	<-m.block
+}
+
+func (m *mockGRPCServer) Stop() {
+	m.stopped = true
+	close(m.block)
+}
+
+func TestGracefulShutdown(t *testing.T) {
+	t.Run("Order of steps", func(t *testing.T) {
+		svc := &mockService{}
+		inspector := New(HealthCheckTarget{Service: svc, Groups: GroupReady})
+		assert.NoError(t, inspector.Start(context.Background()))
+
+		var order []string
+
+		first := &mockShutdowner{called: func() {
+			assert.True(t, inspector.ShuttingDown())
+			order = append(order, "first")
+		}}
+		second := &mockShutdowner{called: func() { order = append(order, "second") }}
+
+		start := time.Now()
+		err := GracefulShutdown(context.Background(), inspector, 10*time.Millisecond, first, second)
+		assert.NoError(t, err)
+		assert.GreaterOrEqual(t, time.Since(start), 10*time.Millisecond)
+		assert.Equal(t, []string{"first", "second"}, order)
+	})
+
+	t.Run("Errors are joined", func(t *testing.T) {
+		errFirst := errors.New("first")
+		second := &mockShutdowner{}
+
+		err := GracefulShutdown(context.Background(), New(), 0, &mockShutdowner{err: errFirst}, second)
+		assert.ErrorIs(t, err, errFirst)
+	})
+
+	t.Run("gRPC server is stopped on timeout", func(t *testing.T) {
+		srv := &mockGRPCServer{block: make(chan struct{})}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+		defer cancel()
+
+		err := GRPCShutdowner(srv).Shutdown(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.True(t, srv.stopped)
+	})
+}
+
+func TestOnStopping(t *testing.T) {
+	inspector := New(HealthCheckTarget{Service: &mockService{}, Groups: GroupLive | GroupReady})
+	child, err := inspector.Child("scope")
+	assert.NoError(t, err)
+
+	var order []string
+
+	inspector.OnStopping(func(context.Context) error {
+		order = append(order, "first")
+
+		assert.ErrorIs(t, inspector.CheckGroup(GroupReady, true), errShuttingDown)
+		assert.Equal(t, StateStopping, inspector.Status().State)
+
+		return errors.New("drain failed")
+	})
+	child.OnStopping(func(context.Context) error {
+		order = append(order, "second")
+
+		return nil
+	})
+
+	assert.NoError(t, inspector.Start(context.Background()))
+
+	err = inspector.Stop(context.Background())
+	assert.ErrorContains(t, err, "drain failed")
+	assert.Equal(t, []string{"first", "second"}, order)
+	assert.Equal(t, StateStopped, inspector.Status().State)
+
+	assert.NoError(t, inspector.Stop(context.Background()))
+	assert.Len(t, order, 2)
+}
+
+func TestStop_canceledDelay(t *testing.T) {
+	inspector := New(HealthCheckTarget{Service: &mockService{}, Groups: GroupReady})
+	assert.NoError(t, WithShutdownDelay(time.Hour)(inspector))
+
+	hooked := false
+	inspector.OnStopping(func(context.Context) error {
+		hooked = true
+
+		return nil
+	})
+
+	assert.NoError(t, inspector.Start(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := inspector.Stop(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.True(t, hooked, "hooks run on every path")
+	assert.Equal(t, StateStopped, inspector.Status().State, "the loop is stopped")
+}
@@ -0,0 +1,140 @@
+package healthz
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+var errWrongSilence = errors.New("silence must end in the future")
+
+// Silence - active silence of the target notifications, see Inspector.Silence.
+type Silence struct {
+	Scope     string    `json:"scope"`
+	Dest      string    `json:"dest"`
+	Until     time.Time `json:"until"`
+	Reason    string    `json:"reason,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type silences struct {
+	mu   sync.Mutex
+	list map[string]Silence // keyed by "scope/dest"
+}
+
+// activeLocked - silences not expired at now, expired ones are dropped, mu must be held.
+func (s *silences) activeLocked(now time.Time) map[string]Silence {
+	for key, silence := range s.list {
+		if !now.Before(silence.Until) {
+			delete(s.list, key)
+		}
+	}
+
+	return s.list
+}
+
+// Silence - suppresses notifications of the target state changes (webhook, Subscribe, WithOnStateChange)
+// till the time, e.g. during planned works of the dependency. Status reporting isn't affected:
+// probes, snapshot and metrics follow the checks, transitions of probe groups are notified as usual.
+// Silencing the silenced target replaces its silence. Child silences targets of its scope only.
+func (i *Inspector) Silence(scope, dest string, until time.Time, reason string) error {
+	if i.parent != nil {
+		if scope != i.scope {
+			return fmt.Errorf("%w: %s", errForeignScope, scope)
+		}
+
+		return i.parent.Silence(scope, dest, until, reason)
+	}
+
+	now := time.Now()
+	if !until.After(now) {
+		return errWrongSilence
+	}
+
+	if !i.registeredTarget(scope, dest) {
+		return fmt.Errorf("%w: %s", errUnregisteredTarget, targetKey(scope, dest))
+	}
+
+	i.silences.mu.Lock()
+	defer i.silences.mu.Unlock()
+
+	if i.silences.list == nil {
+		i.silences.list = make(map[string]Silence)
+	}
+
+	i.silences.list[targetKey(scope, dest)] = Silence{Scope: scope, Dest: dest, Until: until, Reason: reason, CreatedAt: now}
+
+	return nil
+}
+
+// Unsilence - ends the silence of the target early, reports whether it was silenced.
+func (i *Inspector) Unsilence(scope, dest string) bool {
+	if i.parent != nil {
+		return scope == i.scope && i.parent.Unsilence(scope, dest)
+	}
+
+	i.silences.mu.Lock()
+	defer i.silences.mu.Unlock()
+
+	key := targetKey(scope, dest)
+
+	_, ok := i.silences.activeLocked(time.Now())[key]
+	delete(i.silences.list, key)
+
+	return ok
+}
+
+// Silences - active silences ordered by scope and dest.
+func (i *Inspector) Silences() []Silence {
+	if i.parent != nil {
+		var list []Silence
+
+		for _, silence := range i.parent.Silences() {
+			if silence.Scope == i.scope {
+				list = append(list, silence)
+			}
+		}
+
+		return list
+	}
+
+	i.silences.mu.Lock()
+	defer i.silences.mu.Unlock()
+
+	var list []Silence
+
+	for _, silence := range i.silences.activeLocked(time.Now()) {
+		list = append(list, silence)
+	}
+
+	slices.SortFunc(list, func(a, b Silence) int {
+		return strings.Compare(targetKey(a.Scope, a.Dest), targetKey(b.Scope, b.Dest))
+	})
+
+	return list
+}
+
+// unsilenced - the changes of targets not silenced at their time.
+func (i *Inspector) unsilenced(changes []Change) []Change {
+	i.silences.mu.Lock()
+	defer i.silences.mu.Unlock()
+
+	if len(i.silences.list) == 0 {
+		return changes
+	}
+
+	list := make([]Change, 0, len(changes))
+
+	for _, c := range changes {
+		if silence, ok := i.silences.list[targetKey(c.Scope, c.Dest)]; ok && c.Time.Before(silence.Until) {
+			continue
+		}
+
+		list = append(list, c)
+	}
+
+	return list
+}
@@ -0,0 +1,114 @@
+package healthz
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInspector_Silence(t *testing.T) {
+	tests := []struct {
+		name    string
+		scope   string
+		dest    string
+		until   time.Time
+		wantErr error
+	}{
+		{name: "test.1 ok", scope: "db", dest: "pg", until: time.Now().Add(time.Hour)},
+		{name: "test.2 past", scope: "db", dest: "pg", until: time.Now().Add(-time.Second), wantErr: errWrongSilence},
+		{name: "test.3 unknown", scope: "db", dest: "mysql", until: time.Now().Add(time.Hour), wantErr: errUnregisteredTarget},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inspector := New(HealthCheckTarget{Service: &mockService{scope: "db", dest: "pg"}, Groups: GroupReady})
+
+			err := inspector.Silence(tt.scope, tt.dest, tt.until, "planned works")
+			assert.ErrorIs(t, err, tt.wantErr)
+
+			if tt.wantErr != nil {
+				assert.Empty(t, inspector.Silences())
+
+				return
+			}
+
+			silences := inspector.Silences()
+			if assert.Len(t, silences, 1) {
+				assert.Equal(t, "planned works", silences[0].Reason)
+				assert.Equal(t, tt.until, silences[0].Until)
+			}
+		})
+	}
+}
+
+func TestInspector_Silence_notifications(t *testing.T) {
+	pg := &mockService{scope: "db", dest: "pg"}
+	redis := &mockService{scope: "cache", dest: "redis"}
+
+	var (
+		mu     sync.Mutex
+		events []StateChange
+	)
+
+	inspector := New(
+		HealthCheckTarget{Service: pg, Groups: GroupReady},
+		HealthCheckTarget{Service: redis, Groups: GroupLive},
+	)
+	assert.NoError(t, WithOnStateChange(func(sc StateChange) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		events = append(events, sc)
+	})(inspector))
+
+	targetEvents := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+
+		var list []string
+
+		for _, sc := range events {
+			if sc.Group == 0 {
+				list = append(list, targetKey(sc.Scope, sc.Dest))
+			}
+		}
+
+		events = nil
+
+		return list
+	}
+
+	inspector.check(context.Background())
+	targetEvents()
+
+	assert.NoError(t, inspector.Silence("db", "pg", time.Now().Add(time.Hour), "migration"))
+
+	pg.healthErr, redis.healthErr = errors.New("down"), errors.New("down")
+	inspector.check(context.Background())
+
+	assert.Equal(t, []string{"cache/redis"}, targetEvents(), "changes of the silenced target aren't notified")
+	assert.Error(t, inspector.CheckGroup(GroupReady, true), "status is reported as usual")
+
+	snapshot := inspector.Snapshot()
+	if assert.Len(t, snapshot.Silences, 1) {
+		assert.Equal(t, "pg", snapshot.Silences[0].Dest)
+	}
+
+	child, err := inspector.Child("db")
+	assert.NoError(t, err)
+	assert.Len(t, child.Silences(), 1)
+	assert.ErrorIs(t, child.Silence("cache", "redis", time.Now().Add(time.Hour), ""), errForeignScope)
+	assert.False(t, child.Unsilence("cache", "redis"))
+	assert.True(t, child.Unsilence("db", "pg"))
+	assert.False(t, inspector.Unsilence("db", "pg"))
+
+	pg.healthErr = nil
+	inspector.check(context.Background())
+
+	assert.Equal(t, []string{"db/pg"}, targetEvents(), "notified after the silence")
+	assert.Empty(t, inspector.Snapshot().Silences)
+}
@@ -0,0 +1,308 @@
+package healthz
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// TargetResult - result of the last health check of the target.
+type TargetResult struct {
+	Scope    string
+	Dest     string
+	Groups   ProbeGroup
+	Err      error
+	Degraded error          // reason of StatusDegraded, the check passed (Err is nil), see Degraded
+	Details  map[string]any // set by DetailedChecker
+	// start of the check cycle of the result and how long the check took,
+	// zero duration if the check didn't finish
+	CheckedAt time.Time
+	Duration  time.Duration
+
+	groupErrs map[ProbeGroup]error // set for MultiGroupChecker targets
+	carried   bool                 // kept from the last check, the target wasn't due (see WithAdaptiveSchedule)
+	manual    Status               // maintenance or disabled, see Inspector.SetTargetStatus
+}
+
+// errFor - result of the target for the groups.
+func (tr TargetResult) errFor(group ProbeGroup) error {
+	if tr.groupErrs == nil {
+		return tr.Err
+	}
+
+	var errs []error
+
+	for _, g := range groupBits(group & tr.Groups) {
+		errs = append(errs, tr.groupErrs[g])
+	}
+
+	return errors.Join(errs...)
+}
+
+// Healthy - reports whether the last check passed, degraded targets pass.
+func (tr TargetResult) Healthy() bool {
+	return tr.Err == nil
+}
+
+// Status - status of the target: maintenance or disabled if set by Inspector.SetTargetStatus,
+// otherwise of the last check.
+func (tr TargetResult) Status() Status {
+	switch {
+	case tr.manual != "":
+		return tr.manual
+	case tr.Err != nil:
+		return StatusUnhealthy
+	case tr.Degraded != nil:
+		return StatusDegraded
+	default:
+		return StatusHealthy
+	}
+}
+
+func (tr TargetResult) MarshalJSON() ([]byte, error) {
+	view := struct {
+		Scope    string         `json:"scope"`
+		Dest     string         `json:"dest"`
+		Groups   ProbeGroup     `json:"groups"`
+		Healthy  bool           `json:"healthy"`
+		Error    string         `json:"error,omitempty"`
+		Degraded string         `json:"degraded,omitempty"`
+		Status   Status         `json:"status,omitempty"` // maintenance or disabled only
+		Details  map[string]any `json:"details,omitempty"`
+		// zero ones are omitted, like by the json-detailed format
+		CheckedAt       *time.Time `json:"checkedAt,omitempty"`
+		DurationSeconds *float64   `json:"durationSeconds,omitempty"`
+	}{
+		Scope:   tr.Scope,
+		Dest:    tr.Dest,
+		Groups:  tr.Groups,
+		Healthy: tr.Healthy(),
+		Status:  tr.manual,
+		Details: tr.Details,
+	}
+
+	if tr.Err != nil {
+		view.Error = tr.Err.Error()
+	}
+
+	if tr.Degraded != nil {
+		view.Degraded = tr.Degraded.Error()
+	}
+
+	if !tr.CheckedAt.IsZero() {
+		view.CheckedAt = &tr.CheckedAt
+	}
+
+	if tr.Duration > 0 {
+		d := tr.Duration.Seconds()
+		view.DurationSeconds = &d
+	}
+
+	return json.Marshal(view)
+}
+
+// UnmarshalJSON - restores the result served by StatusHandler (e.g. for Compare),
+// the error keeps only its message.
+func (tr *TargetResult) UnmarshalJSON(data []byte) error {
+	var view struct {
+		Scope    string         `json:"scope"`
+		Dest     string         `json:"dest"`
+		Groups   ProbeGroup     `json:"groups"`
+		Healthy  bool           `json:"healthy"`
+		Error    string         `json:"error"`
+		Degraded string         `json:"degraded"`
+		Status   Status         `json:"status"`
+		Details  map[string]any `json:"details"`
+		// zero if omitted
+		CheckedAt       time.Time `json:"checkedAt"`
+		DurationSeconds float64   `json:"durationSeconds"`
+	}
+
+	if err := json.Unmarshal(data, &view); err != nil {
+		return err
+	}
+
+	*tr = TargetResult{
+		Scope:     view.Scope,
+		Dest:      view.Dest,
+		Groups:    view.Groups,
+		Details:   view.Details,
+		CheckedAt: view.CheckedAt,
+		Duration:  time.Duration(math.Round(view.DurationSeconds * float64(time.Second))),
+	}
+
+	if view.Status.manual() {
+		tr.manual = view.Status
+	}
+
+	if !view.Healthy {
+		msg := view.Error
+		if msg == "" {
+			msg = "unhealthy"
+		}
+
+		tr.Err = errors.New(msg)
+	}
+
+	if view.Degraded != "" {
+		tr.Degraded = Degraded(errors.New(view.Degraded))
+	}
+
+	return nil
+}
+
+// Snapshot - results of the last check cycle.
+// Seq grows by one with every published result (check cycles, invalidations, removed targets),
+// so consumers could detect missed updates and order events (see WebhookEvent.Seq, StateChange.Seq).
+type Snapshot struct {
+	Seq         uint64           `json:"seq"`
+	PublishedAt time.Time        `json:"publishedAt"`
+	Targets     []TargetResult   `json:"targets"`
+	Schedule    []TargetSchedule `json:"-"` // see Inspector.Schedule, served by ScheduleHandler
+	// offenders of the cancellation audit, see WithCancellationAudit
+	CancelOffenders []CancelAudit `json:"cancelOffenders,omitempty"`
+	Silences        []Silence     `json:"silences,omitempty"` // active silences, see Inspector.Silence
+}
+
+// SnapshotPage - filtered and paged snapshot served by StatusHandler.
+type SnapshotPage struct {
+	Snapshot
+	Total int `json:"total"` // count of the matching targets
+}
+
+// Snapshot - returns copy of the last check cycle results, for dashboards and logic beyond CheckGroup
+// (see Snapshot.Target, Snapshot.Group). Details of the targets are copied too.
+func (i *Inspector) Snapshot() Snapshot {
+	res := i.result()
+
+	targets := make([]TargetResult, len(res.targets))
+	for n, tr := range res.targets {
+		tr.Details = maps.Clone(tr.Details)
+		targets[n] = tr
+	}
+
+	return Snapshot{
+		Seq:             res.seq,
+		PublishedAt:     res.publishedAt,
+		Targets:         targets,
+		Schedule:        i.Schedule(),
+		CancelOffenders: i.CancelAudit(),
+		Silences:        i.Silences(),
+	}
+}
+
+// ScopeSummary - health of all targets of one scope.
+type ScopeSummary struct {
+	Scope   string `json:"scope"`
+	Healthy int    `json:"healthy"`
+	Total   int    `json:"total"`
+}
+
+// String - e.g. "database: 2/3 up".
+func (ss ScopeSummary) String() string {
+	return fmt.Sprintf("%s: %d/%d up", ss.Scope, ss.Healthy, ss.Total)
+}
+
+// ByScope - summarizes health per scope, sorted by scope.
+func (s Snapshot) ByScope() []ScopeSummary {
+	idx := make(map[string]int)
+
+	var summaries []ScopeSummary
+
+	for _, tr := range s.Targets {
+		n, ok := idx[tr.Scope]
+		if !ok {
+			n = len(summaries)
+			idx[tr.Scope] = n
+
+			summaries = append(summaries, ScopeSummary{Scope: tr.Scope})
+		}
+
+		summaries[n].Total++
+
+		if tr.Healthy() {
+			summaries[n].Healthy++
+		}
+	}
+
+	sort.Slice(summaries, func(a, b int) bool { return summaries[a].Scope < summaries[b].Scope })
+
+	return summaries
+}
+
+// Target - result of the target, false if the snapshot hasn't it.
+func (s Snapshot) Target(scope, dest string) (TargetResult, bool) {
+	for _, tr := range s.Targets {
+		if tr.Scope == scope && tr.Dest == dest {
+			return tr, true
+		}
+	}
+
+	return TargetResult{}, false
+}
+
+// Group - results of the targets of the group (any group of the mask), Err is the error of the target
+// for the group (MultiGroupChecker targets report per group) as CheckGroup evaluates it.
+func (s Snapshot) Group(group ProbeGroup) []TargetResult {
+	var list []TargetResult
+
+	for _, tr := range s.Targets {
+		if tr.Groups&group != 0 {
+			tr.Err = tr.errFor(group)
+			list = append(list, tr)
+		}
+	}
+
+	return list
+}
+
+// StatusHandler - serves the snapshot as JSON (or by a codec accepted by the request, see RegisterCodec),
+// e.g. on /healthz/status. Targets could be filtered and paged by query params,
+// e.g. ?scope=database&status=fail&limit=100&offset=200 (scope, dest, group - repeated or comma separated,
+// status - ok, fail or degraded), then the response has "total" - count of the matching targets.
+func (i *Inspector) StatusHandler() http.Handler {
+	return Gzip(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query, filtered, err := parseTargetQuery(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+
+			return
+		}
+
+		i.refreshRequested(r)
+
+		snapshot := i.Snapshot()
+
+		w.Header().Set(HeaderSeq, strconv.FormatUint(snapshot.Seq, 10))
+
+		for n, tr := range snapshot.Targets {
+			snapshot.Targets[n].Err = i.redact(tr.Err)
+			snapshot.Targets[n].Degraded = i.redact(tr.Degraded)
+		}
+
+		if !filtered {
+			writeEncoded(w, r, http.StatusOK, snapshot)
+
+			return
+		}
+
+		var total int
+
+		snapshot.Targets, total = query.apply(snapshot.Targets)
+
+		writeEncoded(w, r, http.StatusOK, SnapshotPage{Snapshot: snapshot, Total: total})
+	}))
+}
+
+// ScopesHandler - serves per scope summary as JSON (or by a negotiated codec), e.g. on /healthz/scopes.
+func (i *Inspector) ScopesHandler() http.Handler {
+	return Gzip(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeEncoded(w, r, http.StatusOK, i.Snapshot().ByScope())
+	}))
+}
@@ -0,0 +1,233 @@
+package healthz
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshot(t *testing.T) {
+	inspector := New(
+		HealthCheckTarget{Service: &mockService{scope: "database", dest: "pg-1"}, Groups: GroupReady},
+		HealthCheckTarget{Service: &mockService{scope: "kafka", dest: "k-1", healthErr: errors.New("fail")}, Groups: GroupLive},
+		HealthCheckTarget{Service: &mockService{scope: "database", dest: "pg-2"}, Groups: GroupReady},
+	)
+
+	assert.Empty(t, inspector.Snapshot().Targets)
+
+	inspector.check(context.Background())
+
+	snapshot := inspector.Snapshot()
+	assert.Len(t, snapshot.Targets, 3)
+	assert.Equal(t, "pg-1", snapshot.Targets[0].Dest)
+	assert.False(t, snapshot.Targets[1].Healthy())
+
+	tr := snapshot.Targets[1]
+	tr.CheckedAt, tr.Duration = time.Time{}, 0
+
+	body, err := json.Marshal(tr)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"scope":"kafka","dest":"k-1","groups":4,"healthy":false,"error":"fail"}`, string(body))
+}
+
+func TestTargetResult_JSON(t *testing.T) {
+	checkedAt := time.Date(2024, 5, 1, 10, 0, 0, 123000000, time.UTC)
+
+	tests := []struct {
+		name string
+		tr   TargetResult
+		want string
+	}{
+		{
+			name: "test.1 checked",
+			tr:   TargetResult{Scope: "db", Dest: "pg", Groups: GroupReady, CheckedAt: checkedAt, Duration: 1500 * time.Millisecond},
+			want: `{"scope":"db","dest":"pg","groups":8,"healthy":true,"checkedAt":"2024-05-01T10:00:00.123Z","durationSeconds":1.5}`,
+		},
+		{
+			name: "test.2 unfinished",
+			tr:   TargetResult{Scope: "db", Dest: "pg", Groups: GroupReady, Err: errors.New("timeout"), CheckedAt: checkedAt},
+			want: `{"scope":"db","dest":"pg","groups":8,"healthy":false,"error":"timeout","checkedAt":"2024-05-01T10:00:00.123Z"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := json.Marshal(tt.tr)
+			assert.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(body))
+
+			var got TargetResult
+
+			assert.NoError(t, json.Unmarshal(body, &got))
+			assert.True(t, tt.tr.CheckedAt.Equal(got.CheckedAt))
+			assert.Equal(t, tt.tr.Duration, got.Duration)
+			assert.Equal(t, tt.tr.Healthy(), got.Healthy())
+		})
+	}
+}
+
+func TestSnapshot_lookups(t *testing.T) {
+	errReady := errors.New("query failed")
+
+	inspector := New(
+		HealthCheckTarget{
+			Service: &multiGroupService{
+				mockService: mockService{scope: "database", dest: "pg"},
+				groupErrs:   map[ProbeGroup]error{GroupReady: errReady},
+			},
+			Groups: GroupLive | GroupReady,
+		},
+		HealthCheckTarget{
+			Service: &detailedService{mockService: mockService{scope: "cache", dest: "redis"}, details: map[string]any{"keys": 10}},
+			Groups:  GroupLive,
+		},
+	)
+	inspector.check(context.Background())
+
+	snapshot := inspector.Snapshot()
+
+	tr, ok := snapshot.Target("cache", "redis")
+	assert.True(t, ok)
+	assert.Equal(t, GroupLive, tr.Groups)
+	assert.False(t, tr.CheckedAt.IsZero())
+
+	_, ok = snapshot.Target("cache", "memcached")
+	assert.False(t, ok)
+
+	tr.Details["keys"] = 0
+	tr, _ = inspector.Snapshot().Target("cache", "redis")
+	assert.Equal(t, 10, tr.Details["keys"], "details of the snapshot are a copy")
+
+	live := snapshot.Group(GroupLive)
+	if assert.Len(t, live, 2) {
+		assert.NoError(t, live[0].Err)
+	}
+
+	ready := snapshot.Group(GroupReady)
+	if assert.Len(t, ready, 1) {
+		assert.ErrorIs(t, ready[0].Err, errReady)
+	}
+
+	assert.Empty(t, snapshot.Group(GroupStartup))
+}
+
+func TestSnapshot_ByScope(t *testing.T) {
+	snapshot := Snapshot{Targets: []TargetResult{
+		{Scope: "kafka", Dest: "k-1"},
+		{Scope: "database", Dest: "pg-1"},
+		{Scope: "kafka", Dest: "k-2", Err: errors.New("fail")},
+		{Scope: "database", Dest: "pg-2"},
+	}}
+
+	summaries := snapshot.ByScope()
+	assert.Equal(t, []ScopeSummary{
+		{Scope: "database", Healthy: 2, Total: 2},
+		{Scope: "kafka", Healthy: 1, Total: 2},
+	}, summaries)
+	assert.Equal(t, "kafka: 1/2 up", summaries[1].String())
+}
+
+func TestScopesHandler(t *testing.T) {
+	inspector := New(
+		HealthCheckTarget{Service: &mockService{scope: "cache", dest: "redis"}, Groups: GroupReady},
+	)
+	inspector.check(context.Background())
+
+	w := httptest.NewRecorder()
+	inspector.ScopesHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz/scopes", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `[{"scope":"cache","healthy":1,"total":1}]`, w.Body.String())
+}
+
+type detailedService struct {
+	mockService
+	details map[string]any
+}
+
+func (d *detailedService) HealthDetails(context.Context) (map[string]any, error) {
+	return d.details, d.healthErr
+}
+
+func TestDetailedChecker(t *testing.T) {
+	svc := &detailedService{
+		mockService: mockService{scope: "database", dest: "pg-replica"},
+		details:     map[string]any{"replicationLagMs": 120, "poolInUse": 3},
+	}
+
+	inspector := New(HealthCheckTarget{Service: svc, Groups: GroupReady})
+	inspector.check(context.Background())
+
+	tr := inspector.Snapshot().Targets[0]
+	assert.True(t, tr.Healthy())
+	assert.Equal(t, 120, tr.Details["replicationLagMs"])
+
+	w := httptest.NewRecorder()
+	inspector.StatusHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz/status", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var body map[string]any
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, 1.0, body["seq"])
+	assert.NotEmpty(t, body["publishedAt"])
+
+	delete(body, "seq")
+	delete(body, "publishedAt")
+
+	target := body["targets"].([]any)[0].(map[string]any)
+	assert.NotEmpty(t, target["checkedAt"])
+	assert.NotEmpty(t, target["durationSeconds"])
+
+	delete(target, "checkedAt")
+	delete(target, "durationSeconds")
+
+	rest, err := json.Marshal(body)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"targets":[{"scope":"database","dest":"pg-replica","groups":8,"healthy":true,
+		"details":{"replicationLagMs":120,"poolInUse":3}}]}`, string(rest))
+}
+
+func TestSnapshot_seq(t *testing.T) {
+	svc := &mockService{scope: "db", dest: "pg"}
+
+	inspector := New(HealthCheckTarget{Service: svc, Groups: GroupReady})
+	assert.Zero(t, inspector.Snapshot().Seq)
+
+	events, unsubscribe := inspector.Subscribe(10)
+	defer unsubscribe()
+
+	inspector.check(context.Background())
+
+	first := inspector.Snapshot()
+	assert.Equal(t, uint64(1), first.Seq)
+	assert.False(t, first.PublishedAt.IsZero())
+
+	svc.healthErr = errors.New("down")
+	inspector.check(context.Background())
+	assert.Equal(t, uint64(2), inspector.Snapshot().Seq)
+
+	event := <-events
+	assert.Equal(t, uint64(2), event.Seq, "events carry the sequence number of their result")
+
+	assert.True(t, inspector.Invalidate("db", "pg"))
+	assert.Equal(t, uint64(3), inspector.Snapshot().Seq, "every published result is numbered")
+
+	child, err := inspector.Child("db")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), child.Snapshot().Seq)
+
+	w := httptest.NewRecorder()
+	inspector.HealthHandler(GroupReady, true, nil)(w, httptest.NewRequest(http.MethodGet, "/healthz/ready", nil))
+	assert.Equal(t, "3", w.Header().Get(HeaderSeq))
+
+	w = httptest.NewRecorder()
+	inspector.StatusHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz/status", nil))
+	assert.Equal(t, "3", w.Header().Get(HeaderSeq))
+}
@@ -0,0 +1,251 @@
+package healthz
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+var (
+	errWrongTransition    = errors.New("status transition isn't allowed")
+	errUnregisteredTarget = errors.New("target isn't registered")
+	errWrongManualStatus  = errors.New("status could be set to maintenance, disabled or unknown only")
+)
+
+// Statuses of the target state machine beyond the check results, see Inspector.SetTargetStatus.
+const (
+	StatusUnknown     Status = "unknown"     // not yet checked, or back from maintenance/disabled till checked
+	StatusMaintenance Status = "maintenance" // checked and reported but excluded from the group verdicts
+	StatusDisabled    Status = "disabled"    // neither checked nor part of the group verdicts, last result is kept
+)
+
+// statusTransitions - allowed transitions of the target state machine: check results move the target
+// between healthy, degraded and unhealthy, operators put it into maintenance or disable it
+// from any state, and bring it back through unknown till the next check.
+var statusTransitions = map[Status][]Status{
+	StatusUnknown:     {StatusHealthy, StatusDegraded, StatusUnhealthy, StatusMaintenance, StatusDisabled},
+	StatusHealthy:     {StatusDegraded, StatusUnhealthy, StatusMaintenance, StatusDisabled},
+	StatusDegraded:    {StatusHealthy, StatusUnhealthy, StatusMaintenance, StatusDisabled},
+	StatusUnhealthy:   {StatusHealthy, StatusDegraded, StatusMaintenance, StatusDisabled},
+	StatusMaintenance: {StatusUnknown, StatusDisabled},
+	StatusDisabled:    {StatusUnknown, StatusMaintenance},
+}
+
+// CanTransition - reports whether the target state machine allows the transition.
+func (s Status) CanTransition(to Status) bool {
+	return slices.Contains(statusTransitions[s], to)
+}
+
+// manual - reports whether the status is set by operators (maintenance, disabled).
+func (s Status) manual() bool {
+	return s == StatusMaintenance || s == StatusDisabled
+}
+
+// TargetState - state of the target in the state machine.
+type TargetState struct {
+	Scope    string
+	Dest     string
+	Status   Status
+	Previous Status    // status before the last transition, empty if there was none
+	Since    time.Time // time of the last transition, zero if there was none
+}
+
+type targetStates struct {
+	mu     sync.Mutex
+	states map[string]*TargetState // keyed by "scope/dest"
+	manual map[string]Status       // maintenance or disabled targets
+}
+
+// transitLocked - moves the target to the status, mu must be held.
+func (ts *targetStates) transitLocked(scope, dest string, status Status, at time.Time) {
+	if ts.states == nil {
+		ts.states = make(map[string]*TargetState)
+	}
+
+	key := targetKey(scope, dest)
+
+	s, ok := ts.states[key]
+	if !ok {
+		s = &TargetState{Scope: scope, Dest: dest, Status: StatusUnknown}
+		ts.states[key] = s
+	}
+
+	if s.Status == status {
+		return
+	}
+
+	s.Previous, s.Status, s.Since = s.Status, status, at
+}
+
+// manualStatus - maintenance or disabled status of the target, empty if none.
+func (i *Inspector) manualStatus(scope, dest string) Status {
+	i.states.mu.Lock()
+	defer i.states.mu.Unlock()
+
+	return i.states.manual[targetKey(scope, dest)]
+}
+
+// SetTargetStatus - puts the target into maintenance (checked and reported, but excluded from the group
+// verdicts) or disables it (not checked either), StatusUnknown brings it back till the next check.
+// Transitions not allowed by the state machine (see Status.CanTransition) fail.
+// Child sets the status of its scope targets only.
+func (i *Inspector) SetTargetStatus(scope, dest string, status Status) error {
+	if i.parent != nil {
+		if scope != i.scope {
+			return fmt.Errorf("%w: %s", errForeignScope, scope)
+		}
+
+		return i.parent.SetTargetStatus(scope, dest, status)
+	}
+
+	if !status.manual() && status != StatusUnknown {
+		return fmt.Errorf("%w: %q", errWrongManualStatus, status)
+	}
+
+	if !i.registeredTarget(scope, dest) {
+		return fmt.Errorf("%w: %s", errUnregisteredTarget, targetKey(scope, dest))
+	}
+
+	i.changesMu.Lock()
+	defer i.changesMu.Unlock()
+
+	i.states.mu.Lock()
+
+	key := targetKey(scope, dest)
+
+	cur := StatusUnknown
+	if s, ok := i.states.states[key]; ok {
+		cur = s.Status
+	}
+
+	if cur == status {
+		i.states.mu.Unlock()
+
+		return nil
+	}
+
+	if !cur.CanTransition(status) {
+		i.states.mu.Unlock()
+
+		return fmt.Errorf("%w: %s -> %s", errWrongTransition, cur, status)
+	}
+
+	if i.states.manual == nil {
+		i.states.manual = make(map[string]Status)
+	}
+
+	if status == StatusUnknown {
+		delete(i.states.manual, key)
+	} else {
+		i.states.manual[key] = status
+	}
+
+	manual := i.states.manual[key]
+
+	i.states.transitLocked(scope, dest, status, time.Now())
+	i.states.mu.Unlock()
+
+	// publishes the status at once, not with the next cycle
+	prev := i.get()
+	if !prev.checked {
+		return nil
+	}
+
+	next := prev.clone()
+
+	for n, tr := range next.targets {
+		if tr.Scope == scope && tr.Dest == dest {
+			next.targets[n].manual = manual
+		}
+	}
+
+	i.storeLocked(prev, next)
+
+	return nil
+}
+
+// TargetStates - states of the targets in the state machine, for reporting and flap detection.
+func (i *Inspector) TargetStates() []TargetState {
+	if i.parent != nil {
+		var list []TargetState
+
+		for _, ts := range i.parent.TargetStates() {
+			if ts.Scope == i.scope {
+				list = append(list, ts)
+			}
+		}
+
+		return list
+	}
+
+	i.mu.RLock()
+	targets := i.targets
+	i.mu.RUnlock()
+
+	i.states.mu.Lock()
+	defer i.states.mu.Unlock()
+
+	list := make([]TargetState, 0, len(targets))
+
+	for _, target := range targets {
+		scope, dest := target.Service.Scope(), target.Service.Dest()
+
+		ts := TargetState{Scope: scope, Dest: dest, Status: StatusUnknown}
+		if s, ok := i.states.states[targetKey(scope, dest)]; ok {
+			ts = *s
+		}
+
+		list = append(list, ts)
+	}
+
+	return list
+}
+
+// trackStates - moves the targets checked by the cycle to the status of their results
+// and marks results of maintenance and disabled targets.
+func (i *Inspector) trackStates(results []TargetResult, at time.Time) {
+	i.states.mu.Lock()
+	defer i.states.mu.Unlock()
+
+	alive := make(map[string]bool, len(results))
+
+	for n := range results {
+		tr := &results[n]
+		key := targetKey(tr.Scope, tr.Dest)
+		alive[key] = true
+
+		if tr.manual = i.states.manual[key]; tr.manual != "" {
+			continue
+		}
+
+		i.states.transitLocked(tr.Scope, tr.Dest, tr.Status(), at)
+	}
+
+	for key := range i.states.states {
+		if !alive[key] {
+			delete(i.states.states, key)
+			delete(i.states.manual, key)
+		}
+	}
+}
+
+// registeredTarget - reports whether the target is registered.
+func (i *Inspector) registeredTarget(scope, dest string) bool {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	return i.registeredLocked(scope, dest)
+}
+
+// registeredLocked - registeredTarget, i.mu must be held.
+func (i *Inspector) registeredLocked(scope, dest string) bool {
+	for _, t := range i.targets {
+		if t.Service.Scope() == scope && t.Service.Dest() == dest {
+			return true
+		}
+	}
+
+	return false
+}
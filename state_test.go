@@ -0,0 +1,88 @@
+package healthz
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatus_CanTransition(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to Status
+		want     bool
+	}{
+		{name: "test.1 first check", from: StatusUnknown, to: StatusHealthy, want: true},
+		{name: "test.2 failure", from: StatusHealthy, to: StatusUnhealthy, want: true},
+		{name: "test.3 recovery through degraded", from: StatusUnhealthy, to: StatusDegraded, want: true},
+		{name: "test.4 maintenance", from: StatusDegraded, to: StatusMaintenance, want: true},
+		{name: "test.5 back from maintenance", from: StatusMaintenance, to: StatusUnknown, want: true},
+		{name: "test.6 maintenance result", from: StatusMaintenance, to: StatusHealthy},
+		{name: "test.7 forget result", from: StatusHealthy, to: StatusUnknown},
+		{name: "test.8 unknown status", from: Status("lost"), to: StatusHealthy},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.from.CanTransition(tt.to))
+		})
+	}
+}
+
+func TestSetTargetStatus(t *testing.T) {
+	var calls atomic.Int32
+
+	failing := &mockService{scope: "db", dest: "replica", healthErr: errors.New("down"), callBack: func() { calls.Add(1) }}
+
+	inspector := New(
+		HealthCheckTarget{Service: &mockService{scope: "db", dest: "primary"}, Groups: GroupReady},
+		HealthCheckTarget{Service: failing, Groups: GroupReady},
+	)
+
+	assert.Equal(t, StatusUnknown, inspector.TargetStates()[1].Status)
+
+	inspector.check(context.Background())
+	assert.Error(t, inspector.CheckGroup(GroupReady, true))
+
+	state := inspector.TargetStates()[1]
+	assert.Equal(t, StatusUnhealthy, state.Status)
+	assert.Equal(t, StatusUnknown, state.Previous)
+	assert.False(t, state.Since.IsZero())
+
+	assert.ErrorIs(t, inspector.SetTargetStatus("db", "replica", StatusHealthy), errWrongManualStatus)
+	assert.ErrorIs(t, inspector.SetTargetStatus("db", "unknown", StatusMaintenance), errUnregisteredTarget)
+	assert.ErrorIs(t, inspector.SetTargetStatus("db", "replica", StatusUnknown), errWrongTransition)
+
+	assert.NoError(t, inspector.SetTargetStatus("db", "replica", StatusMaintenance))
+	assert.NoError(t, inspector.CheckGroup(GroupReady, true), "maintenance target is excluded at once")
+
+	tr, _ := inspector.Snapshot().Target("db", "replica")
+	assert.Equal(t, StatusMaintenance, tr.Status())
+	assert.Error(t, tr.Err, "result is still reported")
+
+	inspector.check(context.Background())
+	assert.Equal(t, int32(2), calls.Load(), "maintenance target is checked")
+	assert.NoError(t, inspector.CheckGroup(GroupReady, true))
+	assert.Equal(t, StatusMaintenance, inspector.TargetStates()[1].Status)
+
+	child, err := inspector.Child("db")
+	assert.NoError(t, err)
+	assert.ErrorIs(t, child.SetTargetStatus("cache", "redis", StatusDisabled), errForeignScope)
+	assert.NoError(t, child.SetTargetStatus("db", "replica", StatusDisabled))
+
+	inspector.check(context.Background())
+	assert.Equal(t, int32(2), calls.Load(), "disabled target isn't checked")
+	assert.NoError(t, inspector.CheckGroup(GroupReady, true))
+
+	assert.NoError(t, inspector.SetTargetStatus("db", "replica", StatusUnknown))
+	assert.Equal(t, StatusUnknown, inspector.TargetStates()[1].Status)
+	assert.Equal(t, StatusDisabled, inspector.TargetStates()[1].Previous)
+
+	inspector.check(context.Background())
+	assert.Equal(t, int32(3), calls.Load())
+	assert.Error(t, inspector.CheckGroup(GroupReady, true))
+	assert.Equal(t, StatusUnhealthy, inspector.TargetStates()[1].Status)
+}
@@ -0,0 +1,196 @@
+package healthz
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+var errMissCallback = errors.New("missing state change callback")
+
+// StateChange - transition of a target (Scope, Dest set) or of a probe group (Group set)
+// between healthy and unhealthy.
+type StateChange struct {
+	Seq     uint64 // of the published result, see Snapshot.Seq
+	Time    time.Time
+	Group   ProbeGroup // zero for targets
+	Scope   string
+	Dest    string
+	Healthy bool // state after the change
+	Err     error
+}
+
+// probeGroups - groups evaluated for state changes like the default endpoints (see Handler).
+var probeGroups = []struct {
+	group   ProbeGroup
+	needAll bool
+}{
+	{group: GroupStartup},
+	{group: GroupLive},
+	{group: GroupReady, needAll: true},
+}
+
+type subscription struct {
+	ch    chan StateChange
+	scope string // targets of the scope only if set (subscription of a child)
+}
+
+type subscribers struct {
+	mu        sync.Mutex
+	subs      map[*subscription]struct{}
+	callbacks []func(StateChange)
+	groups    map[ProbeGroup]bool // last state of the groups, nil until the first evaluation
+}
+
+// WithOnStateChange - fn is called by the check loop for every transition of targets and of
+// the startup, live (any target healthy) and ready (all targets healthy) groups, fn mustn't block.
+func WithOnStateChange(fn func(StateChange)) Option {
+	return func(i *Inspector) error {
+		if fn == nil {
+			return errMissCallback
+		}
+
+		i.subscribers.mu.Lock()
+		defer i.subscribers.mu.Unlock()
+
+		i.subscribers.callbacks = append(i.subscribers.callbacks, fn)
+
+		return nil
+	}
+}
+
+// Subscribe - channel of transitions (see WithOnStateChange) buffered by size, transitions not fitting
+// the buffer are dropped and logged (slog). The returned func unsubscribes and closes the channel.
+// Subscription of a child gets transitions of its scope targets only.
+func (i *Inspector) Subscribe(size int) (<-chan StateChange, func()) {
+	sub := &subscription{ch: make(chan StateChange, max(size, 0))}
+
+	root := i
+	if i.parent != nil {
+		root = i.parent
+		sub.scope = i.scope
+	}
+
+	s := &root.subscribers
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.subs == nil {
+		s.subs = make(map[*subscription]struct{})
+	}
+
+	s.subs[sub] = struct{}{}
+
+	var once sync.Once
+
+	return sub.ch, func() {
+		once.Do(func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+
+			delete(s.subs, sub)
+			close(sub.ch)
+		})
+	}
+}
+
+// notifyState - sends the target changes of the cycle and transitions of the groups.
+func (i *Inspector) notifyState(changes []Change, result *healthResult) {
+	s := &i.subscribers
+
+	s.mu.Lock()
+
+	if len(s.subs) == 0 && len(s.callbacks) == 0 && i.logger == nil {
+		s.mu.Unlock()
+
+		return
+	}
+
+	events := make([]StateChange, 0, len(changes)+len(probeGroups))
+
+	for _, c := range changes {
+		events = append(events, StateChange{Seq: result.seq, Time: c.Time, Scope: c.Scope, Dest: c.Dest, Healthy: c.Healthy, Err: c.Err})
+	}
+
+	first := s.groups == nil
+	if first {
+		s.groups = make(map[ProbeGroup]bool, len(probeGroups))
+	}
+
+	for _, pg := range probeGroups {
+		err := i.CheckGroup(pg.group, pg.needAll)
+
+		healthy, seen := s.groups[pg.group]
+		if !first && seen && healthy != (err == nil) {
+			events = append(events, StateChange{Seq: result.seq, Time: result.checkedAt, Group: pg.group, Healthy: err == nil, Err: err})
+
+			i.logAt(i.logLevels.Transition, "healthz: probe group state changed",
+				"group", pg.group.String(), "healthy", err == nil, "error", i.redact(err))
+		}
+
+		s.groups[pg.group] = err == nil
+	}
+
+	callbacks := i.deliverLocked(events)
+
+	s.mu.Unlock()
+
+	runCallbacks(callbacks, events)
+}
+
+// notifyShutdown - sends the transition of the ready group flipped by BeginShutdown at once,
+// so subscribers (e.g. load balancer syncers) don't wait for the next cycle.
+func (i *Inspector) notifyShutdown() {
+	s := &i.subscribers
+
+	s.mu.Lock()
+
+	if healthy, seen := s.groups[GroupReady]; (seen && !healthy) || (len(s.subs) == 0 && len(s.callbacks) == 0) {
+		s.mu.Unlock()
+
+		return
+	}
+
+	if s.groups == nil { // not evaluated yet, the next evaluation isn't the first one
+		s.groups = make(map[ProbeGroup]bool, len(probeGroups))
+	}
+
+	s.groups[GroupReady] = false
+
+	events := []StateChange{{Seq: i.get().seq, Time: time.Now(), Group: GroupReady, Err: errShuttingDown}}
+	callbacks := i.deliverLocked(events)
+
+	s.mu.Unlock()
+
+	runCallbacks(callbacks, events)
+}
+
+// deliverLocked - sends the events to the subscribers, returns the callbacks to run
+// without the lock, subscribers.mu must be held.
+func (i *Inspector) deliverLocked(events []StateChange) []func(StateChange) {
+	for _, event := range events {
+		for sub := range i.subscribers.subs {
+			if sub.scope != "" && (event.Group != 0 || event.Scope != sub.scope) {
+				continue
+			}
+
+			select {
+			case sub.ch <- event:
+			default:
+				i.log().Warn("healthz: subscriber is full, state change dropped",
+					"group", event.Group.String(), "scope", event.Scope, "dest", event.Dest)
+			}
+		}
+	}
+
+	return i.subscribers.callbacks
+}
+
+func runCallbacks(callbacks []func(StateChange), events []StateChange) {
+	for _, event := range events {
+		for _, fn := range callbacks {
+			fn(event)
+		}
+	}
+}
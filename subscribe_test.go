@@ -0,0 +1,114 @@
+package healthz
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubscribe(t *testing.T) {
+	db := &mockService{scope: "db", dest: "pg"}
+	cache := &mockService{scope: "cache", dest: "redis"}
+	inspector := New(
+		HealthCheckTarget{Service: db, Groups: GroupLive | GroupReady},
+		HealthCheckTarget{Service: cache, Groups: GroupLive | GroupReady},
+	)
+
+	var called []StateChange
+
+	assert.NoError(t, WithOnStateChange(func(sc StateChange) { called = append(called, sc) })(inspector))
+	assert.ErrorIs(t, WithOnStateChange(nil)(inspector), errMissCallback)
+
+	events, unsubscribe := inspector.Subscribe(8)
+	cacheInspector, err := inspector.Child("cache")
+	assert.NoError(t, err)
+
+	child, unsubscribeChild := cacheInspector.Subscribe(8)
+	defer unsubscribeChild()
+
+	ctx := context.Background()
+
+	inspector.check(ctx)
+	assert.Empty(t, called, "initial state is not a transition")
+
+	db.healthErr = errors.New("fail")
+	inspector.check(ctx)
+
+	want := []StateChange{
+		{Scope: "db", Dest: "pg", Healthy: false},
+		{Group: GroupReady, Healthy: false},
+	}
+
+	got := drain(events)
+	if assert.Len(t, got, len(want)) {
+		for n := range want {
+			assert.Equal(t, want[n].Group, got[n].Group)
+			assert.Equal(t, want[n].Scope, got[n].Scope)
+			assert.Equal(t, want[n].Healthy, got[n].Healthy)
+			assert.Error(t, got[n].Err)
+		}
+	}
+
+	assert.Len(t, called, len(want))
+	assert.Empty(t, drain(child), "child gets transitions of its scope only")
+
+	cache.healthErr = errors.New("fail")
+	inspector.check(ctx)
+
+	got = drain(events)
+	if assert.Len(t, got, 2) {
+		assert.Equal(t, "redis", got[0].Dest)
+		assert.Equal(t, GroupLive, got[1].Group)
+	}
+
+	if got := drain(child); assert.Len(t, got, 1) {
+		assert.Equal(t, "redis", got[0].Dest)
+	}
+
+	unsubscribe()
+	unsubscribe()
+
+	_, open := <-events
+	assert.False(t, open, "channel is closed by unsubscribe")
+
+	db.healthErr, cache.healthErr = nil, nil
+	inspector.check(ctx)
+	assert.Len(t, called, 8)
+}
+
+func drain(ch <-chan StateChange) []StateChange {
+	var list []StateChange
+
+	for {
+		select {
+		case sc := <-ch:
+			list = append(list, sc)
+		default:
+			return list
+		}
+	}
+}
+
+func TestSubscribe_beginShutdown(t *testing.T) {
+	inspector := New(HealthCheckTarget{Service: &mockService{scope: "db", dest: "pg"}, Groups: GroupReady})
+
+	events, unsubscribe := inspector.Subscribe(4)
+	defer unsubscribe()
+
+	inspector.check(context.Background())
+	assert.NoError(t, inspector.BeginShutdown(context.Background()))
+
+	select {
+	case event := <-events:
+		assert.Equal(t, GroupReady, event.Group)
+		assert.False(t, event.Healthy)
+		assert.ErrorIs(t, event.Err, errShuttingDown)
+	default:
+		assert.Fail(t, "ready transition isn't sent at the shutdown start")
+	}
+
+	inspector.check(context.Background())
+	assert.Empty(t, events, "the transition isn't repeated by the next cycle")
+}
@@ -0,0 +1,226 @@
+package healthz
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	errDuplicatedTarget = errors.New("target is already registered")
+	errForeignScope     = errors.New("target of other scope than the child")
+)
+
+// AddTarget - registers the target while the check loop is running (e.g. tenant databases come and go),
+// it is checked from the next cycle. Child adds only targets of its scope to the parent.
+func (i *Inspector) AddTarget(target HealthCheckTarget) error {
+	if target.Service == nil {
+		return errMissService
+	}
+
+	if err := target.Groups.validate(); err != nil {
+		return err
+	}
+
+	scope, dest := target.Service.Scope(), target.Service.Dest()
+
+	if i.parent != nil {
+		if scope != i.scope {
+			return fmt.Errorf("%w: %s", errForeignScope, scope)
+		}
+
+		return i.parent.AddTarget(target)
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	for _, t := range i.targets {
+		if t.Service.Scope() == scope && t.Service.Dest() == dest {
+			return fmt.Errorf("%w: %s", errDuplicatedTarget, targetKey(scope, dest))
+		}
+	}
+
+	// copy on write, running cycle iterates the previous slice
+	targets := make([]HealthCheckTarget, 0, len(i.targets)+1)
+	i.targets = append(append(targets, i.targets...), target)
+
+	return nil
+}
+
+// RemoveTarget - unregisters the target while the check loop is running, its result and metric series
+// are dropped at once. Reports whether the target was registered.
+func (i *Inspector) RemoveTarget(scope, dest string) bool {
+	if i.parent != nil {
+		return scope == i.scope && i.parent.RemoveTarget(scope, dest)
+	}
+
+	i.mu.Lock()
+
+	targets := make([]HealthCheckTarget, 0, len(i.targets))
+
+	for _, t := range i.targets {
+		if t.Service.Scope() != scope || t.Service.Dest() != dest {
+			targets = append(targets, t)
+		}
+	}
+
+	found := len(targets) != len(i.targets)
+	i.targets = targets
+
+	if found {
+		i.deleteTargetSeries(scope, dest)
+	}
+
+	i.mu.Unlock()
+
+	if !found {
+		return false
+	}
+
+	i.dropResult(scope, dest)
+
+	return true
+}
+
+// replaceTargets - current targets with the replaced ones swapped for the targets (at the place of the first
+// replaced one), fails on duplicated scope/dest. Dropped - replaced targets whose scope/dest is gone.
+func replaceTargets(current []HealthCheckTarget, replaced func(HealthCheckTarget) bool,
+	targets []HealthCheckTarget,
+) (merged, dropped []HealthCheckTarget, err error) {
+	merged = make([]HealthCheckTarget, 0, len(current)+len(targets))
+	inserted := false
+
+	for _, target := range current {
+		switch {
+		case !replaced(target):
+			merged = append(merged, target)
+		case !inserted:
+			merged = append(merged, targets...)
+			inserted = true
+		}
+	}
+
+	if !inserted {
+		merged = append(merged, targets...)
+	}
+
+	keys := make(map[string]bool, len(merged))
+
+	for _, target := range merged {
+		key := targetKey(target.Service.Scope(), target.Service.Dest())
+		if keys[key] {
+			return nil, nil, fmt.Errorf("%w: %s", errDuplicatedTarget, key)
+		}
+
+		keys[key] = true
+	}
+
+	for _, target := range current {
+		if replaced(target) && !keys[targetKey(target.Service.Scope(), target.Service.Dest())] {
+			dropped = append(dropped, target)
+		}
+	}
+
+	return merged, dropped, nil
+}
+
+// updateTargetMetrics - metrics of the check result of the inspector and its children,
+// skipped if the target was removed during the cycle, as its series are deleted.
+func (i *Inspector) updateTargetMetrics(res serviceCheckResult) []error {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	if !i.registeredLocked(res.target.Service.Scope(), res.target.Service.Dest()) {
+		return nil
+	}
+
+	return []error{i.updateMetric(res), i.updateChildMetrics(res)}
+}
+
+// registered - results of targets still registered, targets removed during the cycle are dropped.
+func (i *Inspector) registered(results []TargetResult) []TargetResult {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	keys := make(map[string]bool, len(i.targets))
+
+	for _, t := range i.targets {
+		keys[targetKey(t.Service.Scope(), t.Service.Dest())] = true
+	}
+
+	kept := results[:0]
+
+	for _, tr := range results {
+		if keys[targetKey(tr.Scope, tr.Dest)] {
+			kept = append(kept, tr)
+		}
+	}
+
+	return kept
+}
+
+// dropResult - publishes the current result without the target.
+func (i *Inspector) dropResult(scope, dest string) {
+	i.changesMu.Lock()
+	defer i.changesMu.Unlock()
+
+	cur := i.get()
+	next := &healthResult{checked: cur.checked, checkedAt: cur.checkedAt}
+
+	for _, tr := range cur.targets {
+		if tr.Scope != scope || tr.Dest != dest {
+			next.targets = append(next.targets, tr)
+		}
+	}
+
+	i.storeLocked(cur, next)
+}
+
+// deleteTargetSeries - deletes metric series of the removed target of the inspector and its children,
+// i.mu must be held, so a running cycle doesn't bring the series back (see updateTargetMetrics).
+func (i *Inspector) deleteTargetSeries(scope, dest string) {
+	i.deleteSeries(scope, dest)
+
+	for _, child := range i.childrenOf(scope) {
+		child.deleteSeries(scope, dest)
+	}
+}
+
+// deleteSeries - deletes metric series of the target, the shadow (see Rollout) doesn't own them.
+func (i *Inspector) deleteSeries(scope, dest string) {
+	if i.shadow.Load() {
+		return
+	}
+
+	labels := prometheus.Labels{"scope": scope, "dest": dest}
+
+	if i.metric != nil {
+		i.metric.DeletePartialMatch(labels)
+	}
+
+	if i.outcomeMetric != nil {
+		i.outcomeMetric.DeletePartialMatch(labels)
+	}
+
+	if i.latencyMetric != nil {
+		i.latencyMetric.DeletePartialMatch(labels)
+	}
+
+	if i.durationHist != nil {
+		i.durationHist.DeletePartialMatch(labels)
+	}
+
+	if i.queueWaitHist != nil {
+		i.queueWaitHist.DeletePartialMatch(labels)
+	}
+
+	if i.targetMetric != nil {
+		i.targetMetric.DeletePartialMatch(labels)
+
+		i.reportedMu.Lock()
+		delete(i.reported, targetKey(scope, dest))
+		i.reportedMu.Unlock()
+	}
+}
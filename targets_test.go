@@ -0,0 +1,109 @@
+package healthz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddRemoveTarget(t *testing.T) {
+	metric := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test_add_remove"}, []string{"scope", "dest"})
+
+	inspector := New(HealthCheckTarget{Service: &mockService{scope: "db", dest: "main"}, Groups: GroupReady})
+	assert.NoError(t, WithMetric(metric)(inspector))
+	assert.NoError(t, WithCheckPeriod(5*time.Millisecond)(inspector))
+	assert.NoError(t, inspector.Start(context.Background()))
+
+	defer inspector.Stop(context.Background())
+
+	tenant := HealthCheckTarget{Service: &mockService{scope: "tenant", dest: "acme", healthErr: errors.New("down")}, Groups: GroupReady}
+
+	assert.NoError(t, inspector.AddTarget(tenant))
+	assert.ErrorIs(t, inspector.AddTarget(tenant), errDuplicatedTarget)
+
+	assert.Eventually(t, func() bool {
+		return inspector.CheckGroup(GroupReady, true) != nil
+	}, testTimeout, time.Millisecond, "tenant is checked")
+
+	assert.True(t, inspector.RemoveTarget("tenant", "acme"))
+	assert.NoError(t, inspector.CheckGroup(GroupReady, true), "result is dropped at once")
+	assert.Equal(t, 1, testutil.CollectAndCount(metric), "series of the tenant is deleted")
+	assert.False(t, inspector.RemoveTarget("tenant", "acme"))
+
+	t.Run("Concurrent with the check loop", func(t *testing.T) {
+		var wg sync.WaitGroup
+
+		for n := range 10 {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				svc := &mockService{scope: "tenant", dest: fmt.Sprint(n)}
+				assert.NoError(t, inspector.AddTarget(HealthCheckTarget{Service: svc, Groups: GroupReady}))
+				time.Sleep(time.Millisecond)
+				assert.True(t, inspector.RemoveTarget("tenant", svc.dest))
+			}()
+		}
+
+		wg.Wait()
+		assert.Len(t, inspector.Schedule(), 1)
+	})
+
+	t.Run("Invalid target", func(t *testing.T) {
+		assert.ErrorIs(t, inspector.AddTarget(HealthCheckTarget{Groups: GroupLive}), errMissService)
+		assert.ErrorIs(t, inspector.AddTarget(HealthCheckTarget{Service: &mockService{}, Groups: 251}), errMissGroup)
+	})
+
+	t.Run("Child", func(t *testing.T) {
+		child, err := inspector.Child("tenant")
+		assert.NoError(t, err)
+
+		assert.ErrorIs(t, child.AddTarget(HealthCheckTarget{Service: &mockService{scope: "db", dest: "x"}, Groups: GroupLive}), errForeignScope)
+		assert.NoError(t, child.AddTarget(HealthCheckTarget{Service: &mockService{scope: "tenant", dest: "x"}, Groups: GroupLive}))
+		assert.Len(t, inspector.Schedule(), 2)
+		assert.True(t, child.RemoveTarget("tenant", "x"))
+		assert.False(t, child.RemoveTarget("db", "main"))
+	})
+}
+
+func TestRemoveTarget_runningCycle(t *testing.T) {
+	metric := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test_remove_running"}, []string{"scope", "dest"})
+	durations := prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: "test_remove_running_seconds"}, []string{"scope", "dest"})
+
+	started, release := make(chan struct{}), make(chan struct{})
+
+	inspector := New(
+		HealthCheckTarget{Service: &mockService{scope: "db", dest: "main"}, Groups: GroupReady},
+		HealthCheckTarget{Service: &mockService{scope: "db", dest: "tenant", callBack: func() {
+			close(started)
+			<-release
+		}}, Groups: GroupReady},
+	)
+	assert.NoError(t, WithMetric(metric)(inspector))
+	assert.NoError(t, WithDurationMetric(durations)(inspector))
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		inspector.check(context.Background())
+	}()
+
+	<-started
+	assert.True(t, inspector.RemoveTarget("db", "tenant"))
+	close(release)
+	<-done
+
+	assert.Equal(t, 1, testutil.CollectAndCount(metric), "series of the removed target aren't brought back")
+	assert.Equal(t, 1, testutil.CollectAndCount(durations))
+	assert.Equal(t, 1.0, testutil.ToFloat64(metric.WithLabelValues("db", "main")))
+}
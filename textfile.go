@@ -0,0 +1,48 @@
+package healthz
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var errWrongTextfile = errors.New("textfile must have .prom extension")
+
+// textfile - settings of the textfile collector export, see WithTextfileExporter.
+type textfile struct {
+	path     string
+	gatherer prometheus.Gatherer
+}
+
+// WithTextfileExporter - writes metrics of the gatherer (prometheus.DefaultGatherer if nil) to the file
+// of the node_exporter textfile collector after every check cycle, for batch jobs and short-lived
+// processes whose /metrics endpoint is never scraped in time. The file is replaced atomically,
+// its name must have .prom extension. Failed writes are logged.
+func WithTextfileExporter(path string, gatherer prometheus.Gatherer) Option {
+	return func(i *Inspector) error {
+		if !strings.HasSuffix(path, ".prom") {
+			return fmt.Errorf("%w: %q", errWrongTextfile, path)
+		}
+
+		if gatherer == nil {
+			gatherer = prometheus.DefaultGatherer
+		}
+
+		i.textfile = &textfile{path: path, gatherer: gatherer}
+
+		return nil
+	}
+}
+
+// exportTextfile - writes the metrics to the textfile if set.
+func (i *Inspector) exportTextfile() {
+	if i.textfile == nil {
+		return
+	}
+
+	if err := prometheus.WriteToTextfile(i.textfile.path, i.textfile.gatherer); err != nil {
+		i.log().Warn("healthz: metrics not written to textfile", "path", i.textfile.path, "error", err)
+	}
+}
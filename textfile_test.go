@@ -0,0 +1,40 @@
+package healthz
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithTextfileExporter(t *testing.T) {
+	assert.ErrorIs(t, WithTextfileExporter("/tmp/healthz.txt", nil)(New()), errWrongTextfile)
+
+	reg := prometheus.NewRegistry()
+	up := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "service_up", Help: "Health of the dependency."}, []string{"scope", "dest"})
+	reg.MustRegister(up)
+
+	path := filepath.Join(t.TempDir(), "healthz.prom")
+	svc := &mockService{scope: "db", dest: "pg"}
+
+	inspector := New(HealthCheckTarget{Service: svc, Groups: GroupReady})
+	assert.NoError(t, WithMetric(up)(inspector))
+	assert.NoError(t, WithTextfileExporter(path, reg)(inspector))
+
+	inspector.check(context.Background())
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `service_up{dest="pg",scope="db"} 1`)
+
+	svc.healthErr = errors.New("down")
+	inspector.check(context.Background())
+
+	data, err = os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `service_up{dest="pg",scope="db"} 0`, "rewritten every cycle")
+}
@@ -0,0 +1,147 @@
+package healthz
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var errWrongThreshold = errors.New("threshold must be positive")
+
+// thresholds - consecutive results needed to flip the reported state of a target.
+type thresholds struct {
+	failure int
+	success int
+}
+
+// WithThresholds - default thresholds of the targets (like kubernetes probes): reported state of
+// a target flips to unhealthy after failure consecutive failed checks and back to healthy after
+// success consecutive passed ones. HealthCheckTarget.FailureThreshold/SuccessThreshold override it.
+func WithThresholds(failure, success int) Option {
+	return func(i *Inspector) error {
+		if failure < 1 || success < 1 {
+			return errWrongThreshold
+		}
+
+		i.thresholds = thresholds{failure: failure, success: success}
+
+		return nil
+	}
+}
+
+// targetStreak - reported state of the target under thresholds.
+type targetStreak struct {
+	healthy   bool
+	err       error                // error of the flip to unhealthy
+	groupErrs map[ProbeGroup]error // of the flip to unhealthy
+	pending   int                  // consecutive results differing from the reported state
+}
+
+type targetStreaks struct {
+	mu      sync.Mutex
+	streaks map[string]*targetStreak // keyed by "scope/dest"
+}
+
+// thresholdsOf - effective thresholds of the target.
+func (i *Inspector) thresholdsOf(target HealthCheckTarget) thresholds {
+	th := thresholds{failure: 1, success: 1}
+
+	if i.thresholds.failure > 0 {
+		th = i.thresholds
+	}
+
+	if target.FailureThreshold > 0 {
+		th.failure = target.FailureThreshold
+	}
+
+	if target.SuccessThreshold > 0 {
+		th.success = target.SuccessThreshold
+	}
+
+	return th
+}
+
+// applyThresholds - reported results of the cycle: the raw result of a target flips its state
+// only after enough consecutive results, otherwise the state is kept.
+func (i *Inspector) applyThresholds(targets []HealthCheckTarget, results []TargetResult) {
+	i.streaks.mu.Lock()
+	defer i.streaks.mu.Unlock()
+
+	if i.streaks.streaks == nil {
+		i.streaks.streaks = make(map[string]*targetStreak)
+	}
+
+	alive := make(map[string]bool, len(targets))
+
+	for n, target := range targets {
+		key := targetKey(target.Service.Scope(), target.Service.Dest())
+		alive[key] = true
+
+		th := i.thresholdsOf(target)
+		tr := &results[n]
+
+		if tr.carried { // thresholds were applied when it was checked
+			continue
+		}
+
+		s, ok := i.streaks.streaks[key]
+		if !ok {
+			i.streaks.streaks[key] = &targetStreak{healthy: tr.Healthy(), err: tr.Err, groupErrs: tr.groupErrs}
+
+			continue
+		}
+
+		s.evaluate(th, tr)
+	}
+
+	for key := range i.streaks.streaks {
+		if !alive[key] {
+			delete(i.streaks.streaks, key)
+		}
+	}
+}
+
+func (s *targetStreak) evaluate(th thresholds, tr *TargetResult) {
+	if tr.Healthy() == s.healthy {
+		s.err, s.groupErrs, s.pending = tr.Err, tr.groupErrs, 0
+
+		return
+	}
+
+	s.pending++
+
+	need := th.success
+	if s.healthy {
+		need = th.failure
+	}
+
+	if s.pending >= need {
+		s.healthy, s.err, s.groupErrs, s.pending = tr.Healthy(), tr.Err, tr.groupErrs, 0
+
+		return
+	}
+
+	if s.healthy {
+		tr.Err, tr.groupErrs = nil, nil
+
+		return
+	}
+
+	recovering := func(err error) error {
+		return fmt.Errorf("%w: %d of %d successes: %w", errRecovering, s.pending, need, err)
+	}
+
+	tr.Err, tr.groupErrs = recovering(s.err), nil
+
+	if s.groupErrs != nil {
+		tr.groupErrs = make(map[ProbeGroup]error, len(s.groupErrs))
+
+		for g, err := range s.groupErrs {
+			if err != nil {
+				err = recovering(err)
+			}
+
+			tr.groupErrs[g] = err
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package healthz
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThresholds(t *testing.T) {
+	fail := errors.New("fail")
+
+	tests := []struct {
+		name    string
+		target  HealthCheckTarget
+		opts    []Option
+		results []error // raw results of consecutive cycles
+		healthy []bool  // reported state after every cycle
+	}{
+		{
+			name:    "test.1 no thresholds",
+			results: []error{nil, fail, nil},
+			healthy: []bool{true, false, true},
+		},
+		{
+			name:    "test.2 target failure threshold",
+			target:  HealthCheckTarget{FailureThreshold: 3},
+			results: []error{nil, fail, fail, nil, fail, fail, fail, nil},
+			healthy: []bool{true, true, true, true, true, true, false, true},
+		},
+		{
+			name:    "test.3 success threshold of option",
+			opts:    []Option{WithThresholds(1, 2)},
+			results: []error{fail, nil, fail, nil, nil, fail},
+			healthy: []bool{false, false, false, false, true, false},
+		},
+		{
+			name:    "test.4 target overrides option",
+			target:  HealthCheckTarget{FailureThreshold: 2, SuccessThreshold: 1},
+			opts:    []Option{WithThresholds(5, 5)},
+			results: []error{nil, fail, fail, nil},
+			healthy: []bool{true, true, false, true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &mockService{scope: "db", dest: "pg"}
+
+			target := tt.target
+			target.Service = svc
+			target.Groups = GroupReady
+
+			inspector := New(target)
+			for _, opt := range tt.opts {
+				assert.NoError(t, opt(inspector))
+			}
+
+			for n, err := range tt.results {
+				svc.healthErr = err
+				inspector.check(context.Background())
+
+				assert.Equal(t, tt.healthy[n], inspector.CheckGroup(GroupReady, true) == nil, "cycle %d", n)
+			}
+		})
+	}
+
+	assert.ErrorIs(t, WithThresholds(0, 1)(New()), errWrongThreshold)
+}
+
+func TestThresholds_recovering(t *testing.T) {
+	svc := &mockService{scope: "db", dest: "pg", healthErr: errors.New("fail")}
+
+	inspector := New(HealthCheckTarget{Service: svc, Groups: GroupReady, SuccessThreshold: 3})
+	inspector.check(context.Background())
+
+	svc.healthErr = nil
+	inspector.check(context.Background())
+
+	err := inspector.CheckGroup(GroupReady, true)
+	assert.ErrorIs(t, err, errRecovering)
+	assert.ErrorContains(t, err, "1 of 3 successes: fail")
+}
+
+func TestThresholds_metrics(t *testing.T) {
+	up := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test_thresholds_up"}, []string{"scope", "dest"})
+	scopeUp := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test_thresholds_scope_up"}, []string{"scope"})
+	outcomes := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_thresholds_outcomes"}, []string{"scope", "dest", "outcome"})
+
+	svc := &mockService{scope: "db", dest: "pg"}
+
+	inspector := New(HealthCheckTarget{Service: svc, Groups: GroupReady, FailureThreshold: 3})
+	assert.NoError(t, WithMetric(up)(inspector))
+	assert.NoError(t, WithScopeMetric(scopeUp)(inspector))
+	assert.NoError(t, WithOutcomeMetric(outcomes)(inspector))
+
+	inspector.check(context.Background())
+
+	svc.healthErr = errors.New("transient")
+
+	inspector.check(context.Background())
+	assert.NoError(t, inspector.CheckGroup(GroupReady, true))
+	assert.Equal(t, 1.0, testutil.ToFloat64(up.WithLabelValues("db", "pg")), "the gauge agrees with the probes")
+	assert.Equal(t, 1.0, testutil.ToFloat64(scopeUp.WithLabelValues("db")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(outcomes.WithLabelValues("db", "pg", OutcomeError)), "raw checks are counted")
+
+	inspector.check(context.Background())
+	inspector.check(context.Background())
+	assert.Error(t, inspector.CheckGroup(GroupReady, true))
+	assert.Equal(t, 0.0, testutil.ToFloat64(up.WithLabelValues("db", "pg")))
+	assert.Equal(t, 0.0, testutil.ToFloat64(scopeUp.WithLabelValues("db")))
+}
@@ -0,0 +1,71 @@
+package healthz
+
+import (
+	"context"
+	"errors"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var errMissTracer = errors.New("miss tracer")
+
+// Tracer - starts a span around the check of the target, the returned context is passed
+// to Health and end is called with the check result.
+type Tracer func(ctx context.Context, scope, dest string) (spanCtx context.Context, end func(err error))
+
+// ExemplarFunc - labels of the exemplar (e.g. {"trace_id": "..."}) taken from the span context,
+// nil if the context has no trace.
+type ExemplarFunc func(ctx context.Context) prometheus.Labels
+
+// WithTracer - traces every check, exemplar (could be nil) links failures counted by the
+// outcome metric to the traces of the failing checks.
+// Exemplars are exposed in OpenMetrics format only (promhttp.HandlerOpts.EnableOpenMetrics).
+func WithTracer(tracer Tracer, exemplar ExemplarFunc) Option {
+	return func(i *Inspector) error {
+		if tracer == nil {
+			return errMissTracer
+		}
+
+		i.tracer = tracer
+		i.exemplar = exemplar
+
+		return nil
+	}
+}
+
+// startSpan - context of the check span, end must be called with the result.
+func (i *Inspector) startSpan(ctx context.Context, target HealthCheckTarget) (context.Context, func(error), prometheus.Labels) {
+	if i.tracer == nil {
+		return ctx, func(error) {}, nil
+	}
+
+	ctx, end := i.tracer(ctx, target.Service.Scope(), target.Service.Dest())
+
+	var labels prometheus.Labels
+	if i.exemplar != nil {
+		labels = i.exemplar(ctx)
+	}
+
+	return ctx, end, labels
+}
+
+// addWithExemplar - increments the counter, attaching exemplar if there is one.
+func addWithExemplar(counter prometheus.Counter, exemplar prometheus.Labels) {
+	if adder, ok := counter.(prometheus.ExemplarAdder); ok && len(exemplar) > 0 {
+		adder.AddWithExemplar(1, exemplar)
+
+		return
+	}
+
+	counter.Inc()
+}
+
+func observeWithExemplar(observer prometheus.Observer, value float64, exemplar prometheus.Labels) {
+	if eo, ok := observer.(prometheus.ExemplarObserver); ok && len(exemplar) > 0 {
+		eo.ObserveWithExemplar(value, exemplar)
+
+		return
+	}
+
+	observer.Observe(value)
+}
@@ -0,0 +1,44 @@
+package healthz
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+)
+
+type traceIDKey struct{}
+
+func TestWithTracer(t *testing.T) {
+	assert.ErrorIs(t, WithTracer(nil, nil)(New()), errMissTracer)
+
+	var ended []error
+
+	tracer := func(ctx context.Context, scope, dest string) (context.Context, func(error)) {
+		return context.WithValue(ctx, traceIDKey{}, "trace-"+dest), func(err error) { ended = append(ended, err) }
+	}
+	exemplar := func(ctx context.Context) prometheus.Labels {
+		id, _ := ctx.Value(traceIDKey{}).(string)
+
+		return prometheus.Labels{"trace_id": id}
+	}
+
+	errFail := errors.New("fail")
+	outcomes := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_outcomes_total"}, []string{"scope", "dest", "outcome"})
+
+	inspector := New(HealthCheckTarget{Service: &mockService{scope: "db", dest: "pg", healthErr: errFail}, Groups: GroupReady})
+	assert.NoError(t, WithOutcomeMetric(outcomes)(inspector))
+	assert.NoError(t, WithTracer(tracer, exemplar)(inspector))
+
+	inspector.check(context.Background())
+
+	assert.Equal(t, []error{errFail}, ended)
+
+	var m dto.Metric
+	assert.NoError(t, outcomes.WithLabelValues("db", "pg", OutcomeError).(prometheus.Metric).Write(&m))
+	assert.Equal(t, 1.0, m.GetCounter().GetValue())
+	assert.Equal(t, "trace-pg", m.GetCounter().GetExemplar().GetLabel()[0].GetValue())
+}
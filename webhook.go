@@ -0,0 +1,361 @@
+package healthz
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Headers of the webhook requests: unix time of sending, id of the event
+// and HMAC-SHA256 signature "sha256=<hex>" of "<timestamp>.<id>.<body>".
+const (
+	HeaderWebhookTimestamp = "X-Healthz-Timestamp"
+	HeaderWebhookID        = "X-Healthz-Event-Id"
+	HeaderWebhookSignature = "X-Healthz-Signature"
+)
+
+const (
+	defWebhookTimeout   = 5 * time.Second
+	defWebhookTolerance = 5 * time.Minute
+	webhookQueueSize    = 64
+	maxWebhookBody      = 4 << 20
+)
+
+var (
+	errMissWebhook      = errors.New("webhook must have url and secret")
+	errWebhookSignature = errors.New("invalid webhook signature")
+	errWebhookStale     = errors.New("webhook timestamp out of tolerance")
+	errWebhookReplayed  = errors.New("webhook event already received")
+	errWebhookStatus    = errors.New("unexpected webhook response status")
+	errWrongPayload     = errors.New("wrong webhook payload")
+)
+
+// WebhookEvent - payload of the webhook sent after a check cycle with changes:
+// transitions of the cycle and results of all targets after it (of the changed ones if Delta).
+type WebhookEvent struct {
+	ID      string         `json:"id"`
+	Seq     uint64         `json:"seq"` // of the published result, see Snapshot.Seq
+	Time    time.Time      `json:"time"`
+	Changes []Change       `json:"changes"`
+	Targets []TargetResult `json:"targets"`
+	Delta   bool           `json:"delta,omitempty"` // see WebhookPayloadDelta
+}
+
+// WebhookPayload - targets of the webhook events, see WithWebhookPayload.
+type WebhookPayload int
+
+const (
+	WebhookPayloadFull  WebhookPayload = iota // results of all targets (default)
+	WebhookPayloadDelta                       // results of the changed targets only
+)
+
+func (p WebhookPayload) String() string {
+	switch p {
+	case WebhookPayloadFull:
+		return "full"
+	case WebhookPayloadDelta:
+		return "delta"
+	}
+
+	return fmt.Sprintf("WebhookPayload(%d)", int(p))
+}
+
+// SignWebhook - signature of the webhook body of the event id sent at timestamp (see HeaderWebhookSignature).
+func SignWebhook(secret []byte, id string, timestamp time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10) + "." + id + "."))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// SignRequest - sets the headers of the request with the body signed by the secret, verified by
+// WebhookReceiver and ExternalHandler. The id must be unique per request, a random one if empty.
+func SignRequest(req *http.Request, secret []byte, id string, body []byte) {
+	if id == "" {
+		id = newEventID()
+	}
+
+	now := time.Now()
+
+	req.Header.Set(HeaderWebhookTimestamp, strconv.FormatInt(now.Unix(), 10))
+	req.Header.Set(HeaderWebhookID, id)
+	req.Header.Set(HeaderWebhookSignature, SignWebhook(secret, id, now, body))
+}
+
+// webhook - notifier posting signed events to the url.
+type webhook struct {
+	url     string
+	secret  []byte
+	client  *http.Client
+	codec   Codec
+	payload WebhookPayload
+	queue   chan WebhookEvent
+}
+
+// WithWebhook - posts signed WebhookEvent (JSON) to the url after every check cycle with changes
+// of the targets health, client could be nil (default with 5s timeout). Events are sent in order
+// by the running inspector, ones not fitting the queue are dropped and logged (slog).
+func WithWebhook(url string, secret []byte, client *http.Client) Option {
+	return func(i *Inspector) error {
+		if url == "" || len(secret) == 0 {
+			return errMissWebhook
+		}
+
+		if client == nil {
+			client = &http.Client{Timeout: defWebhookTimeout}
+		}
+
+		i.webhook = &webhook{
+			url:    url,
+			secret: secret,
+			client: client,
+			codec:  JSONCodec,
+			queue:  make(chan WebhookEvent, webhookQueueSize),
+		}
+
+		return nil
+	}
+}
+
+// WithWebhookCodec - encodes webhook events by the codec instead of JSON, it must follow WithWebhook.
+// The receiving side must have the codec registered (see RegisterCodec).
+func WithWebhookCodec(codec Codec) Option {
+	return func(i *Inspector) error {
+		if i.webhook == nil {
+			return errMissWebhook
+		}
+
+		if codec == nil || codec.ContentType() == "" {
+			return errWrongCodec
+		}
+
+		i.webhook.codec = codec
+
+		return nil
+	}
+}
+
+// WithWebhookPayload - WebhookPayloadDelta sends results of the changed targets only instead of all,
+// reducing payload size for fleets with thousands of targets and frequent cycles, receivers keep
+// the state by Seq. It must follow WithWebhook.
+func WithWebhookPayload(payload WebhookPayload) Option {
+	return func(i *Inspector) error {
+		if i.webhook == nil {
+			return errMissWebhook
+		}
+
+		if payload != WebhookPayloadFull && payload != WebhookPayloadDelta {
+			return fmt.Errorf("%w: %d", errWrongPayload, payload)
+		}
+
+		i.webhook.payload = payload
+
+		return nil
+	}
+}
+
+// notifyWebhook - queues the event of the cycle changes.
+func (i *Inspector) notifyWebhook(changes []Change, result *healthResult) {
+	if i.webhook == nil || len(changes) == 0 {
+		return
+	}
+
+	delta := i.webhook.payload == WebhookPayloadDelta
+	changed := make(map[string]bool, len(changes))
+
+	redacted := make([]Change, len(changes))
+	for n, c := range changes {
+		c.Err = i.redact(c.Err)
+		redacted[n] = c
+		changed[targetKey(c.Scope, c.Dest)] = true
+	}
+
+	targets := make([]TargetResult, 0, len(result.targets))
+	for _, tr := range result.targets {
+		if delta && !changed[targetKey(tr.Scope, tr.Dest)] {
+			continue
+		}
+
+		tr.Err, tr.Degraded = i.redact(tr.Err), i.redact(tr.Degraded)
+		targets = append(targets, tr)
+	}
+
+	event := WebhookEvent{ID: newEventID(), Seq: result.seq, Time: result.checkedAt, Changes: redacted, Targets: targets, Delta: delta}
+
+	select {
+	case i.webhook.queue <- event:
+	default:
+		i.log().Warn("healthz: webhook queue is full, event dropped", "id", event.ID)
+	}
+}
+
+// run - sends queued events until ctx is done or stop is closed.
+func (w *webhook) run(ctx context.Context, stop <-chan struct{}, logger *slog.Logger) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-stop:
+			return
+		case event := <-w.queue:
+			if err := w.send(ctx, event); err != nil {
+				logger.Warn("healthz: webhook not delivered", "id", event.ID, "error", err)
+			}
+		}
+	}
+}
+
+func (w *webhook) send(ctx context.Context, event WebhookEvent) error {
+	body, err := w.codec.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", w.codec.ContentType())
+	SignRequest(req, w.secret, event.ID, body)
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxWebhookBody))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%w: %d", errWebhookStatus, resp.StatusCode)
+	}
+
+	return nil
+}
+
+func newEventID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
+
+// WebhookReceiver - verifies webhooks of other services: signature, timestamp within the tolerance
+// and event ids not received within the tolerance (replay protection).
+type WebhookReceiver struct {
+	secret    []byte
+	tolerance time.Duration
+
+	mu   sync.Mutex
+	seen map[string]time.Time // ids of received events by their timestamps
+}
+
+// NewWebhookReceiver - receiver of webhooks signed by the secret, tolerance is 5m if not positive.
+func NewWebhookReceiver(secret []byte, tolerance time.Duration) (*WebhookReceiver, error) {
+	if len(secret) == 0 {
+		return nil, errMissWebhook
+	}
+
+	if tolerance <= 0 {
+		tolerance = defWebhookTolerance
+	}
+
+	return &WebhookReceiver{secret: secret, tolerance: tolerance, seen: make(map[string]time.Time)}, nil
+}
+
+// Verify - checks headers of the request with the body, the event id is remembered on success.
+func (wr *WebhookReceiver) Verify(header http.Header, body []byte) error {
+	unix, err := strconv.ParseInt(header.Get(HeaderWebhookTimestamp), 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: %q", errWebhookStale, header.Get(HeaderWebhookTimestamp))
+	}
+
+	timestamp := time.Unix(unix, 0)
+	id := header.Get(HeaderWebhookID)
+
+	expected := SignWebhook(wr.secret, id, timestamp, body)
+	if !hmac.Equal([]byte(expected), []byte(header.Get(HeaderWebhookSignature))) {
+		return errWebhookSignature
+	}
+
+	now := time.Now()
+
+	if age := now.Sub(timestamp); age > wr.tolerance || age < -wr.tolerance {
+		return fmt.Errorf("%w: %s", errWebhookStale, age.Round(time.Second))
+	}
+
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+
+	for seenID, at := range wr.seen {
+		if now.Sub(at) > wr.tolerance {
+			delete(wr.seen, seenID)
+		}
+	}
+
+	if _, ok := wr.seen[id]; ok {
+		return fmt.Errorf("%w: %s", errWebhookReplayed, id)
+	}
+
+	wr.seen[id] = timestamp
+
+	return nil
+}
+
+// Handler - verifies webhooks and passes their events to handle, they are decoded by the codec
+// of the Content-Type (see RegisterCodec). Unverified ones are rejected with 401, malformed with 400,
+// of unknown content type with 415.
+func (wr *WebhookReceiver) Handler(handle func(ctx context.Context, event WebhookEvent)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBody))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+
+			return
+		}
+
+		if err := wr.Verify(r.Header, body); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+
+			return
+		}
+
+		codec, ok := LookupCodec(r.Header.Get("Content-Type"))
+		if !ok {
+			http.Error(w, http.StatusText(http.StatusUnsupportedMediaType), http.StatusUnsupportedMediaType)
+
+			return
+		}
+
+		var event WebhookEvent
+
+		if err := codec.Unmarshal(body, &event); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+
+			return
+		}
+
+		handle(r.Context(), event)
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
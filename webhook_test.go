@@ -0,0 +1,181 @@
+package healthz
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhook(t *testing.T) {
+	secret := []byte("s3cret")
+
+	receiver, err := NewWebhookReceiver(secret, 0)
+	assert.NoError(t, err)
+
+	events := make(chan WebhookEvent, 1)
+	srv := httptest.NewServer(receiver.Handler(func(_ context.Context, event WebhookEvent) { events <- event }))
+	defer srv.Close()
+
+	svc := &mockService{scope: "db", dest: "pg"}
+	inspector := New(HealthCheckTarget{Service: svc, Groups: GroupReady})
+	assert.NoError(t, WithWebhook(srv.URL, secret, nil)(inspector))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go inspector.webhook.run(ctx, nil, slog.Default())
+
+	inspector.check(ctx)
+	svc.healthErr = errors.New("fail")
+	inspector.check(ctx)
+
+	select {
+	case event := <-events:
+		assert.NotEmpty(t, event.ID)
+		assert.Equal(t, uint64(2), event.Seq)
+		if assert.Len(t, event.Changes, 1) {
+			assert.Equal(t, "pg", event.Changes[0].Dest)
+			assert.False(t, event.Changes[0].Healthy)
+			assert.EqualError(t, event.Changes[0].Err, "fail")
+		}
+		assert.Len(t, event.Targets, 1)
+	case <-time.After(testTimeout):
+		t.Fatal("webhook is not received")
+	}
+
+	assert.ErrorIs(t, WithWebhookCodec(JSONCodec)(New()), errMissWebhook, "must follow WithWebhook")
+	assert.ErrorIs(t, WithWebhookCodec(nil)(inspector), errWrongCodec)
+	assert.ErrorIs(t, WithWebhook("", secret, nil)(New()), errMissWebhook)
+	assert.ErrorIs(t, WithWebhook(srv.URL, nil, nil)(New()), errMissWebhook)
+}
+
+func TestWithWebhookPayload(t *testing.T) {
+	tests := []struct {
+		name        string
+		payload     WebhookPayload
+		wantTargets []string
+	}{
+		{name: "test.1 full", payload: WebhookPayloadFull, wantTargets: []string{"pg", "redis"}},
+		{name: "test.2 delta", payload: WebhookPayloadDelta, wantTargets: []string{"pg"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			secret := []byte("s3cret")
+
+			receiver, err := NewWebhookReceiver(secret, 0)
+			assert.NoError(t, err)
+
+			events := make(chan WebhookEvent, 1)
+			srv := httptest.NewServer(receiver.Handler(func(_ context.Context, event WebhookEvent) { events <- event }))
+			defer srv.Close()
+
+			svc := &mockService{scope: "db", dest: "pg"}
+			inspector := New(
+				HealthCheckTarget{Service: svc, Groups: GroupReady},
+				HealthCheckTarget{Service: &mockService{scope: "cache", dest: "redis"}, Groups: GroupReady},
+			)
+			assert.NoError(t, WithWebhook(srv.URL, secret, nil)(inspector))
+			assert.NoError(t, WithWebhookPayload(tt.payload)(inspector))
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			go inspector.webhook.run(ctx, nil, slog.Default())
+
+			inspector.check(ctx)
+			svc.healthErr = errors.New("fail")
+			inspector.check(ctx)
+
+			select {
+			case event := <-events:
+				var dests []string
+				for _, tr := range event.Targets {
+					dests = append(dests, tr.Dest)
+				}
+
+				assert.ElementsMatch(t, tt.wantTargets, dests)
+				assert.Equal(t, tt.payload == WebhookPayloadDelta, event.Delta)
+			case <-time.After(testTimeout):
+				t.Fatal("webhook is not received")
+			}
+		})
+	}
+
+	assert.ErrorIs(t, WithWebhookPayload(WebhookPayloadDelta)(New()), errMissWebhook, "must follow WithWebhook")
+
+	inspector := New()
+	assert.NoError(t, WithWebhook("http://localhost", []byte("s3cret"), nil)(inspector))
+	assert.ErrorIs(t, WithWebhookPayload(WebhookPayload(5))(inspector), errWrongPayload)
+}
+
+func TestWebhookReceiver_Verify(t *testing.T) {
+	secret := []byte("s3cret")
+	body := []byte(`{"id":"1"}`)
+	now := time.Now()
+
+	headers := func(id string, ts time.Time, sig string) http.Header {
+		h := http.Header{}
+		h.Set(HeaderWebhookID, id)
+		h.Set(HeaderWebhookTimestamp, strconv.FormatInt(ts.Unix(), 10))
+		h.Set(HeaderWebhookSignature, sig)
+
+		return h
+	}
+
+	receiver, err := NewWebhookReceiver(secret, time.Minute)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		header  http.Header
+		body    []byte
+		wantErr error
+	}{
+		{name: "test.1 ok", header: headers("a", now, SignWebhook(secret, "a", now, body)), body: body},
+		{name: "test.2 replayed", header: headers("a", now, SignWebhook(secret, "a", now, body)), body: body, wantErr: errWebhookReplayed},
+		{name: "test.3 tampered body", header: headers("b", now, SignWebhook(secret, "b", now, body)), body: []byte(`{"id":"2"}`), wantErr: errWebhookSignature},
+		{name: "test.4 other secret", header: headers("c", now, SignWebhook([]byte("other"), "c", now, body)), body: body, wantErr: errWebhookSignature},
+		{
+			name:    "test.5 stale",
+			header:  headers("d", now.Add(-2*time.Minute), SignWebhook(secret, "d", now.Add(-2*time.Minute), body)),
+			body:    body,
+			wantErr: errWebhookStale,
+		},
+		{name: "test.6 replayed with other id", header: headers("e", now, SignWebhook(secret, "a", now, body)), body: body, wantErr: errWebhookSignature},
+		{name: "test.7 missing timestamp", header: http.Header{}, body: body, wantErr: errWebhookStale},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := receiver.Verify(tt.header, tt.body)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+		})
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/hook", bytes.NewReader(body))
+	receiver.Handler(func(context.Context, WebhookEvent) { t.Fatal("unverified event is handled") }).ServeHTTP(w, r)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest(http.MethodPost, "/hook", bytes.NewReader(body))
+	r.Header = headers("f", now, SignWebhook(secret, "f", now, body))
+	r.Header.Set("Content-Type", "application/xml")
+	receiver.Handler(func(context.Context, WebhookEvent) { t.Fatal("unknown content type is handled") }).ServeHTTP(w, r)
+	assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+
+	_, err = NewWebhookReceiver(nil, 0)
+	assert.ErrorIs(t, err, errMissWebhook)
+}
@@ -0,0 +1,204 @@
+package healthz
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	defWorkerInterval = time.Second
+	workerTimeout     = 5 * time.Second
+)
+
+var (
+	errWrongSocket   = errors.New("incorrect worker socket")
+	errWorkerPush    = errors.New("unexpected worker push response status")
+	errMissWorkerDst = errors.New("worker push must have scope and dest")
+)
+
+// ServeWorkers - receives health of worker subprocesses (see PushToParent) over the unix socket at path
+// until ctx is done. Every worker is an external target of the inspector (WithExternalTarget), so it's
+// unhealthy until the first push and when pushes stop for its ttl. Access is restricted by the socket
+// file mode 0600 instead of a secret, a stale socket at path is replaced.
+func (i *Inspector) ServeWorkers(ctx context.Context, path string) error {
+	if path == "" {
+		return errWrongSocket
+	}
+
+	if fi, err := os.Lstat(path); err == nil {
+		if fi.Mode()&os.ModeSocket == 0 {
+			return fmt.Errorf("%w: %s isn't a socket", errWrongSocket, path)
+		}
+
+		_ = os.Remove(path)
+	}
+
+	ln, err := listenPrivate(path)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(path)
+
+	srv := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost {
+				w.Header().Set("Allow", http.MethodPost)
+				http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+
+				return
+			}
+
+			i.receiveExternal(w, r)
+		}),
+		ReadHeaderTimeout: workerTimeout,
+	}
+
+	go func() {
+		<-ctx.Done()
+		_ = srv.Close()
+	}()
+
+	if err := srv.Serve(ln); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
+
+// listenPrivate - listens the unix socket at path with file mode 0600. The socket is bound in a new
+// directory of mode 0700 and moved to path once its mode is set, so nobody could connect in between.
+func listenPrivate(path string) (*net.UnixListener, error) {
+	dir, err := os.MkdirTemp(filepath.Dir(path), ".healthz-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	bound := filepath.Join(dir, "sock")
+
+	ln, err := net.ListenUnix("unix", &net.UnixAddr{Name: bound, Net: "unix"})
+	if err != nil {
+		return nil, err
+	}
+
+	ln.SetUnlinkOnClose(false) // the socket is moved, ServeWorkers removes it
+
+	if err := os.Chmod(bound, 0o600); err != nil {
+		_ = ln.Close()
+
+		return nil, fmt.Errorf("restrict socket mode: %w", err)
+	}
+
+	if err := os.Rename(bound, path); err != nil {
+		_ = ln.Close()
+
+		return nil, err
+	}
+
+	return ln, nil
+}
+
+// WorkerPush - how a worker process reports its health to the parent.
+type WorkerPush struct {
+	Socket         string // unix socket of the parent ServeWorkers
+	Scope, Dest    string // external target of the worker at the parent
+	Group          ProbeGroup
+	NeedAllHealthy bool
+	Interval       time.Duration // 1s if not positive, must be shorter than ttl of the target
+}
+
+// PushToParent - pushes the verdict of the worker inspector group (GroupReady if zero) to the parent
+// (see ServeWorkers) every interval until ctx is done. Failed pushes are logged (slog), e.g. until the parent listens.
+func (i *Inspector) PushToParent(ctx context.Context, push WorkerPush) error {
+	if push.Socket == "" {
+		return errWrongSocket
+	}
+
+	if push.Scope == "" || push.Dest == "" {
+		return errMissWorkerDst
+	}
+
+	if push.Group == 0 {
+		push.Group = GroupReady
+	}
+
+	if err := push.Group.validate(); err != nil {
+		return err
+	}
+
+	interval := push.Interval
+	if interval <= 0 {
+		interval = defWorkerInterval
+	}
+
+	client := &http.Client{
+		Timeout: workerTimeout,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+
+				return d.DialContext(ctx, "unix", push.Socket)
+			},
+		},
+	}
+	defer client.CloseIdleConnections()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		status := ExternalStatus{Scope: push.Scope, Dest: push.Dest, Healthy: true}
+
+		if err := i.CheckGroup(push.Group, push.NeedAllHealthy); err != nil {
+			status.Healthy, status.Error = false, i.redact(err).Error()
+		}
+
+		if err := pushStatus(ctx, client, status); err != nil && ctx.Err() == nil {
+			i.log().Warn("healthz: worker health not pushed", "socket", push.Socket, "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func pushStatus(ctx context.Context, client *http.Client, status ExternalStatus) error {
+	body, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+
+	// host is ignored by the unix dialer
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "http://healthz/", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("%w: %d", errWorkerPush, resp.StatusCode)
+	}
+
+	return nil
+}
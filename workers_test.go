@@ -0,0 +1,72 @@
+package healthz
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkers(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "healthz.sock")
+
+	parent := New()
+	assert.NoError(t, WithExternalTarget("worker", "w1", GroupReady, time.Second)(parent))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	served := make(chan error, 1)
+	go func() { served <- parent.ServeWorkers(ctx, socket) }()
+
+	svc := &mockService{scope: "queue", dest: "amqp"}
+	worker := New(HealthCheckTarget{Service: svc, Groups: GroupReady})
+	worker.check(ctx)
+
+	pushed := make(chan error, 1)
+	go func() {
+		pushed <- worker.PushToParent(ctx, WorkerPush{Socket: socket, Scope: "worker", Dest: "w1", Interval: 10 * time.Millisecond})
+	}()
+
+	parentReady := func() bool {
+		parent.check(ctx)
+
+		return parent.CheckGroup(GroupReady, true) == nil
+	}
+
+	assert.Eventually(t, parentReady, time.Second, 5*time.Millisecond)
+
+	svc.healthErr = errors.New("broker is down")
+	worker.check(ctx)
+
+	assert.Eventually(t, func() bool { return !parentReady() }, time.Second, 5*time.Millisecond)
+	assert.ErrorContains(t, parent.CheckGroup(GroupReady, true), "broker is down")
+
+	fi, err := os.Stat(socket)
+	if assert.NoError(t, err) {
+		assert.Equal(t, os.FileMode(0o600), fi.Mode().Perm())
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(socket))
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1, "the socket is bound in a temporary private directory")
+
+	cancel()
+	assert.NoError(t, <-served)
+	assert.NoError(t, <-pushed)
+
+	_, err = os.Stat(socket)
+	assert.ErrorIs(t, err, os.ErrNotExist, "the socket is removed")
+
+	assert.ErrorIs(t, parent.ServeWorkers(context.Background(), ""), errWrongSocket)
+
+	file := filepath.Join(t.TempDir(), "file")
+	assert.NoError(t, os.WriteFile(file, nil, 0o600))
+	assert.ErrorIs(t, parent.ServeWorkers(context.Background(), file), errWrongSocket, "not a socket isn't removed")
+
+	assert.ErrorIs(t, worker.PushToParent(context.Background(), WorkerPush{Socket: socket}), errMissWorkerDst)
+}